@@ -3,6 +3,8 @@
 
 package goabu
 
+import "github.com/abu-lang/goabu/tracing"
+
 type Agent interface {
 	Start() error
 	Join() error
@@ -12,3 +14,29 @@ type Agent interface {
 	IsRunning() bool
 	SetLogLevel(int)
 }
+
+// IdentifiedAgent is implemented by the Agents having an identifier, the Executer uses it for
+// recording on which node the updates originated (see [Update]).
+type IdentifiedAgent interface {
+	Agent
+	// ID returns the identifier of the Agent.
+	ID() string
+}
+
+// TransactionAgent is implemented by the Agents identifying the transactions they perform, the Executer
+// uses these identifiers in the Transaction field of the received updates (see [Update]) so that they
+// match the logs and the spans of the Agents.
+type TransactionAgent interface {
+	Agent
+	// ReceivedTransaction returns the identifier of the transaction whose commands are exchanged over
+	// commands, a channel just provided by ReceivedActions. It is called at most once for each channel.
+	ReceivedTransaction(commands chan string) string
+}
+
+// TracingAgent is implemented by the Agents able to record the spans of the transactions they perform,
+// the Executer uses it for making these spans children of the spans of the operations that caused them.
+type TracingAgent interface {
+	Agent
+	// ForAllTraced is like ForAll but the transaction is caused by the span identified by parent.
+	ForAllTraced(payload []byte, parent tracing.SpanContext) error
+}
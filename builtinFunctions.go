@@ -3,23 +3,100 @@
 
 package goabu
 
-import "github.com/hyperjumptech/grule-rule-engine/ast"
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
 
 // builtinFunctions provides the built-in functions usable in GoAbU's rules.
 // The built-in functions consist in the exported methods of the builtInFunctions
 // type including the built-in functions provided by Grule.
 // The exported methods can be directly called in the rules (e.g. "AbsInt(-1)").
+// Like for the registered functions, integer arguments are accepted for the float64 parameters
+// and a returned error makes the evaluation fail.
 type builtinFunctions struct {
 	*ast.BuiltInFunctions
+	now *evaluationTime
 }
 
-func makeBuiltinFunctions(kb *ast.KnowledgeBase, wm *ast.WorkingMemory, dc ast.IDataContext) builtinFunctions {
+func makeBuiltinFunctions(kb *ast.KnowledgeBase, wm *ast.WorkingMemory, dc ast.IDataContext, now *evaluationTime) builtinFunctions {
 	return builtinFunctions{
 		BuiltInFunctions: &ast.BuiltInFunctions{
 			Knowledge:     kb,
 			WorkingMemory: wm,
 			DataContext:   dc,
 		},
+		now: now,
+	}
+}
+
+// withEvaluationTime returns a data context like dataContext, which must have been created by
+// newEmptyGruleStructures, whose Now built-in function returns the time of now.
+func withEvaluationTime(dataContext ast.IDataContext, now *evaluationTime) ast.IDataContext {
+	defunc := dataContext.Get("DEFUNC").(functionsNode)
+	builtins := defunc.builtins.Interface().(builtinFunctions)
+	builtins.now = now
+	defunc.builtins = reflect.ValueOf(builtins)
+	return evaluationContext{IDataContext: dataContext, defunc: defunc}
+}
+
+// evaluationContext is a data context sharing everything but its built-in functions with the one it wraps.
+type evaluationContext struct {
+	ast.IDataContext
+	defunc model.ValueNode
+}
+
+// Get returns the built-in functions of c for "DEFUNC" and the value of the wrapped data context otherwise.
+func (c evaluationContext) Get(key string) model.ValueNode {
+	if key == "DEFUNC" {
+		return c.defunc
+	}
+	return c.IDataContext.Get(key)
+}
+
+// evaluationTime is the source of the time returned by the Now built-in function: while it is fixed
+// Now always returns the same instant, so that the tasks evaluated together agree on the current time.
+type evaluationTime struct {
+	clock   Clock
+	fixed   int
+	instant time.Time
+	lock    sync.Mutex
+}
+
+func newEvaluationTime(clock Clock) *evaluationTime {
+	return &evaluationTime{clock: clock}
+}
+
+// now returns the fixed instant, if any, or the current time of the Clock.
+func (t *evaluationTime) now() time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.fixed > 0 {
+		return t.instant
+	}
+	return t.clock.Now()
+}
+
+// fix fixes the current time of the Clock until the returned function is called.
+// Nested calls keep the instant fixed by the outermost one.
+func (t *evaluationTime) fix() (release func()) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.fixed == 0 {
+		t.instant = t.clock.Now()
+	}
+	t.fixed++
+	return func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		t.fixed--
 	}
 }
 
@@ -30,3 +107,108 @@ func (f builtinFunctions) AbsInt(arg int64) int64 {
 	}
 	return arg
 }
+
+// Min returns the smallest of its arguments.
+func (f builtinFunctions) Min(x float64, others ...float64) float64 {
+	for _, o := range others {
+		x = math.Min(x, o)
+	}
+	return x
+}
+
+// Max returns the largest of its arguments.
+func (f builtinFunctions) Max(x float64, others ...float64) float64 {
+	for _, o := range others {
+		x = math.Max(x, o)
+	}
+	return x
+}
+
+// MinInt returns the smallest of its arguments.
+func (f builtinFunctions) MinInt(x int64, others ...int64) int64 {
+	for _, o := range others {
+		x = min(x, o)
+	}
+	return x
+}
+
+// MaxInt returns the largest of its arguments.
+func (f builtinFunctions) MaxInt(x int64, others ...int64) int64 {
+	for _, o := range others {
+		x = max(x, o)
+	}
+	return x
+}
+
+// Clamp returns x limited to the interval [low, high], it fails if low is greater than high.
+func (f builtinFunctions) Clamp(x, low, high float64) (float64, error) {
+	if low > high {
+		return 0, fmt.Errorf("empty interval [%v, %v]", low, high)
+	}
+	return math.Max(low, math.Min(x, high)), nil
+}
+
+// ClampInt returns x limited to the interval [low, high], it fails if low is greater than high.
+func (f builtinFunctions) ClampInt(x, low, high int64) (int64, error) {
+	if low > high {
+		return 0, fmt.Errorf("empty interval [%d, %d]", low, high)
+	}
+	return max(low, min(x, high)), nil
+}
+
+// RoundTo returns x rounded to the given number of decimal digits, rounding half away from zero.
+// A negative number of digits rounds to a multiple of a power of ten (e.g. RoundTo(1234, -2) is 1200).
+func (f builtinFunctions) RoundTo(x float64, digits int64) float64 {
+	scale := math.Pow10(int(digits))
+	return math.Round(x*scale) / scale
+}
+
+// ToInt returns x truncated toward zero, it fails if the result is not representable as an int64.
+func (f builtinFunctions) ToInt(x float64) (int64, error) {
+	t := math.Trunc(x)
+	if math.IsNaN(t) || t < math.MinInt64 || t >= math.MaxInt64 {
+		return 0, fmt.Errorf("%v is out of the range of the integers", x)
+	}
+	return int64(t), nil
+}
+
+// ToFloat returns x as a float64.
+func (f builtinFunctions) ToFloat(x int64) float64 {
+	return float64(x)
+}
+
+// Format formats its arguments according to format like [fmt.Sprintf] does.
+func (f builtinFunctions) Format(format string, args ...any) string {
+	return fmt.Sprintf(format, args...)
+}
+
+// HasPrefix reports whether s begins with prefix.
+func (f builtinFunctions) HasPrefix(s, prefix string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// HasSuffix reports whether s ends with suffix.
+func (f builtinFunctions) HasSuffix(s, suffix string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+// Now returns the current time according to the Clock of the Executer. All the calls performed while
+// evaluating the tasks of the rules activated together, or the tasks received in the same transaction,
+// return the same instant: in particular the remote tasks are evaluated with the time of the receiver.
+func (f builtinFunctions) Now() time.Time {
+	return f.now.now()
+}
+
+// Since returns the seconds elapsed from t to Now().
+func (f builtinFunctions) Since(t time.Time) float64 {
+	return f.Now().Sub(t).Seconds()
+}
+
+// AddDuration returns t plus the duration d, which is written like "1h30m" or "-1.5s" (see [time.ParseDuration]).
+func (f builtinFunctions) AddDuration(t time.Time, d string) (time.Time, error) {
+	duration, err := time.ParseDuration(d)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(duration), nil
+}
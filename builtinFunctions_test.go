@@ -0,0 +1,152 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/stringset"
+)
+
+func TestStandardLibrary(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mem := memory.MakeResources()
+	mem.Integer["i"] = 0
+	mem.Float["f"] = 0
+	mem.Bool["b"] = false
+	mem.Text["s"] = ""
+	mem.Time["t"] = time.Time{}
+	e, err := NewExecuterAdvanced(mem, nil, MakeMockAgent(), config.TestsLogConfig,
+		&ExecuterConfig{Clock: NewManualClock(start)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	tests := []struct {
+		input    string
+		resource string
+		expected any
+	}{
+		{"f = Min(2.5, 1, 3.0)", "f", 1.0},
+		{"f = Max(2.5, 1, 3.0)", "f", 3.0},
+		{"f = Min(-1.5)", "f", -1.5},
+		{"i = MinInt(4, -2, 7)", "i", int64(-2)},
+		{"i = MaxInt(4, -2, 7)", "i", int64(7)},
+		{"f = Clamp(12.5, 0, 10)", "f", 10.0},
+		{"f = Clamp(-3, 0.5, 10)", "f", 0.5},
+		{"i = ClampInt(5, 0, 10)", "i", int64(5)},
+		{"i = ClampInt(-5, 0, 10)", "i", int64(0)},
+		{"f = Round(2.5)", "f", 3.0},
+		{"f = RoundTo(3.14159, 2)", "f", 3.14},
+		{"f = RoundTo(-2.345, 2)", "f", -2.35},
+		{"f = RoundTo(1234, -2)", "f", 1200.0},
+		{"i = ToInt(-7.9)", "i", int64(-7)},
+		{"f = ToFloat(3) / 2", "f", 1.5},
+		{`s = Format("%s is %d (%.1f%%)", "level", 42, 99.5)`, "s", "level is 42 (99.5%)"},
+		{`s = Format("%v", true)`, "s", "true"},
+		{`b = Contains("goabu", "abu")`, "b", true},
+		{`b = HasPrefix("goabu", "abu")`, "b", false},
+		{`b = HasSuffix("goabu", "abu")`, "b", true},
+		{"t = Now()", "t", start},
+		{`t = AddDuration(Now(), "1h30m")`, "t", start.Add(90 * time.Minute)},
+		{`t = AddDuration(Now(), "-2s")`, "t", start.Add(-2 * time.Second)},
+		{`f = Since(AddDuration(Now(), "-1m"))`, "f", 60.0},
+	}
+	for _, test := range tests {
+		err := e.Input(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.input, err)
+			continue
+		}
+		mem, _ := e.TakeState()
+		if v := e.valuesIn(mem, stringset.Make(test.resource))[test.resource]; v != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.input, test.expected, v)
+		}
+	}
+	for _, input := range []string{
+		"f = Clamp(1, 2, 1)",
+		"i = ClampInt(1, 2, 1)",
+		"i = ToInt(1e30)",
+		`t = AddDuration(Now(), "one hour")`,
+		"f = Min()",
+		`f = Min("one")`,
+	} {
+		if e.Input(input) == nil {
+			t.Errorf("%s should fail", input)
+		}
+	}
+}
+
+func TestDeterministicNow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &steppingClock{ManualClock: NewManualClock(start)}
+	mem := memory.MakeResources()
+	mem.Integer["x"] = 0
+	mem.Time["first"] = time.Time{}
+	mem.Time["second"] = time.Time{}
+	mem.Time["received"] = time.Time{}
+	rules := []string{
+		"rule stamp on x for Now() == Now() do first = Now(), second = Now()",
+		"rule remote on x for all ext.received < Now() do ext.received = Now()",
+	}
+	e, err := NewExecuterAdvanced(mem, rules, MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{Clock: clock})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	err = e.Input("x = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for e.DoIfStable(func() {}) {
+	}
+	for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+		e.Exec()
+	}
+	mem, _ = e.TakeState()
+	if mem.Time["first"].IsZero() || mem.Time["first"] != mem.Time["second"] {
+		t.Error("the tasks should be evaluated at the same instant, got", mem.Time["first"], mem.Time["second"])
+	}
+	if mem.Time["received"].IsZero() || mem.Time["received"] == mem.Time["first"] {
+		t.Error("the received task should be evaluated with the time of the receiver, got", mem.Time["received"])
+	}
+	// overlapping evaluations fix their own instants
+	first := newEvaluationTime(clock)
+	defer first.fix()()
+	second := newEvaluationTime(clock)
+	defer second.fix()()
+	for i := 0; i < 2; i++ {
+		a, err := withEvaluationTime(e.dataContext, first).Get("DEFUNC").CallFunction("Now")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		b, err := withEvaluationTime(e.dataContext, second).Get("DEFUNC").CallFunction("Now")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !a.Interface().(time.Time).Before(b.Interface().(time.Time)) {
+			t.Error("the evaluations should have distinct instants, got", a, b)
+		}
+		if !first.now().Equal(a.Interface().(time.Time)) || !second.now().Equal(b.Interface().(time.Time)) {
+			t.Error("the instants should stay fixed, got", a, b)
+		}
+	}
+}
+
+// steppingClock is a ManualClock whose time advances by a millisecond whenever it is read.
+type steppingClock struct {
+	*ManualClock
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.Advance(time.Millisecond)
+	return c.ManualClock.Now()
+}
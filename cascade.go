@@ -0,0 +1,114 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abu-lang/goabu/ecarule"
+
+	"go.uber.org/zap"
+)
+
+// DefaultCascadeBudget is a cascade budget suitable for most nodes, which can opt in to it by means of
+// the CascadeBudget field of ExecuterConfig.
+const DefaultCascadeBudget = 1000
+
+// CascadeError is sent over the channel returned by Errors when the rules activated by an update
+// produce no updates because the causal chain of the update exhausted the cascade budget
+// (see the CascadeBudget field of ExecuterConfig).
+type CascadeError struct {
+	// Update is the update whose consequences were dropped.
+	Update Update
+	// Rules contains the sorted names of the rules activated by Update.
+	Rules []string
+	// Budget is the cascade budget of the Executer.
+	Budget int
+}
+
+// Error returns a description of the error.
+func (e *CascadeError) Error() string {
+	return fmt.Sprintf("cascade budget %d exhausted by %v: not activating rules %s",
+		e.Budget, e.Update, strings.Join(e.Rules, ", "))
+}
+
+// CycleError is returned by AddRules and ReplaceRule when the Executer rejects the rules that could
+// activate each other forever (see the RejectCycles field of ExecuterConfig).
+type CycleError struct {
+	// Cycles contains the cycles formed by the new rules, as returned by [ecarule.RuleDict.Cycles].
+	Cycles [][]string
+}
+
+// Error returns a description of the error.
+func (e *CycleError) Error() string {
+	cycles := make([]string, 0, len(e.Cycles))
+	for _, c := range e.Cycles {
+		cycles = append(cycles, "["+strings.Join(c, ", ")+"]")
+	}
+	return "rules could activate each other forever: " + strings.Join(cycles, ", ")
+}
+
+// checkCycles looks for the cycles of rules that the rules in added would form once added to the
+// node's knowledge base, replacing the rules with the same name. Depending on m.rejectCycles it
+// returns a [*CycleError] or it logs a warning for each cycle.
+// It should be called while holding m.lockRules.
+func (m *Executer) checkCycles(added []ecarule.Rule) error {
+	rules := m.rulesAux()
+	names := make(map[string]bool)
+	for i := range added {
+		rules.Insert(&added[i])
+		names[added[i].Name] = true
+	}
+	var cycles [][]string
+	for _, c := range rules.Cycles() {
+		for _, name := range c {
+			if names[name] {
+				cycles = append(cycles, c)
+				break
+			}
+		}
+	}
+	if len(cycles) == 0 {
+		return nil
+	}
+	if m.rejectCycles {
+		return &CycleError{Cycles: cycles}
+	}
+	for _, c := range cycles {
+		m.logger.Warn(fmt.Sprintf("Rules %v could activate each other forever", c),
+			zap.String("act", "add_rule"),
+			zap.Strings("cycle", c))
+	}
+	return nil
+}
+
+// rulesAux returns the rules of the node's knowledge base. It should be called while holding m.lockRules.
+func (m *Executer) rulesAux() ecarule.RuleDict {
+	res := ecarule.MakeRuleDict()
+	for _, d := range m.ruleLibrary {
+		res.Add(d)
+	}
+	return res
+}
+
+// exhaustedCascade reports whether the consequences of cause exceed the cascade budget of m.
+// In that case, if some rules are activated, a [*CascadeError] is reported.
+func (m *Executer) exhaustedCascade(cause Update, rules ecarule.RuleDict) bool {
+	if m.cascadeBudget <= 0 || cause.Depth < m.cascadeBudget {
+		return false
+	}
+	if len(rules) > 0 {
+		names := make([]string, 0, len(rules))
+		for name := range rules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		err := &CascadeError{Update: cause, Rules: names, Budget: m.cascadeBudget}
+		m.logger.Warn(err.Error(), zap.String("act", "discovery"), zapOrigin("origin", cause))
+		m.sendError(err)
+	}
+	return true
+}
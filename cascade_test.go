@@ -0,0 +1,103 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestRuleCycles(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["x"] = 0
+	memory.Integer["y"] = 0
+	memory.Integer["z"] = 0
+	e, err := NewExecuterAdvanced(memory, []string{"rule c on x for true do z = x"},
+		MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{RejectCycles: true})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer e.Close()
+	tests := []struct {
+		rules  []string
+		cycles [][]string
+	}{
+		{[]string{"rule up on z for z < 10 do z = z + 1"}, [][]string{{"up"}}},
+		{[]string{"rule a on x for true do y = x", "rule b on y for true do x = y"}, [][]string{{"a", "b"}}},
+		{[]string{"rule a on x for true do y = x"}, nil},
+		{[]string{"rule b on y for true do x = y"}, [][]string{{"a", "b"}}},
+		{[]string{"rule d on y z for true do z = y for all ext.x > 0 do ext.y = this.z"}, [][]string{{"d"}}},
+		{[]string{"rule e on y for true do z = y for all ext.x > 0 do ext.y = this.z"}, nil},
+		{[]string{"rule f on z for true do x = z"}, [][]string{{"a", "c", "e", "f"}}},
+	}
+	for _, test := range tests {
+		err := e.AddRules(test.rules...)
+		var cycleErr *CycleError
+		if test.cycles == nil && err != nil {
+			t.Errorf("AddRules(%q) returned %v", test.rules, err)
+		} else if test.cycles != nil && (!errors.As(err, &cycleErr) || !reflect.DeepEqual(cycleErr.Cycles, test.cycles)) {
+			t.Errorf("AddRules(%q) should report the cycles %v, got %v", test.rules, test.cycles, err)
+		}
+	}
+	if e.HasRule("up") || e.HasRule("b") || e.HasRule("d") || e.HasRule("f") {
+		t.Error("the rules forming cycles should be rejected")
+	}
+	var cycleErr *CycleError
+	if err = e.ReplaceRule("rule c on x for true do x = z"); !errors.As(err, &cycleErr) {
+		t.Error("ReplaceRule should reject a rule activating itself, got", err)
+	}
+
+	// by default the cycles are only logged
+	e2, err := NewExecuter(memory, []string{"rule up on z for z < 10 do z = z + 1"}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e2.Close()
+}
+
+func TestCascadeBudget(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	e, err := NewExecuterAdvanced(memory, []string{"rule forever on foo for true do foo = foo + 1"},
+		MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{CascadeBudget: 3})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	err = e.Input("foo = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 1; i <= 5; i++ {
+		_, pool := e.TakeState()
+		if len(pool) == 0 {
+			break
+		}
+		if pool[0].Depth != i {
+			t.Errorf("update should have depth %d, got %v", i, pool[0])
+		}
+		e.Exec()
+	}
+	mem, pool := e.TakeState()
+	if mem.Integer["foo"] != 4 || len(pool) != 0 {
+		t.Error("the cascade should be stopped after 3 updates, got", mem, pool)
+	}
+	select {
+	case err := <-e.Errors():
+		var cascadeErr *CascadeError
+		if !errors.As(err, &cascadeErr) || cascadeErr.Update.Depth != 3 || cascadeErr.Budget != 3 ||
+			!reflect.DeepEqual(cascadeErr.Rules, []string{"forever"}) {
+			t.Error("unexpected error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for a CascadeError")
+	}
+}
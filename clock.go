@@ -0,0 +1,108 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by an [Executer] for activating the periodic and the delayed rules.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f in its own goroutine after the duration d has elapsed.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a call scheduled by the AfterFunc method of a [Clock].
+type Timer interface {
+	// Stop prevents the call from happening, it reports whether the call was still pending.
+	Stop() bool
+}
+
+// systemClock is the Clock based on the time package, it is used when ExecuterConfig does not specify a Clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// ManualClock is a [Clock] whose time only passes when Advance is called, it is meant for tests.
+type ManualClock struct {
+	now     time.Time
+	pending []*manualTimer
+	lock    sync.Mutex
+}
+
+// manualTimer is a call scheduled on a ManualClock.
+type manualTimer struct {
+	clock *ManualClock
+	when  time.Time
+	f     func()
+}
+
+// NewManualClock returns a ManualClock whose current time is start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the current time of c.
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules f to be called once the time of c is advanced by at least d.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &manualTimer{clock: c, when: c.now.Add(d), f: f}
+	c.pending = append(c.pending, t)
+	return t
+}
+
+// Advance moves the time of c forward by d. The scheduled calls that become due, including the ones
+// scheduled by them, are performed in the calling goroutine in chronological order before Advance returns.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	target := c.now.Add(d)
+	for {
+		next := -1
+		for i, t := range c.pending {
+			if !t.when.After(target) && (next < 0 || t.when.Before(c.pending[next].when)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		t := c.pending[next]
+		c.pending = append(c.pending[:next], c.pending[next+1:]...)
+		c.now = t.when
+		c.lock.Unlock()
+		t.f()
+		c.lock.Lock()
+	}
+	c.now = target
+	c.lock.Unlock()
+}
+
+func (t *manualTimer) Stop() bool {
+	c := t.clock
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, p := range c.pending {
+		if p == t {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/model"
+)
+
+// elementsNode is the [model.ValueNode] of a [memory.Resources] in the data context: it unwraps the
+// interface values of the elements of the List and Map resources, so that the rules can compare them
+// and pass them to functions like the values of the other resources.
+type elementsNode struct {
+	model.ValueNode
+	// other is true for the node of the Other resources, whose values are left wrapped since
+	// their type is the one of the resources.
+	other bool
+}
+
+// GetChildNodeByField returns the node of the field named field.
+func (n elementsNode) GetChildNodeByField(field string) (model.ValueNode, error) {
+	res, err := n.ValueNode.GetChildNodeByField(field)
+	if err != nil {
+		return nil, err
+	}
+	return elementsNode{ValueNode: res, other: field == "Other"}, nil
+}
+
+// GetChildNodeByIndex returns the node of the element at index.
+func (n elementsNode) GetChildNodeByIndex(index int) (model.ValueNode, error) {
+	res, err := n.ValueNode.GetChildNodeByIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapElement(res), nil
+}
+
+// GetChildNodeBySelector returns the node of the element with key index.
+func (n elementsNode) GetChildNodeBySelector(index reflect.Value) (model.ValueNode, error) {
+	res, err := n.ValueNode.GetChildNodeBySelector(index)
+	if err != nil {
+		return nil, err
+	}
+	if n.other {
+		return elementsNode{ValueNode: res}, nil
+	}
+	return unwrapElement(res), nil
+}
+
+func unwrapElement(n model.ValueNode) elementsNode {
+	if v := n.Value(); v.Kind() == reflect.Interface && !v.IsNil() {
+		return elementsNode{ValueNode: model.NewGoValueNode(v.Elem(), n.IdentifiedAs())}
+	}
+	return elementsNode{ValueNode: n}
+}
+
+// elementValue returns v converted to the type of the elements of the List and Map resources:
+// bool, int64 for any integer type, float64 for any floating point type, string or [time.Time].
+func elementValue(v reflect.Value) (any, error) {
+	switch {
+	case !v.IsValid():
+		return nil, errors.New("invalid element: nil")
+	case v.Kind() == reflect.Interface && !v.IsNil():
+		return elementValue(v.Elem())
+	case v.Kind() == reflect.Bool:
+		return v.Bool(), nil
+	case v.CanInt():
+		return v.Int(), nil
+	case v.CanUint() && v.Uint() <= math.MaxInt64:
+		return int64(v.Uint()), nil
+	case v.CanFloat():
+		return v.Float(), nil
+	case v.Kind() == reflect.String:
+		return v.String(), nil
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		return v.Interface(), nil
+	}
+	return nil, fmt.Errorf("invalid element of type %v", v.Type())
+}
+
+// listValue returns the elements of the slice or array v converted by elementValue.
+func listValue(v reflect.Value) ([]any, error) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("the value is not a slice")
+	}
+	res := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		e, err := elementValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// mapValue returns the entries of the map v, whose keys must be strings, with their values
+// converted by elementValue.
+func mapValue(v reflect.Value) (map[string]any, error) {
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, errors.New("the value is not a map with string keys")
+	}
+	res := make(map[string]any, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		e, err := elementValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		res[iter.Key().String()] = e
+	}
+	return res, nil
+}
+
+// sameElement reports whether the elements a and b are equal, Integer and Float elements are
+// compared by value.
+func sameElement(a, b any) bool {
+	switch x := a.(type) {
+	case int64:
+		if y, ok := b.(float64); ok {
+			return float64(x) == y
+		}
+	case float64:
+		if y, ok := b.(int64); ok {
+			return x == float64(y)
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Equal(y)
+		}
+	}
+	return a == b
+}
+
+// Len returns the number of characters of a Text value or the number of elements of a List or Map value.
+func (f builtinFunctions) Len(collection any) (int64, error) {
+	switch c := collection.(type) {
+	case string:
+		return int64(len([]rune(c))), nil
+	case []any:
+		return int64(len(c)), nil
+	case map[string]any:
+		return int64(len(c)), nil
+	}
+	return 0, fmt.Errorf("cannot compute the length of a %T", collection)
+}
+
+// Contains reports whether the Text value container contains the Text value element, whether the
+// List value container contains element or whether the Map value container has the key element.
+func (f builtinFunctions) Contains(container, element any) (bool, error) {
+	switch c := container.(type) {
+	case string:
+		s, ok := element.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot search a %T in a Text value", element)
+		}
+		return strings.Contains(c, s), nil
+	case []any:
+		for _, e := range c {
+			if sameElement(e, element) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := element.(string)
+		if !ok {
+			return false, fmt.Errorf("the keys of a Map value are Text values, got %T", element)
+		}
+		_, present := c[key]
+		return present, nil
+	}
+	return false, fmt.Errorf("cannot search in a %T", container)
+}
+
+// ListOf returns a List value containing the arguments.
+func (f builtinFunctions) ListOf(elements ...any) ([]any, error) {
+	return listValue(reflect.ValueOf(elements))
+}
+
+// EmptyMap returns a Map value without entries.
+func (f builtinFunctions) EmptyMap() map[string]any {
+	return make(map[string]any)
+}
+
+// Append returns a copy of list with elements added at its end.
+func (f builtinFunctions) Append(list []any, elements ...any) ([]any, error) {
+	added, err := listValue(reflect.ValueOf(elements))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, 0, len(list)+len(added))
+	return append(append(res, list...), added...), nil
+}
+
+// RemoveAt returns a copy of list without the element at index.
+func (f builtinFunctions) RemoveAt(list []any, index int64) ([]any, error) {
+	if index < 0 || index >= int64(len(list)) {
+		return nil, fmt.Errorf("index %d out of range for a list of length %d", index, len(list))
+	}
+	res := make([]any, 0, len(list)-1)
+	return append(append(res, list[:index]...), list[index+1:]...), nil
+}
+
+// Remove returns a copy of list without the elements equal to element.
+func (f builtinFunctions) Remove(list []any, element any) []any {
+	res := make([]any, 0, len(list))
+	for _, e := range list {
+		if !sameElement(e, element) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Put returns a copy of m where key is associated with value.
+func (f builtinFunctions) Put(m map[string]any, key string, value any) (map[string]any, error) {
+	v, err := elementValue(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]any, len(m)+1)
+	for k, e := range m {
+		res[k] = e
+	}
+	res[key] = v
+	return res, nil
+}
+
+// Delete returns a copy of m without key.
+func (f builtinFunctions) Delete(m map[string]any, key string) map[string]any {
+	res := make(map[string]any, len(m))
+	for k, e := range m {
+		if k != key {
+			res[k] = e
+		}
+	}
+	return res
+}
+
+// Keys returns the keys of m as a sorted List value.
+func (f builtinFunctions) Keys(m map[string]any) []any {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	res := make([]any, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, k)
+	}
+	return res
+}
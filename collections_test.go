@@ -0,0 +1,93 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/parser"
+)
+
+func TestCollections(t *testing.T) {
+	mem := memory.MakeResources()
+	mem.List["jobs"] = []any{}
+	mem.List["backup"] = []any{}
+	mem.Map["peers"] = map[string]any{}
+	mem.Text["job"] = ""
+	mem.Text["head"] = ""
+	mem.Integer["pending"] = 0
+	mem.Bool["connected"] = false
+	rules := []string{
+		`rule enqueue on job for job != "" && !Contains(jobs, job) do jobs = Append(jobs, job)`,
+		"rule count on jobs for true do pending = Len(jobs)",
+		"rule first on jobs for jobs.Len() > 0 do head = jobs[0]",
+		`rule connect on peers for Contains(peers, "n1") do connected = peers["n1"]`,
+		"rule share on jobs for all Len(jobs) > Len(ext.backup) do ext.backup = jobs",
+	}
+	e, err := NewExecuter(mem, rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	for _, input := range []string{`job = "build"`, `job = "test"`, `job = "build"`, `peers = Put(peers, "n1", true)`} {
+		err = e.Input(input)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+			e.Exec()
+		}
+	}
+	mem, _ = e.TakeState()
+	if !reflect.DeepEqual(mem.List["jobs"], []any{"build", "test"}) || !reflect.DeepEqual(mem.List["backup"], mem.List["jobs"]) ||
+		mem.Integer["pending"] != 2 || mem.Text["head"] != "build" || !mem.Bool["connected"] {
+		t.Error("unexpected state:", mem)
+	}
+	err = e.Input("jobs = RemoveAt(jobs, 0), peers = Delete(peers, \"n1\")")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+		e.Exec()
+	}
+	mem, _ = e.TakeState()
+	if !reflect.DeepEqual(mem.List["jobs"], []any{"test"}) || len(mem.Map["peers"]) != 0 ||
+		mem.Integer["pending"] != 1 || mem.Text["head"] != "test" {
+		t.Error("unexpected state:", mem)
+	}
+	err = e.SetMany(map[string]any{"jobs": []int{3, 1}, "peers": map[string]float32{"n2": 0.5}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	mem, _ = e.TakeState()
+	if !reflect.DeepEqual(mem.List["jobs"], []any{int64(3), int64(1)}) || !reflect.DeepEqual(mem.Map["peers"], map[string]any{"n2": 0.5}) {
+		t.Error("unexpected state:", mem)
+	}
+	for _, value := range []any{3, []any{[]any{}}, nil} {
+		if e.Set("jobs", value) == nil {
+			t.Errorf("setting jobs to %v should fail", value)
+		}
+	}
+	if e.Set("peers", map[int]any{1: true}) == nil {
+		t.Error("setting peers to a map without string keys should fail")
+	}
+	var perr *parser.Error
+	if err := e.AddRules("rule r on head for true do jobs[0] = head"); !errors.As(err, &perr) || perr.Category != parser.CategoryInvalid {
+		t.Error("assigning an element should be an invalid construct, got", err)
+	}
+	if err := e.AddRules("rule r on head for true do jobs = head"); !errors.As(err, &perr) || perr.Category != parser.CategoryTypeMismatch {
+		t.Error("assigning a Text value to a List resource should be a type mismatch, got", err)
+	}
+	for _, input := range []string{"jobs = RemoveAt(jobs, 5)", "jobs = Append(jobs, jobs)", "pending = Len(pending)"} {
+		if e.Input(input) == nil {
+			t.Errorf("%s should fail", input)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package communication
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abu-lang/goabu/metrics"
+)
+
+// Phases of the transactions coordinated by a MemberlistAgent, used as the values of the "phase" label.
+const (
+	phaseInterest = "interest"
+	phaseFirst    = "first"
+	phaseSecond   = "second"
+)
+
+// agentMetrics groups the metrics of a MemberlistAgent.
+type agentMetrics struct {
+	initiated metrics.Counter
+	// committed and aborted are indexed by role: "coordinator" or "participant".
+	committed map[string]metrics.Counter
+	aborted   map[string]metrics.Counter
+	// latency and resends are indexed by phase.
+	latency map[string]metrics.Histogram
+	resends map[string]metrics.Counter
+	// droppedResponses counts the transaction responses discarded by the delegate or by the demultiplexer.
+	droppedResponses metrics.Counter
+	// droppedMessages counts the incoming transaction messages discarded by the delegate.
+	droppedMessages metrics.Counter
+}
+
+// makeAgentMetrics creates the metrics of a MemberlistAgent by means of reg.
+func makeAgentMetrics(reg metrics.Registry) agentMetrics {
+	res := agentMetrics{
+		initiated: reg.Counter("goabu_agent_transactions_initiated_total", "Transactions initiated by the agent."),
+		committed: make(map[string]metrics.Counter),
+		aborted:   make(map[string]metrics.Counter),
+		latency:   make(map[string]metrics.Histogram),
+		resends:   make(map[string]metrics.Counter),
+		droppedResponses: reg.Counter("goabu_agent_dropped_messages_total",
+			"Transaction messages discarded because of full buffers.", "kind", "response"),
+		droppedMessages: reg.Counter("goabu_agent_dropped_messages_total",
+			"Transaction messages discarded because of full buffers.", "kind", "message"),
+	}
+	for _, role := range []string{"coordinator", "participant"} {
+		res.committed[role] = reg.Counter("goabu_agent_transactions_committed_total",
+			"Transactions committed by the agent.", "role", role)
+		res.aborted[role] = reg.Counter("goabu_agent_transactions_aborted_total",
+			"Transactions aborted by the agent.", "role", role)
+	}
+	for _, phase := range []string{phaseInterest, phaseFirst, phaseSecond} {
+		res.latency[phase] = reg.Histogram("goabu_agent_phase_seconds",
+			"Duration of the phases of the coordinated transactions.", "phase", phase)
+		res.resends[phase] = reg.Counter("goabu_agent_resends_total",
+			"Messages of the coordinated transactions sent again because of missing responses.", "phase", phase)
+	}
+	return res
+}
+
+// observePhase records the duration of a phase started at start.
+func (m agentMetrics) observePhase(phase string, start time.Time) {
+	m.latency[phase].Observe(time.Since(start).Seconds())
+}
+
+// SetMetrics makes the MemberlistAgent expose its metrics by means of reg: the transactions initiated,
+// committed and aborted, the duration of the phases of the coordinated transactions, the messages
+// sent again and the transaction messages discarded. If reg is nil then the metrics are discarded.
+// It returns an error if the agent is running.
+func (a *MemberlistAgent) SetMetrics(reg metrics.Registry) error {
+	if a.running {
+		return errors.New("agent is running")
+	}
+	if reg == nil {
+		reg = metrics.Discard
+	}
+	a.metrics = makeAgentMetrics(reg)
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package communication
+
+import (
+	"errors"
+
+	"github.com/abu-lang/goabu/tracing"
+)
+
+// SetTracer makes the MemberlistAgent record the spans of the transactions it takes part in: a span for
+// each initiated transaction along with the spans of its phases and a span for each transaction received
+// as a participant. If t is nil then the spans are not exported.
+// It returns an error if the agent is running.
+func (a *MemberlistAgent) SetTracer(t *tracing.Tracer) error {
+	if a.running {
+		return errors.New("agent is running")
+	}
+	if t == nil {
+		t = tracing.NewTracer(a.id, nil)
+	}
+	a.tracer = t
+	return nil
+}
+
+// startPhase starts the span of the named phase of tran.
+func (a *MemberlistAgent) startPhase(name string, tran transactionInfo) *tracing.Span {
+	return a.tracer.Start(name, tracing.SpanKindInternal, tran.Trace, tracing.String("goabu.transaction", tran.id()))
+}
+
+// endSpan terminates the span of the participation in t, if any, recording its outcome.
+func (t *transactionInfo) endSpan(outcome string) {
+	if t.span == nil {
+		return
+	}
+	t.span.SetAttributes(tracing.String("outcome", outcome))
+	t.span.End()
+}
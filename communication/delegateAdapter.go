@@ -18,6 +18,7 @@ type delegateAdapter struct {
 	transactionMessages  chan message
 	transactionResponses chan message
 	members              BaseMembers
+	metrics              agentMetrics
 	delegate             MemberlistDelegate
 }
 
@@ -86,6 +87,7 @@ func (d delegateAdapter) NotifyMsg(m []byte) {
 			select {
 			case d.transactionResponses <- msg:
 			default:
+				d.metrics.droppedResponses.Inc()
 				d.members.Logger.Warn("Dicarded transaction response",
 					zap.String("act", "discard"),
 					zap.String("obj", "transaction response"),
@@ -96,6 +98,7 @@ func (d delegateAdapter) NotifyMsg(m []byte) {
 			select {
 			case d.transactionMessages <- msg:
 			default:
+				d.metrics.droppedMessages.Inc()
 				d.members.Logger.Warn("Dicarded incoming transaction message",
 					zap.String("act", "discard"),
 					zap.String("obj", "transaction message"),
@@ -10,6 +10,8 @@ import (
 	"sync"
 
 	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/metrics"
+	"github.com/abu-lang/goabu/tracing"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/memberlist"
@@ -83,10 +85,14 @@ type MemberlistAgent struct {
 	coordinatedChannels   chan chan transactionChannels
 	trackGossip           chan chan *sync.WaitGroup
 	initiatedTransactions int
+	metrics               agentMetrics
+	tracer                *tracing.Tracer
 
 	listeningPort     int
 	operations        chan chan []byte
 	operationCommands chan chan string
+	// received maps the commands channels provided by ReceivedActions to the identifiers of their transactions.
+	received sync.Map
 	// testing
 	test       int
 	halted     bool
@@ -126,10 +132,12 @@ func NewMemberlistAgentAdvanced(id string, port int, cfg *memberlist.Config, del
 		initiatedTransactions: 0,
 		operations:            make(chan chan []byte),
 		operationCommands:     make(chan chan string),
+		metrics:               makeAgentMetrics(metrics.Discard),
 	}
 	if res.id == "" {
 		res.id = uuid.New().String() + "/agent"
 	}
+	res.tracer = tracing.NewTracer(res.id, nil)
 	if cfg != nil {
 		res.initialConfig = cfg
 	} else {
@@ -170,6 +178,11 @@ func NewMemberlistAgentAdvanced(id string, port int, cfg *memberlist.Config, del
 	return res
 }
 
+// ID returns the identifier of the MemberlistAgent.
+func (a *MemberlistAgent) ID() string {
+	return a.id
+}
+
 func (a *MemberlistAgent) IsRunning() bool {
 	return a.running
 }
@@ -219,7 +232,7 @@ func (a *MemberlistAgent) Start() error {
 
 	a.running = true
 	a.adapter.start()
-	go demuxResponses(a.coordinatedChannels, a.transactionResponses, a.quitDemux, a.logger)
+	go demuxResponses(a.coordinatedChannels, a.transactionResponses, a.quitDemux, a.logger, a.metrics.droppedResponses)
 	go a.handleTransactions()
 	return nil
 }
@@ -236,6 +249,12 @@ func (a *MemberlistAgent) Join() error {
 }
 
 func (a *MemberlistAgent) ForAll(payload []byte) error {
+	return a.ForAllTraced(payload, tracing.SpanContext{})
+}
+
+// ForAllTraced is like ForAll but the span of the transaction is a child of the span identified by parent,
+// if parent is not valid then the transaction starts a new trace.
+func (a *MemberlistAgent) ForAllTraced(payload []byte, parent tracing.SpanContext) error {
 	if !a.running {
 		return errors.New("agent is not running")
 	}
@@ -245,22 +264,39 @@ func (a *MemberlistAgent) ForAll(payload []byte) error {
 		Payload:   payload,
 	}
 	a.initiatedTransactions++
+	a.metrics.initiated.Inc()
+	span := a.tracer.Start("transaction", tracing.SpanKindProducer, parent, tracing.String("goabu.transaction", info.id()))
+	defer span.End()
+	info.Trace = span.Context()
 	var err error
 	info.Participants, err = a.interested(info)
 	if err != nil {
+		a.metrics.aborted["coordinator"].Inc()
+		span.SetError(err)
 		return err
 	}
+	span.SetAttributes(tracing.Int("goabu.participants", len(info.Participants)))
 	if len(info.Participants) == 0 {
 		a.logger.Debug("Terminated transaction: none interested", zap.String("act", "end_tran"), zap.Int("participants", 0))
 		return nil
 	}
-	return a.coordinateTransaction(info)
+	err = a.coordinateTransaction(info)
+	span.SetError(err)
+	return err
 }
 
 func (a *MemberlistAgent) ReceivedActions() (<-chan chan []byte, <-chan chan string) {
 	return a.operations, a.operationCommands
 }
 
+// ReceivedTransaction returns the identifier of the transaction whose commands are exchanged over commands,
+// a channel provided by ReceivedActions, and forgets it.
+func (a *MemberlistAgent) ReceivedTransaction(commands chan string) string {
+	id, _ := a.received.LoadAndDelete(commands)
+	res, _ := id.(string)
+	return res
+}
+
 func (a *MemberlistAgent) Stop() error {
 	if !a.running {
 		return errors.New("agent is not running")
@@ -382,6 +418,7 @@ func (a *MemberlistAgent) makeAdapter(d MemberlistDelegate) delegateAdapter {
 		trackGossip:          a.trackGossip,
 		transactionMessages:  a.transactionMessages,
 		transactionResponses: a.transactionResponses,
+		metrics:              a.metrics,
 		delegate:             d,
 		members: BaseMembers{
 			AgentID:         a.id,
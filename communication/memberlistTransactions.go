@@ -10,6 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/abu-lang/goabu/metrics"
+	"github.com/abu-lang/goabu/tracing"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/hashicorp/memberlist"
@@ -33,7 +36,11 @@ type transactionInfo struct {
 	Number       int
 	Payload      []byte
 	Participants []string
+	// Trace identifies the span of the transaction.
+	Trace tracing.SpanContext
 
+	// span is the span of the participation in the transaction, if any.
+	span *tracing.Span
 	// initiatorID possibly points to the agent id of the initiator or is nil.
 	initiatorID *string
 	stopMonitor chan bool
@@ -129,7 +136,12 @@ func (a *MemberlistAgent) interested(tran transactionInfo) ([]string, error) {
 	channelsCh := make(chan transactionChannels)
 	a.coordinatedChannels <- channelsCh
 	channelsCh <- channels
+	start := time.Now()
+	span := a.startPhase("interest", tran)
 	nodes, err := a.interestPhase(msg, channels)
+	span.SetError(err)
+	span.End()
+	a.metrics.observePhase(phaseInterest, start)
 	a.testsHaltIf(TestsAfterInterested)
 	if len(nodes) == 0 {
 		channelsCh := make(chan transactionChannels)
@@ -155,7 +167,10 @@ func (a *MemberlistAgent) interestPhase(msg []byte, channels transactionChannels
 		waitFor.Insert(member.Name)
 	}
 	var interested []string
-	for waitFor.Len() > 0 {
+	for sends := 0; waitFor.Len() > 0; sends++ {
+		if sends > 0 {
+			a.metrics.resends[phaseInterest].Add(float64(waitFor.Len()))
+		}
 		var timeout <-chan time.Time = nil
 		waitForCopy := waitFor.Clone()
 		receiversCh := make(chan sets.Set[string])
@@ -234,7 +249,12 @@ func (a *MemberlistAgent) coordinateTransaction(tran transactionInfo) error {
 		zap.String("subj", a.id),
 		zap.String("act", "start_tran"),
 		zap.Int("participants", receivers.Len()))
+	start := time.Now()
+	span := a.startPhase("first_phase", tran)
 	res := a.firstPhase(receivers, msg, channels)
+	span.SetError(res)
+	span.End()
+	a.metrics.observePhase(phaseFirst, start)
 	a.logger.Debug("Terminated first phase",
 		zap.String("subj", a.id),
 		zap.String("act", "end_1_phase"),
@@ -258,7 +278,17 @@ func (a *MemberlistAgent) coordinateTransaction(tran transactionInfo) error {
 	if !ok {
 		a.logger.Panic("Could not marshal "+order.Type+" message", zap.String("act", "marshalling"), zap.String("obj", order.Type))
 	}
+	start = time.Now()
+	span = a.startPhase("second_phase", tran)
+	span.SetAttributes(tracing.String("goabu.decision", action))
 	a.secondPhase(receivers, msg, responses, tran.id())
+	span.End()
+	a.metrics.observePhase(phaseSecond, start)
+	if res == nil {
+		a.metrics.committed["coordinator"].Inc()
+	} else {
+		a.metrics.aborted["coordinator"].Inc()
+	}
 	channelsCh = make(chan transactionChannels)
 	a.coordinatedChannels <- channelsCh
 	channelsCh <- transactionChannels{
@@ -271,7 +301,10 @@ func (a *MemberlistAgent) coordinateTransaction(tran transactionInfo) error {
 
 func (a *MemberlistAgent) firstPhase(participants sets.Set[string], msg []byte, channels transactionChannels) error {
 	waitFor := participants.Clone()
-	for waitFor.Len() > 0 {
+	for sends := 0; waitFor.Len() > 0; sends++ {
+		if sends > 0 {
+			a.metrics.resends[phaseFirst].Add(float64(waitFor.Len()))
+		}
 		var timeout <-chan time.Time = nil
 		waitForCopy := waitFor.Clone()
 		receiversCh := make(chan sets.Set[string])
@@ -319,7 +352,10 @@ func (a *MemberlistAgent) firstPhase(participants sets.Set[string], msg []byte,
 //
 // responses is a channel that must pass the name of a node when a response from that node is received.
 func (a *MemberlistAgent) secondPhase(waitFor sets.Set[string], msg []byte, responses <-chan string, tranID string) {
-	for waitFor.Len() > 0 {
+	for sends := 0; waitFor.Len() > 0; sends++ {
+		if sends > 0 {
+			a.metrics.resends[phaseSecond].Add(float64(waitFor.Len()))
+		}
 		var timeout <-chan time.Time = nil
 		waitForCopy := waitFor.Clone()
 		receiversCh := make(chan sets.Set[string])
@@ -408,7 +444,9 @@ func (a *MemberlistAgent) testsPhaseSend(receivers sets.Set[string], msg []byte,
 	}
 }
 
-func demuxResponses(coordinated <-chan chan transactionChannels, responses <-chan message, quit <-chan chan bool, logger *zap.Logger) {
+func demuxResponses(coordinated <-chan chan transactionChannels, responses <-chan message, quit <-chan chan bool, logger *zap.Logger,
+	dropped metrics.Counter,
+) {
 	stopping := false
 	lines := make(map[string]transactionChannels)
 	for {
@@ -447,6 +485,7 @@ func demuxResponses(coordinated <-chan chan transactionChannels, responses <-cha
 					zap.String("obj", response.Type),
 					zap.String("from", agentID(response.Sender)))
 			default:
+				dropped.Inc()
 				logger.Warn("Discarded interested status",
 					zap.String("act", "discard"),
 					zap.String("obj", response.Type),
@@ -490,6 +529,7 @@ func (a *MemberlistAgent) handleTransactions() {
 					go a.monitorTransaction(*tran)
 				} else {
 					a.terminated[id] = response.Type
+					tran.endSpan(response.Type)
 				}
 				respond = false
 				for _, node := range a.list.Members() {
@@ -509,6 +549,7 @@ func (a *MemberlistAgent) handleTransactions() {
 					}
 					actionsCh := make(chan []byte)
 					commandsCh := make(chan string)
+					a.received.Store(commandsCh, id)
 					a.operations <- actionsCh
 					a.operationCommands <- commandsCh
 					actionsCh <- msg.Transaction.Payload
@@ -524,6 +565,8 @@ func (a *MemberlistAgent) handleTransactions() {
 						}
 					}(*tran)
 					tran.commands = commandsCh
+					tran.span = a.tracer.Start("participate", tracing.SpanKindConsumer, tran.Trace,
+						tracing.String("goabu.transaction", id))
 					for _, member := range a.list.Members() {
 						if member.Name == tran.Initiator {
 							agtID := agentID(member)
@@ -562,7 +605,9 @@ func (a *MemberlistAgent) handleTransactions() {
 					response.Type = <-tran.commands
 					if response.Type == "aborted" {
 						tran.stopMonitor <- true
+						a.metrics.aborted["participant"].Inc()
 						a.terminated[id] = "aborted"
+						tran.endSpan("aborted")
 						delete(a.transactions, id)
 					} else {
 						tran.Participants = msg.Transaction.Participants
@@ -746,7 +791,9 @@ func (a *MemberlistAgent) abort(id string) {
 	tran.stopMonitor <- true
 	tran.commands <- "do_abort"
 	<-tran.commands
+	a.metrics.aborted["participant"].Inc()
 	a.terminated[id] = "aborted"
+	tran.endSpan("aborted")
 	delete(a.transactions, id)
 }
 
@@ -758,7 +805,9 @@ func (a *MemberlistAgent) commit(id string) {
 	tran.stopMonitor <- true
 	tran.commands <- "do_commit"
 	<-tran.commands
+	a.metrics.committed["participant"].Inc()
 	a.terminated[id] = "committed"
+	tran.endSpan("committed")
 	delete(a.transactions, id)
 }
 
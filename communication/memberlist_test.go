@@ -7,9 +7,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/metrics"
+	"github.com/abu-lang/goabu/tracing"
 
 	"github.com/google/uuid"
 )
@@ -166,6 +171,143 @@ func TestForAll(t *testing.T) {
 	}
 }
 
+func TestMetrics(t *testing.T) {
+	agents := makeAgents(t.Name(), []struct {
+		port int
+		join []int
+		test int
+	}{
+		{port: 20100},
+		{port: 20101, join: []int{20100}},
+	})
+	reg := metrics.NewPrometheusRegistry()
+	err := agents[0].SetMetrics(reg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, agt := range agents {
+		start(t, agt, agt.listeningPort)
+	}
+	if agents[0].SetMetrics(nil) == nil {
+		t.Error("SetMetrics should return error when agent is running")
+	}
+	ops, cmds := agents[1].ReceivedActions()
+	result := startMockCommit([]byte("excepteur"), ops, cmds)
+	err = agents[1].Join()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, agt := range agents {
+		for agt.list.NumMembers() != len(agents) {
+		}
+	}
+	err = agents[0].ForAll([]byte("excepteur"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if <-result != TestResCommit {
+		t.Error("the participant should have committed")
+	}
+
+	server := httptest.NewServer(reg)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, sample := range []string{
+		"goabu_agent_transactions_initiated_total 1\n",
+		"goabu_agent_transactions_committed_total{role=\"coordinator\"} 1\n",
+		"goabu_agent_transactions_committed_total{role=\"participant\"} 0\n",
+		"goabu_agent_transactions_aborted_total{role=\"coordinator\"} 0\n",
+		"goabu_agent_phase_seconds_count{phase=\"interest\"} 1\n",
+		"goabu_agent_phase_seconds_count{phase=\"first\"} 1\n",
+		"goabu_agent_phase_seconds_count{phase=\"second\"} 1\n",
+		"goabu_agent_dropped_messages_total{kind=\"response\"} 0\n",
+	} {
+		if !bytes.Contains(body, []byte(sample)) {
+			t.Errorf("missing sample %q in:\n%s", sample, body)
+		}
+	}
+}
+
+func TestTracing(t *testing.T) {
+	agents := makeAgents(t.Name(), []struct {
+		port int
+		join []int
+		test int
+	}{
+		{port: 20102},
+		{port: 20103, join: []int{20102}},
+	})
+	var b bytes.Buffer
+	exporter := tracing.NewJSONExporter(&b)
+	for _, agt := range agents {
+		if err := agt.SetTracer(tracing.NewTracer(agt.ID(), exporter)); err != nil {
+			t.Fatal(err.Error())
+		}
+		start(t, agt, agt.listeningPort)
+	}
+	if agents[0].SetTracer(nil) == nil {
+		t.Error("SetTracer should return error when agent is running")
+	}
+	ops, cmds := agents[1].ReceivedActions()
+	result := startMockCommit([]byte("laboris"), ops, cmds)
+	err := agents[1].Join()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, agt := range agents {
+		for agt.list.NumMembers() != len(agents) {
+		}
+	}
+	parent := tracing.NewTracer("executer", nil).Start("send", tracing.SpanKindProducer, tracing.SpanContext{})
+	err = agents[0].ForAllTraced([]byte("laboris"), parent.Context())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if <-result != TestResCommit {
+		t.Error("the participant should have committed")
+	}
+
+	spans, err := tracing.ReadSpans(&b)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	byName := make(map[string]tracing.SpanData)
+	for _, s := range spans {
+		if s.TraceID != parent.Context().TraceID {
+			t.Errorf("span %s should belong to the trace of its parent", s.Name)
+		}
+		byName[s.Name] = s
+	}
+	if len(spans) != len(byName) || len(spans) != 5 {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	transaction := byName["transaction"]
+	if transaction.Parent != parent.Context().SpanID || transaction.Service != agents[0].ID() {
+		t.Errorf("unexpected transaction span: %+v", transaction)
+	}
+	for _, name := range []string{"interest", "first_phase", "second_phase", "participate"} {
+		if byName[name].Parent != transaction.SpanID {
+			t.Errorf("span %s should be a child of the transaction span", name)
+		}
+	}
+	participate := byName["participate"]
+	if participate.Service != agents[1].ID() || participate.Kind != tracing.SpanKindConsumer {
+		t.Errorf("unexpected participate span: %+v", participate)
+	}
+	outcome := participate.Attributes[len(participate.Attributes)-1]
+	if outcome != tracing.String("outcome", "committed") {
+		t.Errorf("the participation should have committed: %+v", participate)
+	}
+}
+
 func TestStop(t *testing.T) {
 	const port = 11100
 	a := NewMemberlistAgent("TestStop", port, config.TestsLogConfig)
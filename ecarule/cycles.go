@@ -0,0 +1,96 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package ecarule
+
+import (
+	"sort"
+)
+
+// LocalWrites returns the sorted names of the resources assigned by the local tasks of r.
+func (r Rule) LocalWrites() []string {
+	set := make(map[string]bool)
+	for _, task := range r.LocalTasks {
+		for _, action := range task.Actions {
+			set[action.Resource] = true
+		}
+	}
+	res := make([]string, 0, len(set))
+	for resource := range set {
+		res = append(res, resource)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Cycles returns the cycles of the graph having an edge from a rule to another (possibly the same)
+// if the first rule writes, by means of its local tasks, a resource that activates the second one.
+// Each cycle is returned as the sorted names of the rules of a strongly connected component of the
+// graph, the cycles are sorted by their first name. The conditions of the tasks are not taken into
+// account, therefore the rules of a cycle could activate each other forever but do not necessarily do so.
+func (rules RuleDict) Cycles() [][]string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	activated := make(map[string][]string)
+	for _, name := range names {
+		for _, evt := range rules[name].Events {
+			activated[evt] = append(activated[evt], name)
+		}
+	}
+	// Tarjan's strongly connected components algorithm
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var res [][]string
+	var visit func(string)
+	visit = func(v string) {
+		index[v] = len(index)
+		low[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		loop := false
+		for _, resource := range rules[v].LocalWrites() {
+			for _, w := range activated[resource] {
+				if w == v {
+					loop = true
+				}
+				if _, visited := index[w]; !visited {
+					visit(w)
+					low[v] = min(low[v], low[w])
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 || loop {
+			sort.Strings(component)
+			res = append(res, component)
+		}
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			visit(name)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0]
+	})
+	return res
+}
@@ -7,7 +7,7 @@ import "github.com/hyperjumptech/grule-rule-engine/ast"
 
 // Parser is the interface implemented by parsers of GoAbU rules.
 type Parser interface {
-	// Parse parses a series of GoAbU rules.
+	// Parse parses a series of GoAbU rules, it returns the errors found in all of them.
 	Parse(...string) ([]Rule, []error)
 	// ParseExpressions parses a series of local expressions.
 	ParseExpressions(...string) ([]*ast.Expression, []error)
@@ -5,6 +5,8 @@
 package ecarule
 
 import (
+	"time"
+
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 )
 
@@ -12,8 +14,17 @@ import (
 type Rule struct {
 	// Name specifies the rule's name.
 	Name string
+	// Source contains the code of the rule.
+	Source string
+	// Salience is the priority of the rule: the updates produced by rules with higher salience are executed first.
+	Salience int
 	// Events is a list of resource names. The rule is activated when any of the listed resources changes its value.
 	Events []string
+	// Period, if positive, specifies that the rule has no Events and it is activated periodically.
+	Period time.Duration
+	// Delay, if positive, specifies that the rule is activated once Delay has elapsed since the last change
+	// of the resources in Events.
+	Delay time.Duration
 	// LocalTasks contains the rule's local tasks that can modify only local resources when the condition matches.
 	LocalTasks []LocalTask
 	// RemoteTasks contains the rule's remote tasks that modify the resources of the other nodes matching the condition.
@@ -34,6 +45,9 @@ type LocalTask struct {
 	Condition *ast.Expression
 	// Actions is a list of assignments where only local resources can appear.
 	Actions []Action
+	// ReadResources contains the sorted names of the resources read by the condition and by the actions
+	// of the task, it is only filled in for the tasks of parsed rules.
+	ReadResources []string
 }
 
 // RemoteTask models a remote task that can update the resources of the other nodes.
@@ -47,6 +61,18 @@ type RemoteTask struct {
 	RemoteResources []string
 	// LocalResources contains all the names of the local resources of the task.
 	LocalResources []string
+	// AssignedResources contains the sorted names of the remote resources assigned by the actions,
+	// it is only filled in for the tasks of parsed rules.
+	AssignedResources []string
+	// ReadRemoteResources contains the sorted names of the remote resources read by the condition and
+	// by the actions, it is only filled in for the tasks of parsed rules.
+	ReadRemoteResources []string
+	// Salience is the salience of the rule the task belongs to.
+	Salience int
+	// Rule is the name of the rule the task belongs to.
+	Rule string
+	// Index is the index of the task among the remote tasks of the rule.
+	Index int
 }
 
 // String returns the code of the action's assignment.
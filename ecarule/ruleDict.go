@@ -19,6 +19,10 @@ func (rules RuleDict) Insert(rule *Rule) {
 	rules[rule.Name] = rule
 }
 
+func (rules RuleDict) Remove(name string) {
+	delete(rules, name)
+}
+
 func (rules RuleDict) Empty() bool {
 	return len(rules) == 0
 }
@@ -0,0 +1,136 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// EvalStep specifies the step of the execution where an [EvalError] occurred.
+type EvalStep int
+
+const (
+	// StepInput marks the errors occurred while evaluating the actions passed to Input.
+	StepInput EvalStep = iota
+	// StepTask marks the errors occurred while evaluating a local task of a rule of the node.
+	StepTask
+	// StepReceivedTask marks the errors occurred while evaluating a task received from another node.
+	StepReceivedTask
+	// StepAssignment marks the errors occurred while assigning the values of an update to the resources.
+	StepAssignment
+	// StepInvariant marks the errors occurred while evaluating an invariant.
+	StepInvariant
+	// StepSend marks the errors occurred while encoding the remote tasks to send to the other nodes,
+	// e.g. because of the value of an Other resource, the tasks are not sent.
+	StepSend
+)
+
+// String returns the name of the step.
+func (s EvalStep) String() string {
+	switch s {
+	case StepInput:
+		return "input"
+	case StepTask:
+		return "task"
+	case StepReceivedTask:
+		return "received task"
+	case StepAssignment:
+		return "assignment"
+	case StepInvariant:
+		return "invariant"
+	case StepSend:
+		return "send"
+	default:
+		return fmt.Sprintf("EvalStep(%d)", int(s))
+	}
+}
+
+// EvalError is the error returned by Input and sent over the channel returned by Errors when an
+// expression cannot be evaluated at runtime (e.g. a function call fails or an Other resource is nil).
+type EvalError struct {
+	// Step specifies the step of the execution where the error occurred.
+	Step EvalStep
+	// Rule is the name of the rule whose task caused the error, it is empty for the errors
+	// that are not caused by a rule.
+	Rule string
+	// Task is the index of the task of Rule that caused the error (see [Update]).
+	Task int
+	// Invariant is the name of the invariant whose evaluation failed, it is empty unless
+	// Step is StepInvariant.
+	Invariant string
+	// Resource is the name of the resource whose assignment failed, it is empty unless
+	// Step is StepAssignment.
+	Resource string
+	// Err is the error reported by the evaluation.
+	Err error
+}
+
+// Error returns a description of the error.
+func (e *EvalError) Error() string {
+	switch {
+	case e.Rule != "":
+		return fmt.Sprintf("%s evaluation failed for task #%d of rule %s: %v", e.Step, e.Task, e.Rule, e.Err)
+	case e.Invariant != "":
+		return fmt.Sprintf("%s evaluation failed for %s: %v", e.Step, e.Invariant, e.Err)
+	case e.Resource != "":
+		return fmt.Sprintf("%s of %s failed: %v", e.Step, e.Resource, e.Err)
+	default:
+		return fmt.Sprintf("%s evaluation failed: %v", e.Step, e.Err)
+	}
+}
+
+// Unwrap returns the error reported by the evaluation.
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns the channel over which the Executer sends the errors occurred while executing in
+// the background: the failed evaluations of the tasks of the triggered rules (whose updates are not
+// added to the pool), of the tasks received from other nodes (whose transactions are aborted), of
+// the invariants and of the assignments of the executed updates (which are discarded), and the
+// failed encodings of the remote tasks to send (which are dropped). These errors are [*EvalError]s,
+// while the [*CascadeError]s report the rules that were not activated because of the cascade budget
+// (see ExecuterConfig). The errors of Input are returned to its caller and are sent
+// over the channel only for the inputs coming from the ResourceController. The channel is shared by all
+// the callers.
+//
+// Like the channels returned by Subscribe, the channel has a bounded buffer and the Executer never
+// blocks on it: when the buffer is full the oldest error is discarded. The channel is closed by Close.
+func (m *Executer) Errors() <-chan error {
+	return m.errors
+}
+
+// reportError logs err and sends it over the channel returned by Errors.
+func (m *Executer) reportError(err error) {
+	m.logger.Error(err.Error(), zap.String("act", "eval"))
+	m.sendError(err)
+}
+
+// sendError sends err over the channel returned by Errors without blocking, discarding
+// the oldest buffered errors if needed.
+func (m *Executer) sendError(err error) {
+	m.lockErrors.Lock()
+	defer m.lockErrors.Unlock()
+	select {
+	case <-m.closed:
+		return
+	default:
+	}
+	if m.collector != nil {
+		m.collector.Errors = append(m.collector.Errors, err)
+		return
+	}
+	sendDropOldest(m.errors, err, func(*error) {
+		m.logger.Warn("Discarded error: channel full", zap.String("act", "eval"))
+	})
+}
+
+// closeErrors closes the channel returned by Errors.
+func (m *Executer) closeErrors() {
+	m.lockErrors.Lock()
+	defer m.lockErrors.Unlock()
+	close(m.errors)
+}
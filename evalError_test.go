@@ -0,0 +1,141 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+// receiveError returns the next error sent over ch, it fails t after a timeout.
+func receiveError(t *testing.T, ch <-chan error) *EvalError {
+	t.Helper()
+	select {
+	case err := <-ch:
+		var evalErr *EvalError
+		if !errors.As(err, &evalErr) {
+			t.Fatal("expected an EvalError, got", err)
+		}
+		return evalErr
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for an error")
+	}
+	return nil
+}
+
+// faultyResources returns resources where calling a method of the Other resource o fails.
+func faultyResources() memory.Resources {
+	res := memory.MakeResources()
+	res.Integer["i"] = 0
+	res.Text["s"] = ""
+	res.Other["o"] = nil
+	return res
+}
+
+func TestFaultyInput(t *testing.T) {
+	e, err := NewExecuter(faultyResources(), nil, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	err = e.Input("s = \"modified\", i = o.Len()")
+	var evalErr *EvalError
+	if !errors.As(err, &evalErr) || evalErr.Step != StepInput {
+		t.Fatal("expected an input EvalError, got", err)
+	}
+	if mem, _ := e.TakeState(); mem.Text["s"] != "" {
+		t.Error("no resource should be modified, got", mem)
+	}
+	// the executer keeps on working
+	err = e.Input("i = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if mem, _ := e.TakeState(); mem.Integer["i"] != 1 {
+		t.Error("i should be 1, got", mem)
+	}
+}
+
+func TestFaultyRules(t *testing.T) {
+	rules := []string{
+		"rule faulty on i for true do i = o.Len() for true do s = \"ok\"",
+		"rule received on s for all ext.i > 0 do ext.i = ext.o.Len()",
+	}
+	e, err := NewExecuter(faultyResources(), rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	errs := e.Errors()
+	err = e.Input("i = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if evalErr := receiveError(t, errs); evalErr.Step != StepTask || evalErr.Rule != "faulty" || evalErr.Task != 0 {
+		t.Error("unexpected error:", evalErr)
+	}
+	_, pool := e.TakeState()
+	if len(pool) != 1 || pool[0].Rule != "faulty" || pool[0].Task != 1 {
+		t.Fatal("the update of the other task should be in the pool, got", pool)
+	}
+	e.Exec()
+	// the received task cannot be evaluated: the transaction is aborted
+	if evalErr := receiveError(t, errs); evalErr.Step != StepReceivedTask || evalErr.Rule != "received" {
+		t.Error("unexpected error:", evalErr)
+	}
+	mem, pool := e.TakeState()
+	if mem.Integer["i"] != 1 || mem.Text["s"] != "ok" || len(pool) != 0 {
+		t.Error("unexpected state:", mem, pool)
+	}
+	e.Close()
+	if _, ok := <-errs; ok {
+		t.Error("channel should be closed by Close")
+	}
+}
+
+// unsendable is a type not registered in encoding/gob.
+type unsendable struct{}
+
+func (unsendable) Name() string {
+	return "unsendable"
+}
+
+func TestUnsendableTasks(t *testing.T) {
+	res := memory.MakeResources()
+	res.Integer["i"] = 0
+	res.Text["s"] = ""
+	res.Other["o"] = unsendable{}
+	rules := []string{"rule send on i for all ext.i > 0 do ext.s = this.o.Name()"}
+	e, err := NewExecuter(res, rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	errs := e.Errors()
+	err = e.Input("i = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if evalErr := receiveError(t, errs); evalErr.Step != StepSend {
+		t.Error("unexpected error:", evalErr)
+	}
+	// the executer keeps on working
+	err = e.Input("i = 2")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if mem, _ := e.TakeState(); mem.Integer["i"] != 2 {
+		t.Error("i should be 2, got", mem)
+	}
+}
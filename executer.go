@@ -4,17 +4,23 @@
 package goabu
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/abu-lang/goabu/config"
 	"github.com/abu-lang/goabu/ecarule"
 	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/metrics"
 	"github.com/abu-lang/goabu/parser"
+	"github.com/abu-lang/goabu/persistence"
 	"github.com/abu-lang/goabu/stringset"
+	"github.com/abu-lang/goabu/tracing"
 
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 	"go.uber.org/zap"
@@ -22,19 +28,48 @@ import (
 )
 
 type Executer struct {
-	memory         memory.ResourceController
-	lockMemory     sync.RWMutex
-	types          map[string]string
+	memory     memory.ResourceController
+	lockMemory sync.RWMutex
+	types      map[string]string
+	// variables contains the variables encoding the resources, used for building updates without parsing.
+	variables      map[string]*ast.Variable
+	functions      *functionTable
 	pool           []Update
+	scheduling     SchedulingPolicy
 	coordinator    execCoordinator
 	updateReceiver chan<- preparedUpdates
+	poolSignal     chan struct{}
 	lockPool       sync.Mutex
 	ruleLibrary    map[string]ecarule.RuleDict
+	disabledRules  stringset.Set
 	lockRules      sync.Mutex
-	invariants     []*ast.Expression
+	invariants     []invariant
+	// readOnly contains the resources that the rules and the received tasks cannot assign.
+	readOnly readOnlyResources
+	// ranges contains the metadata of the resources with a range.
+	ranges         map[string]memory.Metadata
+	violations     chan InvariantViolation
+	lockViolations sync.Mutex
+	errors         chan error
+	lockErrors     sync.Mutex
+	// collector, if not nil, receives the violations and the errors in place of their channels.
+	collector     *Simulation
+	inputPolicy   InputPolicy
+	cascadeBudget int
+	rejectCycles  bool
+	metrics       executerMetrics
+	tracer        *tracing.Tracer
+
+	clock        Clock
+	timers       map[string]*ruleTimer
+	timersActive bool
+	lockTimers   sync.Mutex
+	// firing tracks the activations of the periodic and delayed rules in progress.
+	firing sync.WaitGroup
 
 	workingMemory *ast.WorkingMemory
-	dataContext   ast.IDataContext
+	// dataContext is shared by the evaluations, whose Now built-in function is fixed by means of withEvaluationTime.
+	dataContext ast.IDataContext
 
 	lexerParserPool sync.Pool
 
@@ -47,6 +82,66 @@ type Executer struct {
 	optimistExec   bool
 	optimistInput  bool
 	lockOptimistic sync.Mutex
+
+	subscribers        map[<-chan ResourceChange]*subscription
+	subscriptionBuffer int
+	lockSubscribers    sync.Mutex
+
+	store            persistence.Store
+	snapshotInterval time.Duration
+	lastUpdateID     atomic.Uint64
+	quitSnapshots    chan chan bool
+
+	pacing       time.Duration
+	quitInputs   chan chan bool
+	quitUpdates  chan chan bool
+	closed       chan struct{}
+	runs         sync.WaitGroup
+	transactions sync.WaitGroup
+	lockClose    sync.Mutex
+}
+
+// ErrClosed is returned by the methods of an [Executer] that has been closed.
+var ErrClosed = errors.New("executer is closed")
+
+// ExecuterConfig groups the optional settings of an [Executer].
+type ExecuterConfig struct {
+	// Scheduling is the policy used for choosing the next update to execute, if nil [FIFO] is used.
+	Scheduling SchedulingPolicy
+	// Pacing is the minimum delay between two consecutive executions performed by Run,
+	// if it is zero then Run executes the updates as soon as possible.
+	Pacing time.Duration
+	// SubscriptionBuffer is the capacity of the channels returned by Subscribe,
+	// if it is not positive then [DefaultSubscriptionBuffer] is used.
+	SubscriptionBuffer int
+	// Store, if not nil, is used for persisting the state of the Executer (see Snapshot).
+	Store persistence.Store
+	// InputPolicy is the policy used for the inputs that would violate the invariants,
+	// the default is InputReject.
+	InputPolicy InputPolicy
+	// CascadeBudget is the maximum length of a causal chain of updates (see the Depth field of Update):
+	// the rules activated by an update whose Depth is CascadeBudget produce no updates and a [*CascadeError]
+	// is sent over the channel returned by Errors. If it is zero or negative then the causal chains are
+	// unbounded, [DefaultCascadeBudget] is a suitable budget for most nodes.
+	CascadeBudget int
+	// RejectCycles makes AddRules and ReplaceRule return a [*CycleError], instead of logging a warning,
+	// for the rules that could activate each other forever (see [ecarule.RuleDict.Cycles]).
+	RejectCycles bool
+	// Metrics, if not nil, is used for exposing the metrics of the Executer: the length of the pool,
+	// the number of enqueued, executed and rejected updates and the time spent waiting for the resources.
+	Metrics metrics.Registry
+	// Tracer, if not nil, records the spans of the inputs, of the executions of the updates, of the timer
+	// activations and of the remote tasks sent and received. If nil the updates still carry the identifiers
+	// of their traces (see the Trace field of Update) but no span is exported.
+	Tracer *tracing.Tracer
+	// Functions are registered, as if by RegisterFunction, before parsing the rules and the invariants.
+	Functions map[string]any
+	// Clock is used for activating the periodic ("every") and the delayed ("after") rules,
+	// if nil the system clock is used.
+	Clock Clock
+	// SnapshotInterval is the interval between two consecutive snapshots taken automatically,
+	// if it is zero then snapshots are only taken upon construction and by calling Snapshot.
+	SnapshotInterval time.Duration
 }
 
 func NewExecuter(
@@ -55,15 +150,84 @@ func NewExecuter(
 	agt Agent,
 	lc config.LogConfig,
 	invariants ...string,
+) (*Executer, error) {
+	return NewExecuterAdvanced(mem, rules, agt, lc, nil, invariants...)
+}
+
+// NewExecuterAdvanced creates an Executer like NewExecuter does but also allows specifying
+// its optional settings by means of cfg, if cfg is nil then the default settings are used.
+func NewExecuterAdvanced(
+	mem memory.ResourceController,
+	rules []string,
+	agt Agent,
+	lc config.LogConfig,
+	cfg *ExecuterConfig,
+	invariants ...string,
+) (*Executer, error) {
+	if cfg == nil {
+		cfg = &ExecuterConfig{}
+	}
+	res, err := newExecuter(mem, rules, agt, lc, cfg, invariants...)
+	if err != nil {
+		return nil, err
+	}
+	err = res.start(mem, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// newExecuter creates an Executer without starting its goroutines, the ResourceController nor the Agent.
+func newExecuter(
+	mem memory.ResourceController,
+	rules []string,
+	agt Agent,
+	lc config.LogConfig,
+	cfg *ExecuterConfig,
+	invariants ...string,
 ) (*Executer, error) {
 	res := &Executer{
-		memory:      mem.Copy(),
-		pool:        make([]Update, 0),
-		coordinator: newCoordinator(),
-		ruleLibrary: make(map[string]ecarule.RuleDict),
-		invariants:  make([]*ast.Expression, 0, len(invariants)),
-		agent:       agt,
+		memory:        mem.Copy(),
+		pool:          make([]Update, 0),
+		scheduling:    cfg.Scheduling,
+		inputPolicy:   cfg.InputPolicy,
+		cascadeBudget: cfg.CascadeBudget,
+		rejectCycles:  cfg.RejectCycles,
+		clock:         cfg.Clock,
+		tracer:        cfg.Tracer,
+		functions:     newFunctionTable(),
+		timers:        make(map[string]*ruleTimer),
+		coordinator:   newCoordinator(),
+		ruleLibrary:   make(map[string]ecarule.RuleDict),
+		disabledRules: stringset.Make(),
+		poolSignal:    make(chan struct{}, 1),
+		pacing:        cfg.Pacing,
+		quitInputs:    make(chan chan bool),
+		quitUpdates:   make(chan chan bool),
+		closed:        make(chan struct{}),
+		quitSnapshots: make(chan chan bool),
+		subscribers:   make(map[<-chan ResourceChange]*subscription),
+		invariants:    make([]invariant, 0, len(invariants)),
+		agent:         agt,
+	}
+	res.subscriptionBuffer = cfg.SubscriptionBuffer
+	if res.subscriptionBuffer <= 0 {
+		res.subscriptionBuffer = DefaultSubscriptionBuffer
+	}
+	if res.clock == nil {
+		res.clock = systemClock{}
+	}
+	if res.tracer == nil {
+		res.tracer = tracing.NewTracer(res.agentID(), nil)
 	}
+	if cfg.Metrics != nil {
+		res.registerMetrics(cfg.Metrics)
+	} else {
+		res.registerMetrics(metrics.Discard)
+	}
+	res.violations = make(chan InvariantViolation, res.subscriptionBuffer)
+	res.errors = make(chan error, res.subscriptionBuffer)
 	if res.memory.HasDuplicates() {
 		return nil, errors.New("multiple resources have the same name")
 	}
@@ -72,13 +236,22 @@ func NewExecuter(
 		return nil, err
 	}
 	res.types = res.memory.Types()
-	res.dataContext, res.workingMemory, err = newEmptyGruleStructures(map[string]memory.Resources{"this": res.memory.GetResources()})
+	err = res.initMetadata()
+	if err != nil {
+		return nil, err
+	}
+	err = res.functions.registerAll(cfg.Functions)
+	if err != nil {
+		return nil, err
+	}
+	res.dataContext, res.workingMemory, err = newEmptyGruleStructures(map[string]memory.Resources{"this": res.memory.GetResources()}, res.functions, newEvaluationTime(res.clock))
 	if err != nil {
 		return nil, err
 	}
 	res.lexerParserPool = sync.Pool{
 		New: func() interface{} {
-			return parser.New(res.types, res.workingMemory, &res.lockMemory)
+			return parser.New(res.types, res.workingMemory,
+				parser.WithLocker(&res.lockMemory), parser.WithFunctions(res.functions), parser.WithReadOnly(res.readOnly))
 		},
 	}
 	if lc.Encoding == "" {
@@ -97,25 +270,43 @@ func NewExecuter(
 		return nil, err
 	}
 	res.SetLogLevel(lc.Level)
-	err = res.addInvariants(invariants...)
+	res.variables, err = res.resourceVariables()
 	if err != nil {
 		return nil, err
 	}
-	err = res.AddRules(rules...)
+	err = res.addInvariants(invariants...)
 	if err != nil {
 		return nil, err
 	}
-	res.updateReceiver = res.startUpdateReceiver()
-	err = mem.Start()
+	err = res.AddRules(rules...)
 	if err != nil {
 		return nil, err
 	}
-	go res.receiveInputs()
-	err = res.StartAgent()
+	return res, nil
+}
+
+// start starts the goroutines of m, the ResourceController from which m was created and the Agent.
+// If cfg specifies a Store then m starts persisting its state, beginning with a snapshot.
+func (m *Executer) start(mem memory.ResourceController, cfg *ExecuterConfig) error {
+	m.updateReceiver = m.startUpdateReceiver()
+	if cfg.Store != nil {
+		m.store = cfg.Store
+		err := m.Snapshot()
+		if err != nil {
+			return err
+		}
+		if cfg.SnapshotInterval > 0 {
+			m.snapshotInterval = cfg.SnapshotInterval
+			go m.takeSnapshots()
+		}
+	}
+	err := mem.Start()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return res, nil
+	m.startTimers()
+	go m.receiveInputs()
+	return m.StartAgent()
 }
 
 func (m *Executer) StartAgent() error {
@@ -149,8 +340,10 @@ func (m *Executer) SetAgent(agt Agent) error {
 	return nil
 }
 
+// TakeState returns a copy of the resources of the node and of its pool of updates, the copies do not share
+// mutable state with the node (see [memory.DeepCopy]) and can be freely modified.
 func (m *Executer) TakeState() (memory.Resources, []Update) {
-	m.coordinator.requestWrite(false)
+	m.requestWrite(false)
 	m.lockMemory.RLock()
 	memCopy := m.memory.Copy().GetResources()
 	m.lockMemory.RUnlock()
@@ -159,9 +352,7 @@ func (m *Executer) TakeState() (memory.Resources, []Update) {
 	lock <- false // no updates are added
 	poolCopy := make([]Update, 0, len(m.pool))
 	for _, update := range m.pool {
-		var updateCopy Update = make([]Assignment, len(update))
-		copy(updateCopy, update)
-		poolCopy = append(poolCopy, updateCopy)
+		poolCopy = append(poolCopy, update.copy())
 	}
 	<-lock
 	m.coordinator.closeWrite()
@@ -169,7 +360,7 @@ func (m *Executer) TakeState() (memory.Resources, []Update) {
 }
 
 func (m *Executer) DoIfStable(f func()) bool {
-	m.coordinator.requestWrite(false)
+	m.requestWrite(false)
 	lock := make(chan bool)
 	m.updateReceiver <- preparedUpdates{confirm: lock}
 	lock <- false // no updates are added
@@ -189,43 +380,221 @@ func (m *Executer) HasRule(name string) bool {
 }
 
 // AddRules adds a list of GoAbU rules to the node's knowledge base.
+// If some rules cannot be parsed then none is added and the returned error joins the [*parser.Error]s
+// found in all of them, which can be retrieved by means of errors.As or of its Unwrap() []error method.
+// The rules that could activate each other forever are reported as described by the RejectCycles
+// field of ExecuterConfig.
 func (m *Executer) AddRules(rules ...string) error {
 	if len(rules) == 0 {
 		return nil
 	}
+	parsedRules, err := m.parseRules(rules...)
+	if err != nil {
+		return err
+	}
 	m.lockRules.Lock()
 	defer m.lockRules.Unlock()
-	parser := m.lexerParserPool.Get().(ecarule.Parser)
-	defer m.lexerParserPool.Put(parser)
-	parsedRules, errs := parser.Parse(rules...)
-	if len(errs) > 0 {
-		for _, err := range errs {
-			m.logger.Error("error during parsing: "+err.Error(),
-				zap.String("act", "parse"),
-				zap.Strings("obj", rules))
-		}
-		m.logger.Sync()
-		return errs[0]
+	err = m.checkCycles(parsedRules)
+	if err != nil {
+		return err
 	}
+	defer m.persistRules()
 	if len(parsedRules) == 1 {
 		return m.addRuleAux(parsedRules[0])
 	}
 	return addList(parsedRules, m.addRuleAux)
 }
 
+// RemoveRule removes the rule with the given name from the node's knowledge base.
+// The updates already produced by the rule are not removed from the pool and discoveries
+// that are already in progress may still be using the removed rule.
+func (m *Executer) RemoveRule(name string) error {
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	if !m.hasRuleAux(name) {
+		return fmt.Errorf("there is no rule named %s", name)
+	}
+	m.removeRuleAux(name)
+	m.disabledRules.Remove(name)
+	m.persistRules()
+	return nil
+}
+
+// ReplaceRule substitutes the rule having the same name of the rule encoded by src with the latter.
+// The new rule inherits whether the replaced rule was enabled (see SetRuleEnabled).
+// Like for RemoveRule, discoveries that are already in progress may still be using the replaced rule.
+func (m *Executer) ReplaceRule(src string) error {
+	parsedRules, err := m.parseRules(src)
+	if err != nil {
+		return err
+	}
+	if len(parsedRules) != 1 {
+		return fmt.Errorf("expected a single rule, found %d", len(parsedRules))
+	}
+	rule := parsedRules[0]
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	if !m.hasRuleAux(rule.Name) {
+		return fmt.Errorf("there is no rule named %s", rule.Name)
+	}
+	err = m.checkCycles(parsedRules)
+	if err != nil {
+		return err
+	}
+	m.removeRuleAux(rule.Name)
+	err = m.addRuleAux(rule)
+	m.persistRules()
+	return err
+}
+
+// SetRuleEnabled enables or disables the rule with the given name. Disabled rules remain in the
+// node's knowledge base but are never activated. Rules are enabled when added.
+func (m *Executer) SetRuleEnabled(name string, enabled bool) error {
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	if !m.hasRuleAux(name) {
+		return fmt.Errorf("there is no rule named %s", name)
+	}
+	if enabled {
+		m.disabledRules.Remove(name)
+	} else {
+		m.disabledRules.Insert(name)
+	}
+	m.persistRules()
+	m.logger.Debug(fmt.Sprintf("Set rule enabled: %t", enabled), zap.String("act", "enable_rule"), zap.String("obj", name))
+	return nil
+}
+
+// IsRuleEnabled reports whether the node has an enabled rule with the given name.
+func (m *Executer) IsRuleEnabled(name string) bool {
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	return m.hasRuleAux(name) && !m.disabledRules.Has(name)
+}
+
+// Run repeatedly calls Exec until ctx is done or the Executer is closed, waiting for new updates
+// whenever the pool is empty. Two consecutive executions are separated by at least the Pacing
+// specified upon construction. Run returns ctx.Err() if ctx is done and [ErrClosed] if the
+// Executer is closed. Multiple calls to Run can be active at the same time.
+func (m *Executer) Run(ctx context.Context) error {
+	m.lockClose.Lock()
+	select {
+	case <-m.closed:
+		m.lockClose.Unlock()
+		return ErrClosed
+	default:
+	}
+	m.runs.Add(1)
+	m.lockClose.Unlock()
+	defer m.runs.Done()
+	var pacing <-chan time.Time = nil
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.closed:
+			return ErrClosed
+		default:
+		}
+		if pacing == nil {
+			m.lockPool.Lock()
+			empty := len(m.pool) == 0
+			m.lockPool.Unlock()
+			if !empty {
+				m.Exec()
+				if m.pacing > 0 {
+					pacing = time.After(m.pacing)
+				}
+				continue
+			}
+		}
+		// when pacing the pool is checked again after the delay
+		var signal <-chan struct{} = nil
+		if pacing == nil {
+			signal = m.poolSignal
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.closed:
+			return ErrClosed
+		case <-pacing:
+			pacing = nil
+		case <-signal:
+		}
+	}
+}
+
+// Close stops the Executer: it waits for the active calls to Run to return, it cancels the activations
+// of the periodic and delayed rules, it stops the Agent
+// (if running) and the ResourceController and terminates every goroutine started by the Executer.
+// The Executer should not be used after Close, subsequent calls to Close return [ErrClosed].
+func (m *Executer) Close() error {
+	m.lockClose.Lock()
+	select {
+	case <-m.closed:
+		m.lockClose.Unlock()
+		return ErrClosed
+	default:
+	}
+	close(m.closed)
+	m.lockClose.Unlock()
+	m.runs.Wait()
+	m.stopTimers()
+	reply := make(chan bool)
+	if m.snapshotInterval > 0 {
+		m.quitSnapshots <- reply
+		<-reply
+	}
+	m.quitInputs <- reply
+	<-reply
+	var res error
+	m.lockAgent.Lock()
+	if m.agent.IsRunning() {
+		res = m.agent.Stop()
+	}
+	m.lockAgent.Unlock()
+	var err error
+	if stoppable, ok := m.memory.(memory.StoppableResourceController); ok {
+		m.lockMemory.Lock()
+		err = stoppable.Stop()
+		m.lockMemory.Unlock()
+	}
+	if res == nil {
+		res = err
+	}
+	// the agent has been stopped so the transactions in progress are about to terminate
+	m.transactions.Wait()
+	m.quitUpdates <- reply
+	<-reply
+	m.closeSubscriptions()
+	m.closeViolations()
+	m.closeErrors()
+	m.logger.Info("Closed executer", zap.String("act", "close"))
+	m.logger.Sync()
+	return res
+}
+
 func (m *Executer) Exec() {
-	m.coordinator.requestWrite(m.HasOptimisticExec())
+	m.exec()
+}
+
+// exec implements Exec, it returns the chosen update and whether it was executed.
+func (m *Executer) exec() (Update, bool) {
+	m.requestWrite(m.HasOptimisticExec())
 	defer m.coordinator.closeWrite()
 	m.lockPool.Lock()
 	if len(m.pool) == 0 {
 		m.lockPool.Unlock()
-		return
+		return Update{}, false
 	}
 	update, index := m.chooseUpdate()
 	m.lockPool.Unlock()
-	m.logger.Info(fmt.Sprintf("Exec: %v", update), zap.String("act", "exec"), zapUpdate("update", update))
+	m.logger.Info(fmt.Sprintf("Exec: %v", update), zap.String("act", "exec"), zapUpdate("update", update), zapOrigin("origin", update))
+	span := m.tracer.Start("exec", tracing.SpanKindInternal, update.Trace, updateAttributes(update)...)
+	defer span.End()
 	workingSet := stringset.Make()
-	for _, action := range update {
+	for _, action := range update.Assignments {
 		workingSet.Insert(action.Resource)
 	}
 	m.coordinator.fixWorkingSetWrite(workingSet)
@@ -233,57 +602,149 @@ func (m *Executer) Exec() {
 	m.removeUpdate(index)
 	m.lockPool.Unlock()
 	m.lockMemory.Lock()
-	var modified stringset.Set
-	if len(m.invariants) > 0 {
-		copy := m.memory.Extract(workingSet.Slice())
-		modified = m.applyUpdate(update, false)
-		if !m.invariantsOk() {
-			m.memory.Enclose(copy)
-			for _, action := range update {
-				if modified.Has(action.Resource) {
-					modified.Remove(action.Resource)
-					m.workingMemory.ResetVariable(action.variable)
-				}
+	var previous memory.Resources
+	if m.hasInvariants() {
+		previous = m.memory.Extract(workingSet.Slice())
+	}
+	modified, changes, err := m.applyUpdate(update)
+	if err != nil {
+		m.discardUpdate(update)
+		m.logger.Info(fmt.Sprintf("Exec-Fail: %v could not be applied", update),
+			zap.String("act", "exec-fail"),
+			zapUpdate("update", update))
+		m.reportError(err)
+		span.SetError(err)
+		return update, false
+	}
+	if m.hasInvariants() {
+		violated, err := m.violatedInvariants()
+		if err != nil || len(violated) > 0 {
+			after := m.resourceValues(workingSet)
+			m.restoreResources(previous, update)
+			before := m.resourceValues(workingSet)
+			m.discardUpdate(update)
+			if err != nil {
+				m.logger.Info(fmt.Sprintf("Exec-Fail: the invariants could not be evaluated after %v", update),
+					zap.String("act", "exec-fail"),
+					zapUpdate("update", update))
+				m.reportError(err)
+				span.SetError(err)
+				return update, false
 			}
-			m.lockMemory.Unlock()
-			m.coordinator.confirmWrite()
-			m.logger.Info(fmt.Sprintf("Exec-Fail: %v would violate the invariants", update),
+			m.logger.Info(fmt.Sprintf("Exec-Fail: %v would violate the invariants %v", update, violated),
 				zap.String("act", "exec-fail"),
-				zapUpdate("update", update))
-			return
+				zapUpdate("update", update),
+				zap.Strings("invariants", violated))
+			m.reportViolations(violated, update, before, after)
+			m.metrics.rejected.Inc()
+			span.SetAttributes(tracing.String("outcome", "rejected"))
+			return update, false
 		}
-	} else {
-		modified = m.applyUpdate(update, false)
 	}
+	m.metrics.executed.Inc()
+	m.publishChanges(changes)
 	m.signalModified(modified)
-	m.discovery(modified)
+	span.SetAttributes(tracing.String("outcome", "executed"))
+	cause := update
+	cause.Trace = span.Context()
+	m.discovery(modified, cause)
+	// recorded after the consequences of update: if the record is lost update is executed again
+	m.persistApplied(update.id, changes)
 	m.logger.Debug("Terminated Exec", zap.String("act", "exec"))
 	m.logger.Sync()
+	return update, true
+}
+
+// discardUpdate terminates a call to Exec that did not execute update. It should be called while
+// holding m.lockMemory, which is released.
+func (m *Executer) discardUpdate(update Update) {
+	m.persistApplied(update.id, nil)
+	m.lockMemory.Unlock()
+	m.coordinator.confirmWrite()
 }
 
 func (m *Executer) Input(actions string) error {
-	parsed, err := m.parseActions(actions)
+	_, err := m.input(actions, nil)
+	return err
+}
+
+// input implements Input and SetMany: it atomically performs the actions, if values is nil or actions
+// is not empty, and sets the resources named by the keys of values as a single input. It returns the
+// update of the input.
+func (m *Executer) input(actions string, values map[string]any) (Update, error) {
+	var parsed []ecarule.Action
+	if actions != "" || values == nil {
+		var err error
+		parsed, err = m.parseActions(actions)
+		if err != nil {
+			return Update{}, err
+		}
+	}
+	typed, err := m.typedAssignments(values)
 	if err != nil {
-		return err
+		return Update{}, err
 	}
 	workingSet := stringset.Make()
 	for _, p := range parsed {
 		workingSet.Insert(p.Resource)
 	}
-	m.coordinator.requestWrite(m.HasOptimisticInput())
+	for _, a := range typed {
+		workingSet.Insert(a.Resource)
+	}
+	// the repair actions are part of the input
+	m.lockMemory.RLock()
+	workingSet.Add(m.repairResources())
+	m.lockMemory.RUnlock()
+	obj := actions
+	if len(typed) > 0 {
+		obj = strings.TrimSpace(obj + " " + Update{Assignments: typed}.String())
+	}
+	m.requestWrite(m.HasOptimisticInput())
 	defer m.coordinator.closeWrite()
 	m.coordinator.fixWorkingSetWrite(workingSet)
+	now := newEvaluationTime(m.clock)
+	defer now.fix()()
 	m.lockMemory.RLock()
-	update, err := evalActions(parsed, m.dataContext, m.workingMemory)
+	update, err := evalActions(parsed, withEvaluationTime(m.dataContext, now), m.workingMemory)
+	m.lockMemory.RUnlock()
 	if err != nil {
-		m.logger.Panic("Error during input actions evaluation: "+err.Error(),
-			zap.String("act", "eval"),
-			zap.String("obj", actions))
+		err = &EvalError{Step: StepInput, Err: err}
+		m.logger.Error(err.Error(), zap.String("act", "eval"), zap.String("obj", actions))
+		m.coordinator.confirmWrite()
+		return Update{}, err
 	}
-	m.lockMemory.RUnlock()
-	m.logger.Info("Input: "+actions, zap.String("act", "input"), zapUpdate("update", update))
+	update.Assignments = append(update.Assignments, typed...)
+	update.Source = SourceInput
+	m.logger.Info("Input: "+obj, zap.String("act", "input"), zapUpdate("update", update))
+	return update, m.applyInput(update, obj, workingSet)
+}
+
+// applyInput applies the update of an input, described by obj, and performs the discovery.
+// It should be called after fixing workingSet as the working set of the input.
+func (m *Executer) applyInput(update Update, obj string, workingSet stringset.Set) error {
+	span := m.tracer.Start("input", tracing.SpanKindInternal, tracing.SpanContext{}, tracing.String("goabu.input", obj))
+	defer span.End()
+	update.Trace = span.Context()
 	m.lockMemory.Lock()
-	m.discovery(m.applyUpdate(update, true))
+	var previous memory.Resources
+	if m.hasInvariants() {
+		previous = m.memory.Extract(update.resources())
+	}
+	modified, changes, err := m.applyUpdate(update)
+	if err == nil && m.hasInvariants() {
+		modified, changes, err = m.enforceInvariants(update, previous, modified, changes, workingSet)
+	}
+	if err != nil {
+		m.lockMemory.Unlock()
+		m.coordinator.confirmWrite()
+		m.logger.Error(err.Error(), zap.String("act", "input"), zap.String("obj", obj))
+		span.SetError(err)
+		return err
+	}
+	m.signalModified(modified)
+	m.persistApplied(0, changes)
+	m.publishChanges(changes)
+	m.discovery(modified, update)
 	m.logger.Debug("Processed input", zap.String("act", "input"))
 	m.logger.Sync()
 	return nil
@@ -351,25 +812,77 @@ func (m *Executer) HasOptimisticInput() bool {
 	return m.optimistInput
 }
 
+// SetSchedulingPolicy sets the policy used by Exec for choosing the next update to execute.
+// If p is nil then [FIFO] is used.
+func (m *Executer) SetSchedulingPolicy(p SchedulingPolicy) {
+	m.lockPool.Lock()
+	m.scheduling = p
+	m.lockPool.Unlock()
+}
+
+// chooseUpdate returns the next update to execute along with its index in m.pool.
+// The update is chosen by the scheduling policy among the updates with the highest salience.
+// It should be called only when m.pool is not empty and while holding m.lockPool.
 func (m *Executer) chooseUpdate() (Update, int) {
-	// TODO: implement other strategies
-	return m.pool[0], 0
+	candidates, indexes := highestSalience(m.pool)
+	if m.scheduling == nil {
+		return m.pool[indexes[0]], indexes[0]
+	}
+	i := m.scheduling.Choose(candidates)
+	if i < 0 || i >= len(candidates) {
+		m.logger.Error(fmt.Sprintf("Scheduling policy chose invalid index %d: falling back to FIFO", i),
+			zap.String("act", "choose"),
+			zap.Int("pool", len(candidates)))
+		i = 0
+	}
+	return m.pool[indexes[i]], indexes[i]
+}
+
+// highestSalience returns the updates of pool having the highest salience, in arrival order,
+// along with their indexes in pool.
+func highestSalience(pool []Update) ([]Update, []int) {
+	max := pool[0].Salience
+	for _, u := range pool[1:] {
+		if u.Salience > max {
+			max = u.Salience
+		}
+	}
+	var updates []Update
+	var indexes []int
+	for i, u := range pool {
+		if u.Salience == max {
+			updates = append(updates, u)
+			indexes = append(indexes, i)
+		}
+	}
+	return updates, indexes
 }
 
 func (m *Executer) removeUpdate(index int) {
 	m.pool = append(m.pool[:index], m.pool[index+1:len(m.pool)]...)
 }
 
-func (m *Executer) applyUpdate(update Update, input bool) stringset.Set {
+// applyUpdate performs the assignments of update returning the set of modified resources and the
+// description of their changes, whose values do not share any state with the resources.
+func (m *Executer) applyUpdate(update Update) (stringset.Set, []ResourceChange, error) {
 	modified := stringset.Make()
-	for _, action := range update {
+	var changes []ResourceChange
+	// assigned contains the performed assignments along with the previous values of the resources
+	var assigned []Assignment
+	fail := func(resource string, err error) (stringset.Set, []ResourceChange, error) {
+		for i := len(assigned) - 1; i >= 0; i-- {
+			// the previous value was assignable
+			assigned[i].variable.Assign(assigned[i].Value, m.dataContext, m.workingMemory)
+			m.workingMemory.ResetVariable(assigned[i].variable)
+		}
+		return nil, nil, &EvalError{Step: StepAssignment, Rule: update.Rule, Task: update.Task, Resource: resource, Err: err}
+	}
+	for _, action := range update.Assignments {
 		variable := action.variable
 		variable = m.workingMemory.AddVariable(variable)
 		currentVal, err := variable.Evaluate(m.dataContext, m.workingMemory)
 		if err != nil {
-			m.logger.Panic(fmt.Sprintf("Could not evaluate resource %s: %s", action.Resource, err.Error()),
-				zap.String("act", "eval_var"),
-				zapUpdate("action", action))
+			return fail(action.Resource, err)
 		}
 		if reflect.DeepEqual(currentVal, action.Value) {
 			m.logger.Debug(fmt.Sprintf("Skipping action %v: resource value would not change", action),
@@ -380,41 +893,23 @@ func (m *Executer) applyUpdate(update Update, input bool) stringset.Set {
 		ltype := currentVal.Type()
 		rtype := action.Value.Type()
 		if !rtype.AssignableTo(ltype) {
-			m.logger.DPanic(fmt.Sprintf("Skipping action %v: cannot assign a %v to a %v", action, rtype, ltype),
-				zap.String("act", "assign"),
-				zapUpdate("action", action))
-		} else {
-			err := variable.Assign(action.Value, m.dataContext, m.workingMemory)
-			if err != nil {
-				m.logger.Panic("Could not perform assignment: "+err.Error(),
-					zap.String("act", "assign"),
-					zapUpdate("action", action))
-			}
-			modified.Insert(action.Resource)
-			if input {
-				m.memory.Modified(action.Resource)
-				m.logger.Debug(fmt.Sprintf("Modified resource \"%s\"", action.Resource),
-					zap.String("act", "assign"),
-					zapUpdate("action", action))
-			}
+			return fail(action.Resource, fmt.Errorf("cannot assign a %v to a %v", rtype, ltype))
 		}
-	}
-	return modified
-}
-
-func (m *Executer) invariantsOk() bool {
-	for _, inv := range m.invariants {
-		val, err := inv.Evaluate(m.dataContext, m.workingMemory)
+		err = variable.Assign(action.Value, m.dataContext, m.workingMemory)
 		if err != nil {
-			m.logger.Panic("Could not evaluate invariant: "+err.Error(),
-				zap.String("act", "eval_inv"),
-				zap.String("obj", inv.GetGrlText()))
-		}
-		if !val.Bool() {
-			return false
+			return fail(action.Resource, err)
 		}
+		assigned = append(assigned, Assignment{Resource: action.Resource, variable: variable, Value: currentVal})
+		modified.Insert(action.Resource)
+		changes = append(changes, ResourceChange{
+			Resource: action.Resource,
+			Old:      memory.DeepCopy(currentVal.Interface()),
+			New:      memory.DeepCopy(action.Value.Interface()),
+			Source:   update.Source,
+			Rule:     update.Rule,
+		})
 	}
-	return true
+	return modified, changes, nil
 }
 
 func (m *Executer) signalModified(modified stringset.Set) {
@@ -434,8 +929,16 @@ func (m *Executer) resourceValue(resource string) reflect.Value {
 
 // discovery given a set of modified resource names adds to the pool the updates coming from the
 // triggered local rules and adds the updates from the global rules in the pools of the other nodes.
-func (m *Executer) discovery(modified stringset.Set) {
-	updates, wire := m.triggeredActions(modified)
+// The resources were modified by cause.
+func (m *Executer) discovery(modified stringset.Set, cause Update) {
+	m.activate(m.activeRules(modified, cause), cause)
+}
+
+// activate adds to the pool the updates coming from the local tasks of rules and adds the updates from
+// their remote tasks in the pools of the other nodes, the rules were activated by cause.
+// It should be called while holding m.lockMemory, which is released.
+func (m *Executer) activate(rules ecarule.RuleDict, cause Update) {
+	updates, wire := m.triggeredActions(rules, cause)
 	m.lockMemory.Unlock()
 	ok := make(chan bool)
 	m.updateReceiver <- preparedUpdates{updates: updates, confirm: ok}
@@ -446,18 +949,31 @@ func (m *Executer) discovery(modified stringset.Set) {
 		zap.String("act", "discovery"),
 		zapUpdates("updates", updates))
 	if len(wire.Tasks) > 0 {
+		span := m.tracer.Start("send", tracing.SpanKindProducer, wire.Trace,
+			tracing.Int("goabu.tasks", len(wire.Tasks)))
+		defer span.End()
+		wire.Trace = span.Context()
 		payload, err := marshalWireTasks(wire)
 		if err != nil {
-			m.logger.Panic("Error during external actions marshalling: "+err.Error(),
-				zap.String("act", "marshalling"),
-				zap.String("obj", "external actions"))
+			m.reportError(&EvalError{Step: StepSend, Err: err})
+			span.SetError(err)
+			return
 		}
 		tentatives := 0
 		for {
-			err = m.agent.ForAll(payload)
+			err = m.forAll(payload, wire.Trace)
 			if err == nil {
 				break
 			}
+			select {
+			case <-m.closed:
+				m.logger.Error("Executer closed: dropping external actions",
+					zap.String("act", "for_all"),
+					zap.Int("transactions", tentatives+1))
+				span.SetError(ErrClosed)
+				return
+			default:
+			}
 			tentatives++
 			if tentatives%10 == 0 {
 				m.logger.Error(fmt.Sprintf("Failed %d transactions", tentatives),
@@ -465,24 +981,38 @@ func (m *Executer) discovery(modified stringset.Set) {
 					zap.Int("transactions", tentatives))
 			}
 		}
+		span.SetAttributes(tracing.Int("goabu.attempts", tentatives+1))
 	}
 }
 
-// triggeredActions, given a set of modified resources, calculates the local updates and the partially evaluated tasks
-// that are to be sent to the other nodes.
-func (m *Executer) triggeredActions(modified stringset.Set) ([]Update, wireTasks) {
+// triggeredActions, given a set of activated rules, calculates the local updates and the partially evaluated tasks
+// that are to be sent to the other nodes. The rules were activated by cause: the calculated updates and tasks
+// have depth cause.Depth + 1, if this exceeds the cascade budget then there are no updates nor tasks.
+func (m *Executer) triggeredActions(rules ecarule.RuleDict, cause Update) ([]Update, wireTasks) {
 	var newpool []Update
 	var wTask wireTasks
-	rules := m.activeRules(modified)
+	if m.exhaustedCascade(cause, rules) {
+		return nil, wTask
+	}
+	// the tasks of the activated rules are evaluated at the same instant
+	now := newEvaluationTime(m.clock)
+	defer now.fix()()
+	dataContext := withEvaluationTime(m.dataContext, now)
 	localResources := stringset.Make()
 	for _, rule := range rules {
-		for _, task := range rule.LocalTasks {
-			tActions, err := condEvalActions(task.Condition, task.Actions, m.dataContext, m.workingMemory)
+		for i, task := range rule.LocalTasks {
+			tActions, err := condEvalActions(task.Condition, task.Actions, dataContext, m.workingMemory)
 			if err != nil {
-				m.logger.Panic("Error during actions evaluation: "+err.Error(),
-					zap.String("act", "eval"),
-					zap.String("obj", "actions"))
+				m.reportError(&EvalError{Step: StepTask, Rule: rule.Name, Task: i, Err: err})
+				continue
 			}
+			tActions.Rule = rule.Name
+			tActions.Task = i
+			tActions.Source = SourceLocal
+			tActions.Salience = rule.Salience
+			tActions.Initiator = m.agentID()
+			tActions.Depth = cause.Depth + 1
+			tActions.Trace = cause.Trace
 			newpool = appendNonempty(newpool, tActions)
 		}
 		for _, task := range rule.RemoteTasks {
@@ -491,15 +1021,39 @@ func (m *Executer) triggeredActions(modified stringset.Set) ([]Update, wireTasks
 		}
 	}
 	wTask.Resources = m.memory.Extract(localResources.Slice())
+	if len(wTask.Tasks) > 0 {
+		wTask.Initiator = m.agentID()
+		wTask.Depth = cause.Depth + 1
+		wTask.Trace = cause.Trace
+	}
 	return newpool, wTask
 }
 
-func (m *Executer) activeRules(modified stringset.Set) ecarule.RuleDict {
+// agentID returns the identifier of the Agent of m, if any.
+func (m *Executer) agentID() string {
+	if agt, ok := m.agent.(IdentifiedAgent); ok {
+		return agt.ID()
+	}
+	return ""
+}
+
+// activeRules returns the enabled rules activated by the modified resources, except for the delayed
+// rules whose activation is (re)scheduled instead. The resources were modified by cause.
+func (m *Executer) activeRules(modified stringset.Set, cause Update) ecarule.RuleDict {
 	res := ecarule.MakeRuleDict()
 	m.lockRules.Lock()
 	for resource := range modified {
 		res.Add(m.ruleLibrary[resource])
 	}
+	for name := range m.disabledRules {
+		res.Remove(name)
+	}
+	for name, rule := range res {
+		if rule.Delay > 0 {
+			res.Remove(name)
+			m.delay(rule, cause)
+		}
+	}
 	m.lockRules.Unlock()
 	return res
 }
@@ -518,7 +1072,12 @@ func (m *Executer) addRuleAux(rule ecarule.Rule) error {
 	if m.hasRuleAux(rule.Name) {
 		return fmt.Errorf("there is already a rule named %s", rule.Name)
 	}
-	for _, evt := range rule.Events {
+	events := rule.Events
+	if rule.Period > 0 {
+		events = []string{periodicEvent}
+		m.schedulePeriodic(&rule)
+	}
+	for _, evt := range events {
 		if m.ruleLibrary[evt] == nil {
 			m.ruleLibrary[evt] = ecarule.MakeRuleDict()
 		}
@@ -528,6 +1087,36 @@ func (m *Executer) addRuleAux(rule ecarule.Rule) error {
 	return nil
 }
 
+// removeRuleAux removes the rule with the given name from every event index of the node's knowledge base.
+func (m *Executer) removeRuleAux(name string) {
+	for evt, d := range m.ruleLibrary {
+		d.Remove(name)
+		if d.Empty() {
+			delete(m.ruleLibrary, evt)
+		}
+	}
+	m.unschedule(name)
+	m.logger.Debug("Removed rule", zap.String("act", "remove_rule"), zap.String("obj", name))
+}
+
+// parseRules parses a series of GoAbU rules, the returned error joins the [*parser.Error]s of all the rules.
+// It should not be called while holding m.lockRules since the parser acquires m.lockMemory.
+func (m *Executer) parseRules(rules ...string) ([]ecarule.Rule, error) {
+	parser := m.lexerParserPool.Get().(ecarule.Parser)
+	defer m.lexerParserPool.Put(parser)
+	res, errs := parser.Parse(rules...)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			m.logger.Error("error during parsing: "+err.Error(),
+				zap.String("act", "parse"),
+				zap.Strings("obj", rules))
+		}
+		m.logger.Sync()
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
+
 func (m *Executer) addActions(actions string) error {
 	parsed, err := m.parseActions(actions)
 	if err != nil {
@@ -538,48 +1127,20 @@ func (m *Executer) addActions(actions string) error {
 		return err
 	}
 	m.pool = append(m.pool, update)
+	m.signalPool()
 	return nil
 }
 
-func (m *Executer) addPool(pl []string) error {
-	return addList(pl, m.addActions)
+// signalPool notifies a waiting call to Run that some updates were added to the pool.
+func (m *Executer) signalPool() {
+	select {
+	case m.poolSignal <- struct{}{}:
+	default:
+	}
 }
 
-// addInvariants constructs the node's invariant by forming a logical conjunction from
-// a list of local boolean expressions.
-func (m *Executer) addInvariants(invs ...string) error {
-	if len(invs) == 0 {
-		return nil
-	}
-	parser := m.lexerParserPool.Get().(ecarule.Parser)
-	defer m.lexerParserPool.Put(parser)
-	exps, errs := parser.ParseExpressions(invs...)
-	if len(errs) > 0 {
-		for _, err := range errs {
-			m.logger.Error("error during parsing: "+err.Error(),
-				zap.String("act", "parse"),
-				zap.Strings("obj", invs))
-		}
-		m.logger.Sync()
-		return errs[0]
-	}
-	for i, exp := range exps {
-		val, err := exp.Evaluate(m.dataContext, m.workingMemory)
-		if err != nil {
-			m.logger.Error("Could not evaluate invariant: "+err.Error(),
-				zap.String("act", "eval_inv"),
-				zap.String("obj", invs[i]))
-			return err
-		}
-		if val.Kind() != reflect.Bool {
-			m.logger.Error("Invariant with non-boolean type",
-				zap.String("act", "add_inv"),
-				zap.String("obj", invs[i]))
-			return fmt.Errorf("type of invariant #%d is not boolean", i)
-		}
-		m.invariants = append(m.invariants, exp)
-	}
-	return nil
+func (m *Executer) addPool(pl []string) error {
+	return addList(pl, m.addActions)
 }
 
 // parseActions parses a series of local actions.
@@ -594,7 +1155,7 @@ func (m *Executer) parseActions(actions string) ([]ecarule.Action, error) {
 				zap.String("obj", actions))
 		}
 		m.logger.Sync()
-		return nil, errs[0]
+		return nil, errors.Join(errs...)
 	}
 
 	return res, nil
@@ -602,7 +1163,10 @@ func (m *Executer) parseActions(actions string) ([]ecarule.Action, error) {
 
 // newEmptyGruleStructures creates a clean working memory and data context containing
 // the [memory.Resources] from resources as struct instances referenced by the map keys.
-func newEmptyGruleStructures(resources map[string]memory.Resources) (ast.IDataContext, *ast.WorkingMemory, error) {
+// The function calls are dispatched to the built-in functions and to the ones registered in functions,
+// the Now built-in function returns the time of now. The elements of the List and Map resources
+// are unwrapped by elementsNode.
+func newEmptyGruleStructures(resources map[string]memory.Resources, functions *functionTable, now *evaluationTime) (ast.IDataContext, *ast.WorkingMemory, error) {
 	dataContext := ast.NewDataContext()
 	kbName := "dummy"
 	for name, rs := range resources {
@@ -613,6 +1177,10 @@ func newEmptyGruleStructures(resources map[string]memory.Resources) (ast.IDataCo
 			return dataContext, nil, err
 		}
 	}
+	dc := dataContext.(*ast.DataContext)
+	for name := range resources {
+		dc.ObjectStore[name] = elementsNode{ValueNode: dc.Get(name)}
+	}
 	version := "0.0.0"
 	knowledgeBase := &ast.KnowledgeBase{
 		Name:          kbName,
@@ -620,15 +1188,17 @@ func newEmptyGruleStructures(resources map[string]memory.Resources) (ast.IDataCo
 		RuleEntries:   make(map[string]*ast.RuleEntry),
 		WorkingMemory: ast.NewWorkingMemory(kbName, version),
 	}
-	err := dataContext.Add("DEFUNC",
-		makeBuiltinFunctions(
-			knowledgeBase,
-			knowledgeBase.WorkingMemory,
-			dataContext,
-		))
+	builtins := makeBuiltinFunctions(
+		knowledgeBase,
+		knowledgeBase.WorkingMemory,
+		dataContext,
+		now,
+	)
+	err := dataContext.Add("DEFUNC", builtins)
 	if err != nil {
 		return dataContext, nil, err
 	}
+	dc.ObjectStore["DEFUNC"] = functionsNode{ValueNode: dc.Get("DEFUNC"), builtins: reflect.ValueOf(builtins), functions: functions}
 	knowledgeBase.InitializeContext(dataContext)
 	return dataContext, knowledgeBase.WorkingMemory, nil
 }
@@ -648,19 +1218,12 @@ func validNames(names []string) error {
 }
 
 func addList[T any](objs []T, add func(T) error) error {
-	var fstErr error
-	failed := ""
+	var errs []error
 	for i, obj := range objs {
 		err := add(obj)
 		if err != nil {
-			failed += strconv.Itoa(i) + ", "
-			if fstErr == nil {
-				fstErr = err
-			}
+			errs = append(errs, fmt.Errorf("could not add element #%d: %w", i, err))
 		}
 	}
-	if fstErr != nil {
-		return fmt.Errorf("could not add elements with indexes %s as %s", failed[:len(failed)-2], fstErr.Error())
-	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -4,6 +4,7 @@
 package goabu
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/abu-lang/goabu/memory"
 	"github.com/abu-lang/goabu/parser"
 	"github.com/abu-lang/goabu/stringset"
+	"github.com/abu-lang/goabu/tracing"
 
 	"go.uber.org/zap"
 )
@@ -31,47 +33,87 @@ type preparedUpdates struct {
 
 func (m *Executer) receiveInputs() {
 	inputs := m.memory.Inputs()
+	var events <-chan memory.InputEvent
+	if typed, ok := m.memory.(memory.TypedResourceController); ok {
+		events = typed.InputEvents()
+	}
 	errors := m.memory.Errors()
 	bufferSize := int(math.RoundToEven(float64(len(m.types)) * inputsRate))
 	var buffer string = ""
+	var values map[string]any = make(map[string]any)
 	var l int = 0
 	var timeout <-chan time.Time = nil
 	var inBuffer stringset.Set = stringset.Make()
+	// flush applies the buffered inputs and events as a single update
 	flush := func() {
-		err := m.Input(buffer)
-		if err != nil {
-			m.logger.Panic("Error in parsing I/O input actions: "+err.Error(),
-				zap.String("act", "io_parse"), zap.String("obj", buffer))
+		if buffer != "" || len(values) > 0 {
+			_, err := m.input(buffer, values)
+			if err != nil {
+				m.logger.Error("Error in I/O inputs: "+err.Error(),
+					zap.String("act", "io_input"), zap.String("obj", buffer), zap.Any("values", values))
+				m.sendError(err)
+			}
 		}
 		buffer = ""
+		values = make(map[string]any)
 		l = 0
 		inBuffer = stringset.Make()
 		timeout = nil
 	}
 	for {
+		var resource string
 		select {
 		case err := <-errors:
 			m.logger.Error("I/O error: "+err.Error(), zap.String("act", "io"))
+			flush()
+			continue
 		case input := <-inputs:
-			resource := strings.TrimSpace(strings.Split(input, "=")[0])
+			resource = strings.TrimSpace(strings.Split(input, "=")[0])
 			if inBuffer.Has(resource) {
 				flush()
 			}
 			buffer += input
-			l++
-			inBuffer.Insert(resource)
-			if l < bufferSize {
-				if l == 1 {
-					timeout = time.After(inputsFlush * time.Millisecond)
-				}
-				continue
+		case event := <-events:
+			resource = event.Resource
+			if inBuffer.Has(resource) {
+				flush()
 			}
+			values[resource] = event.Value
 		case <-timeout:
+			flush()
+			continue
+		case reply := <-m.quitInputs:
+			reply <- true
+			return
+		}
+		l++
+		inBuffer.Insert(resource)
+		if l < bufferSize {
+			if l == 1 {
+				timeout = time.After(inputsFlush * time.Millisecond)
+			}
+			continue
 		}
 		flush()
 	}
 }
 
+// takeSnapshots periodically takes a snapshot of the state of m every m.snapshotInterval.
+func (m *Executer) takeSnapshots() {
+	ticker := time.NewTicker(m.snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// errors are logged by Snapshot
+			m.Snapshot()
+		case reply := <-m.quitSnapshots:
+			reply <- true
+			return
+		}
+	}
+}
+
 func (m *Executer) receiveExternalActions() {
 	requests, commandRequests := m.agent.ReceivedActions()
 	for {
@@ -80,12 +122,21 @@ func (m *Executer) receiveExternalActions() {
 			return
 		}
 		commandsCh := <-commandRequests
-		go m.serveTransaction(actionsCh, commandsCh)
+		transaction := ""
+		if agt, ok := m.agent.(TransactionAgent); ok {
+			transaction = agt.ReceivedTransaction(commandsCh)
+		}
+		m.transactions.Add(1)
+		go func() {
+			defer m.transactions.Done()
+			m.serveTransaction(actionsCh, commandsCh, transaction)
+		}()
 	}
 }
 
 // serveTransaction interacts with the Agent in order to possibly receive and append a list of Updates to m.pool.
-func (m *Executer) serveTransaction(actionsCh <-chan []byte, commandsCh chan string) {
+// transaction is the identifier of the transaction assigned by the Agent, if any.
+func (m *Executer) serveTransaction(actionsCh <-chan []byte, commandsCh chan string, transaction string) {
 	defer m.logger.Sync()
 	wTasks, err := unmarshalWireTasks(<-actionsCh)
 	if err != nil {
@@ -95,41 +146,77 @@ func (m *Executer) serveTransaction(actionsCh <-chan []byte, commandsCh chan str
 		commandsCh <- "aborted"
 		return
 	}
+	span := m.tracer.Start("receive", tracing.SpanKindConsumer, wTasks.Trace,
+		tracing.String("goabu.initiator", wTasks.Initiator),
+		tracing.String("goabu.transaction", transaction),
+		tracing.Int("goabu.tasks", len(wTasks.Tasks)))
+	defer span.End()
+	outcome := "aborted"
+	defer func() { span.SetAttributes(tracing.String("outcome", outcome)) }()
 	var updates []Update
 	workingSet := stringset.Make(wTasks.getRemoteResources()...)
-	k := m.coordinator.requestRead(workingSet)
+	k := m.requestRead(workingSet)
+	// all the received tasks are evaluated at the same instant
+	now := newEvaluationTime(m.clock)
+	defer now.fix()()
 	m.lockMemory.RLock()
-	context, workMem, err := newEmptyGruleStructures(map[string]memory.Resources{"this": m.memory.GetResources(), "ext": wTasks.Resources})
+	context, workMem, err := newEmptyGruleStructures(map[string]memory.Resources{"this": m.memory.GetResources(), "ext": wTasks.Resources}, m.functions, now)
 	m.lockMemory.RUnlock()
 	if err != nil {
-		m.logger.Panic(err.Error())
-	}
-	p := parser.New(m.types, workMem)
-	lTasks, errs := p.ParseRemoteTasks(wTasks.Resources.Types(), wTasks.Tasks...)
-	if len(errs) > 0 {
-		for _, err := range errs {
-			m.logger.Error("error during parsing: "+err.Error(),
-				zap.String("act", "parse"),
-				zap.String("obj", "received tasks"))
-		}
-		m.logger.Sync()
+		m.logger.Error("Could not create the evaluation context: "+err.Error(),
+			zap.String("act", "eval"),
+			zap.String("obj", "received tasks"))
+		m.coordinator.closeRead(k)
 		commandsCh <- "aborted"
 		return
 	}
-	for _, task := range lTasks {
-		m.lockMemory.RLock()
-		update, err := condEvalActions(task.Condition, task.Actions, context, workMem)
-		if err != nil {
-			m.logger.Panic("Error during received task evaluation: "+err.Error(),
-				zap.String("act", "eval"),
-				zap.String("obj", "received tasks"))
+	p := parser.New(m.types, workMem, parser.WithFunctions(m.functions), parser.WithReadOnly(m.readOnly))
+	remoteTypes := wTasks.Resources.Types()
+	for _, rTask := range wTasks.Tasks {
+		lTasks, errs := p.ParseRemoteTasks(remoteTypes, rTask)
+		if len(errs) > 0 {
+			for _, err := range errs {
+				m.logger.Error("error during parsing: "+err.Error(),
+					zap.String("act", "parse"),
+					zap.String("obj", "received tasks"))
+			}
+			// e.g. the task calls a function that is not registered on this node
+			err = &EvalError{Step: StepReceivedTask, Rule: rTask.Rule, Task: rTask.Index, Err: errors.Join(errs...)}
+			m.reportError(err)
+			span.SetError(err)
+			m.logger.Sync()
+			m.coordinator.closeRead(k)
+			commandsCh <- "aborted"
+			return
+		}
+		for _, task := range lTasks {
+			m.lockMemory.RLock()
+			update, err := condEvalActions(task.Condition, task.Actions, context, workMem)
+			if err != nil {
+				m.lockMemory.RUnlock()
+				err = &EvalError{Step: StepReceivedTask, Rule: rTask.Rule, Task: rTask.Index, Err: err}
+				m.reportError(err)
+				span.SetError(err)
+				m.coordinator.closeRead(k)
+				commandsCh <- "aborted"
+				return
+			}
+			update.Rule = rTask.Rule
+			update.Task = rTask.Index
+			update.Salience = rTask.Salience
+			update.Source = SourceRemote
+			update.Initiator = wTasks.Initiator
+			update.Transaction = transaction
+			update.Depth = wTasks.Depth
+			update.Trace = span.Context()
+			updates = appendNonempty(updates, update)
+			m.lockMemory.RUnlock()
 		}
-		updates = appendNonempty(updates, update)
-		m.lockMemory.RUnlock()
 	}
 	if len(updates) == 0 {
 		if m.coordinator.confirmRead(k) {
 			m.coordinator.closeRead(k)
+			outcome = "not_interested"
 			commandsCh <- "not_interested"
 		} else {
 			m.coordinator.closeRead(k)
@@ -159,6 +246,9 @@ func (m *Executer) serveTransaction(actionsCh <-chan []byte, commandsCh chan str
 	switch <-commandsCh {
 	case "do_commit":
 		ok = true
+		outcome = "committed"
+		// the updates are persisted before acknowledging
+		m.persistEnqueued(m.register(updates))
 		fallthrough
 	case "do_abort":
 		m.coordinator.closeRead(k)
@@ -183,17 +273,26 @@ func (m *Executer) startUpdateReceiver() chan<- preparedUpdates {
 			select {
 			case ok := <-confirm:
 				if ok {
+					m.persistEnqueued(m.register(queue[0].updates))
 					m.lockPool.Lock()
 					m.pool = append(m.pool, queue[0].updates...)
 					m.lockPool.Unlock()
+					m.metrics.enqueued.Add(float64(len(queue[0].updates)))
+					if len(queue[0].updates) > 0 {
+						m.signalPool()
+					}
 					m.logger.Info(fmt.Sprintf("Added %d updates to the pool", len(queue[0].updates)),
 						zap.String("act", "add_updates"),
-						zapUpdates("updates", queue[0].updates))
+						zapUpdates("updates", queue[0].updates),
+						zapOrigins("origins", queue[0].updates))
 				}
 				confirm <- ok
 				queue = queue[1:]
 			case u := <-updates:
 				queue = append(queue, u)
+			case reply := <-m.quitUpdates:
+				reply <- true
+				return
 			}
 			if len(queue) == 0 {
 				confirm = nil
@@ -0,0 +1,54 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"time"
+
+	"github.com/abu-lang/goabu/metrics"
+	"github.com/abu-lang/goabu/stringset"
+)
+
+// executerMetrics groups the metrics of an Executer.
+type executerMetrics struct {
+	enqueued  metrics.Counter
+	executed  metrics.Counter
+	rejected  metrics.Counter
+	waitRead  metrics.Histogram
+	waitWrite metrics.Histogram
+}
+
+// registerMetrics creates the metrics of m by means of reg.
+func (m *Executer) registerMetrics(reg metrics.Registry) {
+	reg.GaugeFunc("goabu_executer_pool_length", "Number of updates in the pool.", func() float64 {
+		m.lockPool.Lock()
+		defer m.lockPool.Unlock()
+		return float64(len(m.pool))
+	})
+	m.metrics = executerMetrics{
+		enqueued: reg.Counter("goabu_executer_updates_enqueued_total", "Updates added to the pool."),
+		executed: reg.Counter("goabu_executer_updates_executed_total", "Updates executed."),
+		rejected: reg.Counter("goabu_executer_updates_rejected_total",
+			"Updates and inputs discarded because they would violate the invariants."),
+		waitRead: reg.Histogram("goabu_executer_coordinator_wait_seconds",
+			"Time spent waiting for access to the resources.", "access", "read"),
+		waitWrite: reg.Histogram("goabu_executer_coordinator_wait_seconds",
+			"Time spent waiting for access to the resources.", "access", "write"),
+	}
+}
+
+// requestWrite calls the requestWrite method of m.coordinator, recording the wait time.
+func (m *Executer) requestWrite(optimistic bool) {
+	start := time.Now()
+	m.coordinator.requestWrite(optimistic)
+	m.metrics.waitWrite.Observe(time.Since(start).Seconds())
+}
+
+// requestRead calls the requestRead method of m.coordinator, recording the wait time.
+func (m *Executer) requestRead(workingSet stringset.Set) key {
+	start := time.Now()
+	res := m.coordinator.requestRead(workingSet)
+	m.metrics.waitRead.Observe(time.Since(start).Seconds())
+	return res
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/metrics"
+)
+
+func TestExecuterMetrics(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["level"] = 0
+	memory.Integer["pump"] = 0
+	rules := []string{
+		"rule fill on level for level > 5 do pump = level * 10",
+		"rule drain on level for level > 5 do level = 0",
+	}
+	reg := metrics.NewPrometheusRegistry()
+	e, err := NewExecuterAdvanced(memory, rules, MakeMockAgent(), config.TestsLogConfig,
+		&ExecuterConfig{Metrics: reg}, "pump < 100")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	if err = e.Input("level = 12"); err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Input("pump = 200")
+	server := httptest.NewServer(reg)
+	defer server.Close()
+	scrape := func() string {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return string(body)
+	}
+	check := func(body string, samples ...string) {
+		t.Helper()
+		for _, sample := range samples {
+			if !strings.Contains(body, sample+"\n") {
+				t.Errorf("missing sample %q in:\n%s", sample, body)
+			}
+		}
+	}
+	check(scrape(),
+		"goabu_executer_pool_length 2",
+		"goabu_executer_updates_enqueued_total 2",
+		"goabu_executer_updates_executed_total 0",
+		"goabu_executer_updates_rejected_total 1",
+		`goabu_executer_coordinator_wait_seconds_count{access="write"} 2`,
+	)
+	e.Exec()
+	e.Exec()
+	check(scrape(),
+		"goabu_executer_pool_length 0",
+		"goabu_executer_updates_executed_total 1",
+		"goabu_executer_updates_rejected_total 2",
+		`goabu_executer_coordinator_wait_seconds_count{access="write"} 4`,
+	)
+}
@@ -0,0 +1,322 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/persistence"
+	"github.com/abu-lang/goabu/stringset"
+	"github.com/abu-lang/goabu/tracing"
+
+	"go.uber.org/zap"
+)
+
+// NewExecuterFromSnapshot restores an Executer from the last snapshot saved in store and the records
+// appended after it, e.g. after a crash of the node. The resources of mem that are present in the
+// snapshot take their persisted values, the rules, the invariants and the pool of pending updates
+// are the persisted ones. The restored Executer keeps on persisting its state by means of store,
+// beginning with a new snapshot, and signals every resource as modified to the ResourceController.
+//
+// The optional settings are specified by means of cfg as for NewExecuterAdvanced, cfg.Store is ignored.
+func NewExecuterFromSnapshot(
+	mem memory.ResourceController,
+	store persistence.Store,
+	agt Agent,
+	lc config.LogConfig,
+	cfg *ExecuterConfig,
+) (*Executer, error) {
+	snapshot, records, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	settings := ExecuterConfig{}
+	if cfg != nil {
+		settings = *cfg
+	}
+	settings.Store = store
+	rules := snapshot.Rules
+	invariants := snapshot.Invariants
+	for _, r := range records {
+		switch r.Kind {
+		case persistence.RecordRules:
+			rules = r.Rules
+		case persistence.RecordInvariants:
+			invariants = r.Invariants
+		}
+	}
+	sources := make([]string, 0, len(rules))
+	for _, r := range rules {
+		sources = append(sources, r.Source)
+	}
+	mem.Enclose(snapshot.Resources.Extract(mem.ResourceNames()))
+	res, err := newExecuter(mem, sources, agt, lc, &settings)
+	if err != nil {
+		return nil, err
+	}
+	for _, inv := range invariants {
+		err = res.addInvariant(inv.Name, inv.Source)
+		if err != nil {
+			return nil, err
+		}
+		if inv.Repair != "" {
+			err = res.setInvariantRepair(inv.Name, inv.Repair)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, r := range rules {
+		if !r.Enabled {
+			res.disabledRules.Insert(r.Name)
+		}
+	}
+	err = res.replay(snapshot.Pool, records)
+	if err != nil {
+		return nil, err
+	}
+	err = res.start(mem, &settings)
+	if err != nil {
+		return nil, err
+	}
+	res.lockMemory.Lock()
+	res.signalModified(stringset.Make(res.memory.ResourceNames()...))
+	res.lockMemory.Unlock()
+	res.logger.Info(fmt.Sprintf("Restored executer from snapshot with %d records", len(records)),
+		zap.String("act", "restore"),
+		zapUpdates("pool", res.pool))
+	return res, nil
+}
+
+// Snapshot durably stores the current state of the Executer: the values of its resources, the updates
+// in its pool, its rules along with whether they are enabled and its invariants. No update is executed
+// nor received while the snapshot is taken. Snapshot returns an error if no Store was specified upon
+// construction.
+func (m *Executer) Snapshot() error {
+	if m.store == nil {
+		return errors.New("no persistence store was specified")
+	}
+	m.requestWrite(false)
+	defer m.coordinator.closeWrite()
+	// waits for the received transactions in progress, which persist their updates before terminating
+	m.coordinator.fixWorkingSetWrite(stringset.Make(m.memory.ResourceNames()...))
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	lock := make(chan bool)
+	m.updateReceiver <- preparedUpdates{confirm: lock}
+	lock <- false // no updates are added
+	pool := make([]persistence.Update, 0, len(m.pool))
+	for _, update := range m.pool {
+		pool = append(pool, persistentUpdate(update))
+	}
+	m.lockMemory.RLock()
+	snapshot := persistence.Snapshot{
+		Resources:  m.memory.Copy().GetResources(),
+		Pool:       pool,
+		Rules:      m.persistentRules(),
+		Invariants: m.persistentInvariants(),
+	}
+	m.lockMemory.RUnlock()
+	err := m.store.SaveSnapshot(snapshot)
+	<-lock
+	if err != nil {
+		m.logger.Error("Could not save snapshot: "+err.Error(), zap.String("act", "snapshot"))
+		return err
+	}
+	m.logger.Debug("Saved snapshot", zap.String("act", "snapshot"), zap.Int("pool", len(pool)))
+	return nil
+}
+
+// replay restores the pool of m from the updates of a snapshot and performs the operations described
+// by the subsequent records.
+func (m *Executer) replay(pool []persistence.Update, records []persistence.Record) error {
+	for _, u := range pool {
+		update, err := m.restoreUpdate(u)
+		if err != nil {
+			return err
+		}
+		m.pool = append(m.pool, update)
+	}
+	for _, r := range records {
+		switch r.Kind {
+		case persistence.RecordEnqueued:
+			for _, u := range r.Updates {
+				update, err := m.restoreUpdate(u)
+				if err != nil {
+					return err
+				}
+				m.pool = append(m.pool, update)
+			}
+		case persistence.RecordApplied:
+			if len(r.Updates) != 1 {
+				return fmt.Errorf("record #%d: expected a single applied update", r.Sequence)
+			}
+			update, err := m.restoreUpdate(r.Updates[0])
+			if err != nil {
+				return err
+			}
+			if update.id != 0 {
+				for i, u := range m.pool {
+					if u.id == update.id {
+						m.removeUpdate(i)
+						break
+					}
+				}
+			}
+			m.lockMemory.Lock()
+			_, _, err = m.applyUpdate(update)
+			m.lockMemory.Unlock()
+			if err != nil {
+				return fmt.Errorf("record #%d: %w", r.Sequence, err)
+			}
+		}
+	}
+	for _, u := range m.pool {
+		if u.id > m.lastUpdateID.Load() {
+			m.lastUpdateID.Store(u.id)
+		}
+	}
+	return nil
+}
+
+// register assigns a new id and the current time of m.clock as enqueue time to the updates that do not
+// have an id and returns them.
+func (m *Executer) register(updates []Update) []Update {
+	var res []Update
+	now := m.clock.Now()
+	for i := range updates {
+		if updates[i].id == 0 {
+			updates[i].id = m.lastUpdateID.Add(1)
+			updates[i].Enqueued = now
+			res = append(res, updates[i])
+		}
+	}
+	return res
+}
+
+// restoreUpdate constructs the Update represented by u.
+func (m *Executer) restoreUpdate(u persistence.Update) (Update, error) {
+	res := Update{
+		Assignments: make([]Assignment, 0, len(u.Assignments)),
+		Rule:        u.Rule,
+		Task:        u.Task,
+		Salience:    u.Salience,
+		Source:      ChangeSource(u.Source),
+		Initiator:   u.Initiator,
+		Transaction: u.Transaction,
+		Enqueued:    u.Enqueued,
+		Depth:       u.Depth,
+		Trace:       tracing.SpanContext{TraceID: u.TraceID, SpanID: u.SpanID},
+		id:          u.ID,
+	}
+	for _, a := range u.Assignments {
+		variable, present := m.variables[a.Resource]
+		if !present {
+			return Update{}, fmt.Errorf("no resource named %s", a.Resource)
+		}
+		res.Assignments = append(res.Assignments, Assignment{
+			Resource: a.Resource,
+			variable: variable,
+			Value:    reflect.ValueOf(a.Value),
+		})
+	}
+	return res, nil
+}
+
+// persist appends r to the log of m.store, if any.
+func (m *Executer) persist(r persistence.Record) {
+	if m.store == nil {
+		return
+	}
+	err := m.store.Append(r)
+	if err != nil {
+		m.logger.Error("Could not persist record: "+err.Error(),
+			zap.String("act", "persist"),
+			zap.Int("kind", int(r.Kind)))
+	}
+}
+
+// persistEnqueued records the addition of updates to the pool.
+func (m *Executer) persistEnqueued(updates []Update) {
+	if m.store == nil || len(updates) == 0 {
+		return
+	}
+	r := persistence.Record{Kind: persistence.RecordEnqueued}
+	for _, u := range updates {
+		r.Updates = append(r.Updates, persistentUpdate(u))
+	}
+	m.persist(r)
+}
+
+// persistApplied records the execution of the update with the given id (0 for the inputs)
+// which resulted in changes.
+func (m *Executer) persistApplied(id uint64, changes []ResourceChange) {
+	if m.store == nil || (id == 0 && len(changes) == 0) {
+		return
+	}
+	applied := persistence.Update{ID: id}
+	for _, c := range changes {
+		applied.Assignments = append(applied.Assignments, persistence.Assignment{Resource: c.Resource, Value: c.New})
+	}
+	m.persist(persistence.Record{Kind: persistence.RecordApplied, Updates: []persistence.Update{applied}})
+}
+
+// persistRules records the current rules. It should be called while holding m.lockRules.
+func (m *Executer) persistRules() {
+	if m.store == nil {
+		return
+	}
+	m.persist(persistence.Record{Kind: persistence.RecordRules, Rules: m.persistentRules()})
+}
+
+// persistentRules returns the durable representation of the rules of m sorted by name.
+// It should be called while holding m.lockRules.
+func (m *Executer) persistentRules() []persistence.Rule {
+	var res []persistence.Rule
+	added := stringset.Make()
+	for _, d := range m.ruleLibrary {
+		for name, rule := range d {
+			if added.Has(name) {
+				continue
+			}
+			added.Insert(name)
+			res = append(res, persistence.Rule{
+				Name:    name,
+				Source:  rule.Source,
+				Enabled: !m.disabledRules.Has(name),
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// persistentUpdate returns the durable representation of u.
+func persistentUpdate(u Update) persistence.Update {
+	res := persistence.Update{
+		ID:          u.id,
+		Assignments: make([]persistence.Assignment, 0, len(u.Assignments)),
+		Rule:        u.Rule,
+		Task:        u.Task,
+		Salience:    u.Salience,
+		Source:      int(u.Source),
+		Initiator:   u.Initiator,
+		Transaction: u.Transaction,
+		Enqueued:    u.Enqueued,
+		Depth:       u.Depth,
+		TraceID:     u.Trace.TraceID,
+		SpanID:      u.Trace.SpanID,
+	}
+	for _, a := range u.Assignments {
+		res.Assignments = append(res.Assignments, persistence.Assignment{Resource: a.Resource, Value: a.Value.Interface()})
+	}
+	return res
+}
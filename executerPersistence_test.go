@@ -0,0 +1,158 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/persistence"
+)
+
+func persistenceResources() memory.Resources {
+	res := memory.MakeResources()
+	res.Integer["foo"] = 0
+	res.Integer["bar"] = 0
+	res.Integer["baz"] = 0
+	return res
+}
+
+var persistenceRules = []string{
+	"rule inc on foo for true do bar = foo + 1",
+	"rule dbl on bar for true do baz = bar * 2",
+}
+
+// newPersistentExecuter creates an Executer persisting its state in dir.
+func newPersistentExecuter(t *testing.T, dir string) *Executer {
+	store, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { store.Close() })
+	e, err := NewExecuterAdvanced(persistenceResources(), persistenceRules, MakeMockAgent(), config.TestsLogConfig,
+		&ExecuterConfig{Store: store}, "baz < 100")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	return e
+}
+
+// restoreExecuter creates an Executer from the state persisted in dir.
+func restoreExecuter(t *testing.T, dir string) *Executer {
+	store, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { store.Close() })
+	e, err := NewExecuterFromSnapshot(persistenceResources(), store, MakeMockAgent(), config.TestsLogConfig, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+// checkState fails t if the resources of e are different from foo, bar and baz
+// or if the pool of e does not contain pool updates.
+func checkState(t *testing.T, e *Executer, foo, bar, baz int64, pool int) {
+	t.Helper()
+	mem, updates := e.TakeState()
+	if mem.Integer["foo"] != foo || mem.Integer["bar"] != bar || mem.Integer["baz"] != baz {
+		t.Errorf("expected foo = %d, bar = %d, baz = %d, got %v", foo, bar, baz, mem)
+	}
+	if len(updates) != pool {
+		t.Errorf("expected %d updates in the pool, got %v", pool, updates)
+	}
+}
+
+func TestCrashRecovery(t *testing.T) {
+	dir := t.TempDir()
+	e := newPersistentExecuter(t, dir)
+	err := e.Input("foo = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	err = e.AddRules("rule reset on baz for true do foo = 0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.SetRuleEnabled("reset", false)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.AddInvariant("bounded", "bar < 1000")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	checkState(t, e, 1, 2, 0, 1)
+	// no snapshot is taken by Close: the restored state comes from the log
+	e.Close()
+
+	r := restoreExecuter(t, dir)
+	checkState(t, r, 1, 2, 0, 1)
+	if !r.HasRule("reset") || r.IsRuleEnabled("reset") || !r.IsRuleEnabled("dbl") {
+		t.Error("rules and their enabled state should be restored")
+	}
+	if invs := r.Invariants(); len(invs) != 2 || invs["#0"] != "baz < 100" || invs["bounded"] != "bar < 1000" {
+		t.Error("invariants should be restored, got", invs)
+	}
+	_, pool := r.TakeState()
+	if len(pool) > 0 && (pool[0].Rule != "dbl" || pool[0].Source != SourceLocal || pool[0].Initiator != "mock" ||
+		pool[0].Enqueued.IsZero()) {
+		t.Error("pending update should keep its origin, got", pool[0])
+	}
+	r.Exec()
+	checkState(t, r, 1, 2, 4, 0)
+	err = r.Input("foo = 60")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for !r.DoIfStable(func() {}) {
+		r.Exec()
+	}
+	// baz = 122 would violate the invariant
+	checkState(t, r, 60, 61, 4, 0)
+}
+
+func TestTornLogRecovery(t *testing.T) {
+	dir := t.TempDir()
+	e := newPersistentExecuter(t, dir)
+	err := e.Input("foo = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Snapshot()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	checkState(t, e, 1, 2, 0, 1)
+	e.Close()
+	// the last record, describing the execution of the update of inc, was only partially written
+	// so the update is executed again after the update it produced was added to the pool
+	path := filepath.Join(dir, "wal")
+	wal, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = os.WriteFile(path, wal[:len(wal)-1], 0o644)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	r := restoreExecuter(t, dir)
+	checkState(t, r, 1, 0, 0, 2)
+	for !r.DoIfStable(func() {}) {
+		r.Exec()
+	}
+	checkState(t, r, 1, 2, 4, 0)
+}
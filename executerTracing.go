@@ -0,0 +1,34 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import "github.com/abu-lang/goabu/tracing"
+
+// forAll sends payload to the other nodes by means of the Agent of m, the transaction is caused by
+// the span identified by parent if the Agent is a [TracingAgent].
+func (m *Executer) forAll(payload []byte, parent tracing.SpanContext) error {
+	if agt, ok := m.agent.(TracingAgent); ok {
+		return agt.ForAllTraced(payload, parent)
+	}
+	return m.agent.ForAll(payload)
+}
+
+// updateAttributes returns the attributes describing the provenance of u.
+func updateAttributes(u Update) []tracing.Attribute {
+	res := []tracing.Attribute{
+		tracing.String("goabu.update", u.String()),
+		tracing.String("goabu.source", u.Source.String()),
+		tracing.Int("goabu.depth", u.Depth),
+	}
+	if u.Rule != "" {
+		res = append(res, tracing.String("goabu.rule", u.Rule), tracing.Int("goabu.task", u.Task))
+	}
+	if u.Initiator != "" {
+		res = append(res, tracing.String("goabu.initiator", u.Initiator))
+	}
+	if u.Transaction != "" {
+		res = append(res, tracing.String("goabu.transaction", u.Transaction))
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/tracing"
+)
+
+// spanRecorder is a tracing.Exporter keeping the exported spans.
+type spanRecorder struct {
+	spans []tracing.SpanData
+	lock  sync.Mutex
+}
+
+func (r *spanRecorder) Export(s tracing.SpanData) error {
+	r.lock.Lock()
+	r.spans = append(r.spans, s)
+	r.lock.Unlock()
+	return nil
+}
+
+// named returns the only exported span with the given name.
+func (r *spanRecorder) named(t *testing.T, name string) tracing.SpanData {
+	t.Helper()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var res []tracing.SpanData
+	for _, s := range r.spans {
+		if s.Name == name {
+			res = append(res, s)
+		}
+	}
+	if len(res) != 1 {
+		t.Fatalf("expected a span named %s, got %d", name, len(res))
+	}
+	return res[0]
+}
+
+func attribute(s tracing.SpanData, key string) any {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["level"] = 0
+	memory.Integer["pump"] = 0
+	memory.Bool["alarm"] = false
+	rules := []string{
+		"rule fill on level for level > 5 do pump = level * 10",
+		"rule notify on pump for all ext.alarm == false do ext.alarm = true",
+	}
+	rec := &spanRecorder{}
+	e, err := NewExecuterAdvanced(memory, rules, MakeMockAgent(), config.TestsLogConfig,
+		&ExecuterConfig{Tracer: tracing.NewTracer("node", rec)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	if err = e.Input("level = 12"); err != nil {
+		t.Fatal(err.Error())
+	}
+	input := rec.named(t, "input")
+	if input.Parent.IsValid() || input.Service != "node" || attribute(input, "goabu.input") != "level = 12" {
+		t.Errorf("unexpected input span: %+v", input)
+	}
+	e.lockPool.Lock()
+	if len(e.pool) != 1 || e.pool[0].Trace != input.SpanContext {
+		t.Errorf("the update of fill should be caused by the input: %+v", e.pool)
+	}
+	e.lockPool.Unlock()
+	e.Exec()
+	// the update of notify is added to the pool asynchronously
+	for e.DoIfStable(func() {}) {
+	}
+	e.Exec()
+	if err = e.Set("level", int64(20)); err != nil {
+		t.Fatal(err.Error())
+	}
+	e.lockPool.Lock()
+	if len(e.pool) != 1 || e.pool[0].Trace.TraceID == input.TraceID || !e.pool[0].Trace.IsValid() {
+		t.Errorf("each input should start a new trace: %+v", e.pool)
+	}
+	e.lockPool.Unlock()
+
+	// waits for the spans of the received transaction
+	e.Close()
+
+	checkParent := func(s, parent tracing.SpanData) {
+		t.Helper()
+		if s.TraceID != input.TraceID || s.Parent != parent.SpanID {
+			t.Errorf("span %s should be a child of %s", s.Name, parent.Name)
+		}
+	}
+	rec.lock.Lock()
+	var execs []tracing.SpanData
+	for _, s := range rec.spans {
+		if s.Name == "exec" {
+			execs = append(execs, s)
+		}
+	}
+	rec.lock.Unlock()
+	if len(execs) != 2 {
+		t.Fatalf("expected 2 exec spans, got %d", len(execs))
+	}
+	fill, alarm := execs[0], execs[1]
+	if attribute(fill, "goabu.rule") != "fill" {
+		fill, alarm = alarm, fill
+	}
+	checkParent(fill, input)
+	if attribute(fill, "outcome") != "executed" || attribute(fill, "goabu.source") != "local" {
+		t.Errorf("unexpected exec span: %+v", fill)
+	}
+	send := rec.named(t, "send")
+	checkParent(send, fill)
+	receive := rec.named(t, "receive")
+	checkParent(receive, send)
+	if attribute(receive, "outcome") != "committed" || attribute(receive, "goabu.transaction") != "mock->0" {
+		t.Errorf("unexpected receive span: %+v", receive)
+	}
+	checkParent(alarm, receive)
+	if attribute(alarm, "goabu.rule") != "notify" || attribute(alarm, "goabu.source") != "remote" {
+		t.Errorf("unexpected exec span: %+v", alarm)
+	}
+}
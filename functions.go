@@ -0,0 +1,223 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/abu-lang/goabu/parser"
+
+	"github.com/hyperjumptech/grule-rule-engine/model"
+	"go.uber.org/zap"
+)
+
+// builtinNames contains the names of the built-in functions (see builtinFunctions).
+var builtinNames = func() map[string]bool {
+	res := make(map[string]bool)
+	t := reflect.TypeOf(builtinFunctions{})
+	for i := 0; i < t.NumMethod(); i++ {
+		res[t.Method(i).Name] = true
+	}
+	return res
+}()
+
+// functionTypes contains the types that the parameters and the results of the registered functions can have,
+// that is the types of the values of the resources.
+var functionTypes = map[reflect.Type]bool{
+	reflect.TypeOf(false):       true,
+	reflect.TypeOf(int64(0)):    true,
+	reflect.TypeOf(float64(0)):  true,
+	reflect.TypeOf(""):          true,
+	reflect.TypeOf(time.Time{}): true,
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// functionTable contains the functions registered by means of RegisterFunction. It implements
+// [parser.Functions] by reporting both the built-in and the registered functions.
+type functionTable struct {
+	functions map[string]reflect.Value
+	lock      sync.RWMutex
+}
+
+func newFunctionTable() *functionTable {
+	return &functionTable{functions: make(map[string]reflect.Value)}
+}
+
+// HasFunction reports whether name is a built-in or a registered function.
+func (t *functionTable) HasFunction(name string) bool {
+	if builtinNames[name] {
+		return true
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	_, present := t.functions[name]
+	return present
+}
+
+// register adds fn to t after validating its signature.
+func (t *functionTable) register(name string, fn any) error {
+	if !parser.ValidateIdentifiers(name)[0] {
+		return fmt.Errorf(`invalid function name: "%s"`, name)
+	}
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return fmt.Errorf("cannot register %s: %T is not a function", name, fn)
+	}
+	typ := f.Type()
+	for i := 0; i < typ.NumIn(); i++ {
+		in := typ.In(i)
+		if typ.IsVariadic() && i == typ.NumIn()-1 {
+			in = in.Elem()
+		}
+		if !functionTypes[in] {
+			return fmt.Errorf("cannot register %s: parameter %d has unsupported type %v", name, i+1, in)
+		}
+	}
+	if typ.NumOut() == 0 || typ.NumOut() > 2 || typ.NumOut() == 2 && typ.Out(1) != errorType {
+		return fmt.Errorf("cannot register %s: it must return a single value, optionally followed by an error", name)
+	}
+	if !functionTypes[typ.Out(0)] {
+		return fmt.Errorf("cannot register %s: result has unsupported type %v", name, typ.Out(0))
+	}
+	if builtinNames[name] {
+		return fmt.Errorf("there is already a built-in function named %s", name)
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, present := t.functions[name]; present {
+		return fmt.Errorf("there is already a function named %s", name)
+	}
+	t.functions[name] = f
+	return nil
+}
+
+// registerAll registers the given functions in the order of their names.
+func (t *functionTable) registerAll(functions map[string]any) error {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err := t.register(name, functions[name])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registered returns the registered functions.
+func (t *functionTable) registered() map[string]any {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	res := make(map[string]any, len(t.functions))
+	for name, f := range t.functions {
+		res[name] = f.Interface()
+	}
+	return res
+}
+
+// call calls the registered function name with args, it returns false if there is no such function.
+func (t *functionTable) call(name string, args []reflect.Value) (reflect.Value, bool, error) {
+	t.lock.RLock()
+	f, present := t.functions[name]
+	t.lock.RUnlock()
+	if !present {
+		return reflect.Value{}, false, nil
+	}
+	res, err := callFunction(name, f, args)
+	return res, true, err
+}
+
+// callFunction calls the function f, registered or built-in as name, with args after checking their types.
+// The integer arguments are converted to float64 for the parameters of type float64.
+func callFunction(name string, f reflect.Value, args []reflect.Value) (res reflect.Value, err error) {
+	typ := f.Type()
+	params := typ.NumIn()
+	if typ.IsVariadic() && len(args) < params-1 {
+		return reflect.Value{}, fmt.Errorf("function %s expects at least %d arguments, got %d", name, params-1, len(args))
+	}
+	if !typ.IsVariadic() && len(args) != params {
+		return reflect.Value{}, fmt.Errorf("function %s expects %d arguments, got %d", name, params, len(args))
+	}
+	in := make([]reflect.Value, 0, len(args))
+	for i, arg := range args {
+		var param reflect.Type
+		if typ.IsVariadic() && i >= params-1 {
+			param = typ.In(params - 1).Elem()
+		} else {
+			param = typ.In(i)
+		}
+		switch {
+		case arg.IsValid() && arg.Type().AssignableTo(param):
+		case arg.IsValid() && arg.Kind() == reflect.Int64 && param.Kind() == reflect.Float64:
+			arg = arg.Convert(param)
+		default:
+			got := "nothing"
+			if arg.IsValid() {
+				got = arg.Type().String()
+			}
+			return reflect.Value{}, fmt.Errorf("argument %d of function %s must be a %v, got %s", i+1, name, param, got)
+		}
+		in = append(in, arg)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("function %s panicked: %v", name, r)
+		}
+	}()
+	out := f.Call(in)
+	if len(out) == 0 {
+		return reflect.Value{}, nil
+	}
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("function %s: %w", name, out[1].Interface().(error))
+	}
+	return out[0], nil
+}
+
+// functionsNode is the [model.ValueNode] of the built-in functions, it dispatches the calls
+// to the registered functions to its functionTable.
+type functionsNode struct {
+	model.ValueNode
+	// builtins is the builtinFunctions of the node.
+	builtins  reflect.Value
+	functions *functionTable
+}
+
+// CallFunction calls the built-in or registered function named funcName.
+func (n functionsNode) CallFunction(funcName string, args ...reflect.Value) (reflect.Value, error) {
+	res, present, err := n.functions.call(funcName, args)
+	if present {
+		return res, err
+	}
+	if f := n.builtins.MethodByName(funcName); f.IsValid() {
+		return callFunction(funcName, f, args)
+	}
+	return n.ValueNode.CallFunction(funcName, args...)
+}
+
+// RegisterFunction makes fn callable by name in the conditions and in the actions of the rules and
+// in the invariants. The parameters and the result of fn must have the types of the values of the
+// resources: bool, int64, float64, string or time.Time; fn can be variadic and can also return an
+// error, which makes the evaluation calling it fail. Integer arguments are accepted for the float64
+// parameters. The name must be a valid identifier not used by a built-in or registered function.
+//
+// The rules calling a function that is neither built-in nor registered are rejected, the same holds
+// for the tasks received from the other nodes: the nodes receiving the remote tasks of a rule should
+// register the functions called by them.
+func (m *Executer) RegisterFunction(name string, fn any) error {
+	err := m.functions.register(name, fn)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("Registered function "+name, zap.String("act", "register_function"), zap.String("obj", name))
+	return nil
+}
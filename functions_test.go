@@ -0,0 +1,144 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestRegisterFunction(t *testing.T) {
+	mem := memory.MakeResources()
+	mem.Float["celsius"] = 0
+	mem.Float["fahrenheit"] = 0
+	mem.Text["label"] = ""
+	e, err := NewExecuter(mem, nil, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	invalid := []struct {
+		name string
+		fn   any
+	}{
+		{"Broken", 42},
+		{"Broken", (func(float64) float64)(nil)},
+		{"Broken", func(int) float64 { return 0 }},
+		{"Broken", func(float64) {}},
+		{"Broken", func(float64) (float64, float64) { return 0, 0 }},
+		{"Broken", func(float64) []float64 { return nil }},
+		{"Broken", func(...int) int64 { return 0 }},
+		{"AbsInt", func(int64) int64 { return 0 }},
+		{"for", func(int64) int64 { return 0 }},
+		{"not valid", func(int64) int64 { return 0 }},
+	}
+	for _, test := range invalid {
+		if e.RegisterFunction(test.name, test.fn) == nil {
+			t.Errorf("registering %s as %T should be an error", test.name, test.fn)
+		}
+	}
+	err = e.AddRules("rule convert on celsius for true do fahrenheit = ToFahrenheit(celsius)")
+	if err == nil || !strings.Contains(err.Error(), "undefined function ToFahrenheit") {
+		t.Error("calling an unregistered function should be an error, got", err)
+	}
+	err = e.RegisterFunction("ToFahrenheit", func(c float64) float64 { return c*9/5 + 32 })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.RegisterFunction("ToFahrenheit", func(c float64) float64 { return c }) == nil {
+		t.Error("registering a function twice should be an error")
+	}
+	err = e.RegisterFunction("Join", func(sep string, parts ...string) string { return strings.Join(parts, sep) })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.AddRules(
+		"rule convert on celsius for ToFahrenheit(celsius) != fahrenheit do fahrenheit = ToFahrenheit(celsius)",
+		`rule describe on fahrenheit for true do label = Join(" ", "warm", "water")`,
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// the integer argument is accepted for the float64 parameter
+	err = e.AddInvariant("boiling", "ToFahrenheit(celsius) <= ToFahrenheit(100)")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input("celsius = 40.0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+		e.Exec()
+	}
+	if mem, _ := e.TakeState(); mem.Float["fahrenheit"] != 104 || mem.Text["label"] != "warm water" {
+		t.Error("unexpected state:", mem)
+	}
+	if e.Input("celsius = 101.0") == nil {
+		t.Error("the input violating the invariant should be rejected")
+	}
+	if mem, _ := e.TakeState(); mem.Float["celsius"] != 40 {
+		t.Error("the input violating the invariant should be rejected, got", mem)
+	}
+}
+
+func TestFunctionErrors(t *testing.T) {
+	mem := memory.MakeResources()
+	mem.Integer["x"] = 0
+	mem.Integer["y"] = 0
+	cfg := &ExecuterConfig{Functions: map[string]any{
+		"Checked": func(x int64) (int64, error) {
+			if x < 0 {
+				return 0, errors.New("negative argument")
+			}
+			return x * 2, nil
+		},
+	}}
+	rules := []string{
+		"rule checked on x for true do y = Checked(x)",
+		// the receiving node has not registered Remote
+		"rule remote on y for all ext.y > 0 do ext.x = Remote(ext.y)",
+	}
+	e, err := NewExecuterAdvanced(mem, rules, MakeMockAgent(), config.TestsLogConfig, cfg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	errs := e.Errors()
+	err = e.Input("x = -1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if evalErr := receiveError(t, errs); evalErr.Step != StepTask || evalErr.Rule != "checked" ||
+		!strings.Contains(evalErr.Error(), "negative argument") {
+		t.Error("unexpected error:", evalErr)
+	}
+	err = e.Input("x = 3")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	evalErr := receiveError(t, errs)
+	if evalErr.Step != StepReceivedTask || evalErr.Rule != "remote" ||
+		!strings.Contains(evalErr.Error(), "undefined function Remote") {
+		t.Error("unexpected error:", evalErr)
+	}
+	if mem, _ := e.TakeState(); mem.Integer["x"] != 3 || mem.Integer["y"] != 6 {
+		t.Error("unexpected state:", mem)
+	}
+	select {
+	case err := <-errs:
+		t.Error("unexpected error:", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
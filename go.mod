@@ -9,8 +9,10 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/hashicorp/memberlist v0.5.1
 	github.com/hyperjumptech/grule-rule-engine v1.15.0
+	github.com/pelletier/go-toml v1.9.5
 	go.uber.org/zap v1.27.0
 	gobot.io/x/gobot/v2 v2.2.0
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/apimachinery v0.30.1
 )
 
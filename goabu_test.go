@@ -4,13 +4,17 @@
 package goabu
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/abu-lang/goabu/config"
 	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/parser"
 )
 
 var Optimistic = flag.Bool("opt", false, "set optimistic concurrency control")
@@ -65,6 +69,45 @@ func TestNewExecuter(t *testing.T) {
 	}
 }
 
+func TestTakeStateCopies(t *testing.T) {
+	mem := memory.MakeResources()
+	mem.Other["settings"] = map[string]int{"speed": 1}
+	mem.List["jobs"] = []interface{}{"build"}
+	mem.Integer["x"] = 0
+	e, err := NewExecuter(mem, []string{"rule copy on x for true do jobs = Append(jobs, \"test\")"},
+		MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	mem.Other["settings"].(map[string]int)["speed"] = 2
+	state, _ := e.TakeState()
+	state.Other["settings"].(map[string]int)["speed"] = 3
+	state.List["jobs"][0] = "deploy"
+	settings := map[string]int{"speed": 4}
+	err = e.Set("settings", settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	settings["speed"] = 5
+	err = e.Input("x = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, pool := e.TakeState()
+	if len(pool) != 1 {
+		t.Fatal("expected an update in the pool, got", pool)
+	}
+	pool[0].Assignments[0].Value.Interface().([]interface{})[0] = "deploy"
+	e.Exec()
+	state, _ = e.TakeState()
+	if state.Other["settings"].(map[string]int)["speed"] != 4 || state.List["jobs"][0] != "build" || len(state.List["jobs"]) != 2 {
+		t.Error("modifying the values outside the node should not affect it, got", state)
+	}
+}
+
 func TestAddRules(t *testing.T) {
 	local := `rule local on trigger executed
 		for !this.executed do
@@ -103,6 +146,208 @@ func TestAddRules(t *testing.T) {
 	}
 }
 
+func TestParseErrors(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["level"] = 0
+	memory.Text["label"] = ""
+	e, err := NewExecuter(memory, nil, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.AddRules(
+		"rule valid on level for level > 0 do label = \"positive\"",
+		"rule typo on level for true do lvel = 0",
+		"rule typed on level for true do level = label",
+	)
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatal("the errors of all the rules should be returned, got", err)
+	}
+	var parseErr *parser.Error
+	if !errors.As(joined.Unwrap()[0], &parseErr) || parseErr.Rule != 1 || parseErr.Category != parser.CategoryUnknownResource ||
+		len(parseErr.Suggestions) != 1 || parseErr.Suggestions[0] != "level" {
+		t.Error("unexpected error:", joined.Unwrap()[0])
+	}
+	if !errors.As(joined.Unwrap()[1], &parseErr) || parseErr.Rule != 2 || parseErr.Category != parser.CategoryTypeMismatch {
+		t.Error("unexpected error:", joined.Unwrap()[1])
+	}
+	if len(e.ruleLibrary) != 0 {
+		t.Error("no rule should be added, got", e.ruleLibrary)
+	}
+	if !errors.As(e.Input("level = \"high\""), &parseErr) || parseErr.Rule != -1 || parseErr.Category != parser.CategoryTypeMismatch {
+		t.Error("the input should be rejected with a type mismatch")
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	memory.Integer["baz"] = 0
+	rules := []string{
+		"rule r1 on foo bar for true do baz = foo + bar",
+		"rule r2 on foo for true do bar = foo",
+	}
+	e, err := NewExecuter(memory, rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.RemoveRule("r1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.HasRule("r1") || !e.HasRule("r2") {
+		t.Error("only r2 should be present")
+	}
+	if len(e.ruleLibrary) != 1 || len(e.ruleLibrary["foo"]) != 1 {
+		t.Error("r1 should be removed from every event index")
+	}
+	if e.RemoveRule("r1") == nil {
+		t.Error("removing an absent rule should be an error")
+	}
+	err = e.RemoveRule("r2")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(e.ruleLibrary) != 0 {
+		t.Error("ruleLibrary should be empty")
+	}
+	err = e.AddRules(rules[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !e.HasRule("r1") {
+		t.Error("r1 should be present")
+	}
+}
+
+func TestReplaceRule(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	memory.Integer["baz"] = 0
+	e, err := NewExecuter(memory, []string{"rule r on foo bar for true do baz = foo + bar"}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	err = e.ReplaceRule("rule r salience 3 on bar for true do baz = bar * 10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(e.ruleLibrary) != 1 || !e.ruleLibrary["bar"].Has("r") || e.ruleLibrary["bar"]["r"].Salience != 3 {
+		t.Error("r should have been replaced")
+	}
+	if e.ReplaceRule("rule other on foo for true do baz = 1") == nil {
+		t.Error("replacing an absent rule should be an error")
+	}
+	if e.ReplaceRule("rule r on foo for true do baz = 1 rule s on foo for true do baz = 2") == nil {
+		t.Error("replacing with multiple rules should be an error")
+	}
+	if e.ReplaceRule("rule r on foo for do baz = 1") == nil {
+		t.Error("replacing with an invalid rule should be an error")
+	}
+	if e.HasRule("other") || e.HasRule("s") || !e.ruleLibrary["bar"].Has("r") {
+		t.Error("failed replacements should not modify the rules")
+	}
+	e.addActions("foo = 1, bar = 2")
+	for !e.DoIfStable(func() {}) {
+		e.Exec()
+	}
+	mem, _ := e.TakeState()
+	if mem.Integer["baz"] != 20 {
+		t.Error("baz should be 20, got", mem.Integer["baz"])
+	}
+}
+
+func TestSetRuleEnabled(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	e, err := NewExecuter(memory, []string{"rule r on foo for true do bar = bar + 1"}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	if e.SetRuleEnabled("other", false) == nil {
+		t.Error("disabling an absent rule should be an error")
+	}
+	err = e.SetRuleEnabled("r", false)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.IsRuleEnabled("r") || !e.HasRule("r") {
+		t.Error("r should be present but disabled")
+	}
+	e.addActions("foo = 1")
+	e.Exec()
+	if !e.DoIfStable(func() {}) {
+		t.Error("disabled rules should not be activated")
+	}
+	err = e.ReplaceRule("rule r on foo for true do bar = bar + 2")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.IsRuleEnabled("r") {
+		t.Error("replaced rule should still be disabled")
+	}
+	err = e.SetRuleEnabled("r", true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.addActions("foo = 2")
+	for !e.DoIfStable(func() {}) {
+		e.Exec()
+	}
+	mem, _ := e.TakeState()
+	if mem.Integer["bar"] != 2 {
+		t.Error("bar should be 2, got", mem.Integer["bar"])
+	}
+}
+
+func TestConcurrentRuleChanges(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	e, err := NewExecuter(memory, []string{"rule r on foo for true do bar = foo"}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 50; i++ {
+			if e.ReplaceRule(fmt.Sprintf("rule r on foo for true do bar = foo + %d", i)) != nil {
+				t.Error("could not replace r")
+			}
+			if e.SetRuleEnabled("r", i%2 == 0) != nil {
+				t.Error("could not enable/disable r")
+			}
+		}
+		done <- true
+	}()
+	for i := 1; i <= 50; i++ {
+		err = e.Input(fmt.Sprintf("foo = %d", i))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for !e.DoIfStable(func() {}) {
+			e.Exec()
+		}
+	}
+	<-done
+	err = e.RemoveRule("r")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.HasRule("r") || len(e.ruleLibrary) != 0 {
+		t.Error("r should be removed")
+	}
+}
+
 func TestAddPool(t *testing.T) {
 	memory := memory.MakeResources()
 	memory.Float["elit"] = 5.0
@@ -328,6 +573,53 @@ func TestTwoTasks(t *testing.T) {
 	}
 }
 
+func TestProvenance(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Bool["start"] = false
+	memory.Integer["a"] = 0
+	memory.Integer["b"] = 0
+	e, err := NewExecuter(memory, []string{"rule prov on start for start do a = 1 for all ext.b == 0 do ext.b = 2"},
+		MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	before := time.Now()
+	err = e.Input("start = true")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, pool := e.TakeState()
+	for i := 0; i < 100 && len(pool) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, pool = e.TakeState()
+	}
+	if len(pool) != 2 {
+		t.Fatal("pool should have length 2, got", pool)
+	}
+	var local, remote Update
+	for _, u := range pool {
+		if u.Source == SourceRemote {
+			remote = u
+		} else {
+			local = u
+		}
+	}
+	if local.Source != SourceLocal || local.Rule != "prov" || local.Task != 0 || local.Initiator != "mock" ||
+		local.Transaction != "" {
+		t.Error("unexpected provenance of the local update:", local)
+	}
+	if remote.Rule != "prov" || remote.Task != 0 || remote.Initiator != "mock" || remote.Transaction != "mock->0" {
+		t.Error("unexpected provenance of the remote update:", remote)
+	}
+	for _, u := range pool {
+		if u.Enqueued.Before(before) || u.Enqueued.After(time.Now()) {
+			t.Error("unexpected enqueue time:", u.Enqueued)
+		}
+	}
+}
+
 func TestAbsInt(t *testing.T) {
 	memory := memory.MakeResources()
 	memory.Integer["x"] = -5
@@ -351,3 +643,106 @@ func TestAbsInt(t *testing.T) {
 		t.Error("should be stable")
 	}
 }
+
+// waitGoroutines waits for the number of goroutines to be at most n, it returns false after a timeout.
+func waitGoroutines(n int) bool {
+	for i := 0; i < 100; i++ {
+		if runtime.NumGoroutine() <= n {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestRun(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["counter"] = 0
+	memory.Integer["target"] = 0
+	e, err := NewExecuterAdvanced(memory, []string{"rule count on counter for counter < target do counter = counter + 1"},
+		MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{Pacing: time.Millisecond})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	ctx, cancel := context.WithCancel(context.Background())
+	res := make(chan error)
+	go func() {
+		res <- e.Run(ctx)
+	}()
+	err = e.Input("target = 10, counter = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; ; i++ {
+		mem, _ := e.TakeState()
+		if mem.Integer["counter"] == 10 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Run should have drained the pool")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err = <-res; err != context.Canceled {
+		t.Error("Run should return context.Canceled, got", err)
+	}
+	if !e.DoIfStable(func() {}) {
+		t.Error("should be stable")
+	}
+	err = e.Close()
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	rules := []string{
+		"rule local on foo for true do bar = foo",
+		"rule global on bar for all true do ext.foo = this.bar + 1",
+	}
+	e, err := NewExecuter(memory, rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	res := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func() {
+			res <- e.Run(context.Background())
+		}()
+	}
+	err = e.Input("foo = 1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	time.Sleep(50 * time.Millisecond)
+	err = e.Close()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	for i := 0; i < 2; i++ {
+		if err = <-res; err != ErrClosed {
+			t.Error("Run should return ErrClosed, got", err)
+		}
+	}
+	if e.agent.IsRunning() {
+		t.Error("agent should be stopped")
+	}
+	if e.Close() != ErrClosed {
+		t.Error("closing twice should return ErrClosed")
+	}
+	if e.Run(context.Background()) != ErrClosed {
+		t.Error("Run should return ErrClosed after Close")
+	}
+	if !waitGoroutines(before) {
+		t.Errorf("goroutines leaked: %d before, %d after Close", before, runtime.NumGoroutine())
+	}
+}
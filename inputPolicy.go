@@ -0,0 +1,253 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/stringset"
+
+	"go.uber.org/zap"
+)
+
+// InputPolicy specifies how the Executer handles the inputs that would violate its invariants.
+type InputPolicy int
+
+const (
+	// InputReject rejects the inputs that would violate an invariant: no resource is modified and
+	// an [*InvariantError] is returned.
+	InputReject InputPolicy = iota
+	// InputRepair performs the repair actions of the violated invariants (see SetInvariantRepair)
+	// right after the input, as part of it. The input is rejected as by InputReject if some violated
+	// invariant has no repair actions or if the invariants still do not hold after the repair.
+	InputRepair
+	// InputFlag accepts the inputs anyway.
+	InputFlag
+)
+
+// String returns the name of the policy.
+func (p InputPolicy) String() string {
+	switch p {
+	case InputReject:
+		return "reject"
+	case InputRepair:
+		return "repair"
+	case InputFlag:
+		return "flag"
+	default:
+		return fmt.Sprintf("InputPolicy(%d)", int(p))
+	}
+}
+
+// InvariantError is the error returned by Input, Set and SetMany when an input is rejected because
+// it would violate some invariants.
+type InvariantError struct {
+	// Invariants contains the names of the violated invariants.
+	Invariants []string
+	// Update is the rejected update.
+	Update Update
+}
+
+// Error returns a description of the error.
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("input %v would violate the invariants %s", e.Update, strings.Join(e.Invariants, ", "))
+}
+
+// SetInputPolicy sets the policy used for the inputs that would violate the invariants.
+// Regardless of the policy, an [InvariantViolation] is sent over the channel returned by Violations
+// for each invariant violated by an input that is not repaired.
+func (m *Executer) SetInputPolicy(p InputPolicy) {
+	m.lockMemory.Lock()
+	m.inputPolicy = p
+	m.lockMemory.Unlock()
+}
+
+// SetInvariantRepair sets the actions that repair the state after an input violating the invariant
+// with the given name when the input policy is InputRepair, e.g. "temperature = 90" for the invariant
+// "temperature <= 90". If actions is empty then the repair actions of the invariant are removed.
+func (m *Executer) SetInvariantRepair(name, actions string) error {
+	err := m.setInvariantRepair(name, actions)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("Set repair actions of invariant "+name, zap.String("act", "repair_inv"), zap.String("obj", actions))
+	return nil
+}
+
+// setInvariantRepair parses actions and sets them as the repair actions of the invariant with the given name.
+func (m *Executer) setInvariantRepair(name, actions string) error {
+	var parsed []ecarule.Action
+	if strings.TrimSpace(actions) == "" {
+		actions = ""
+	} else {
+		var err error
+		parsed, err = m.parseActions(actions)
+		if err != nil {
+			return err
+		}
+	}
+	m.lockMemory.Lock()
+	defer m.lockMemory.Unlock()
+	for i := range m.invariants {
+		if m.invariants[i].name == name {
+			m.invariants[i].repair = parsed
+			m.invariants[i].repairSource = actions
+			m.persistInvariants()
+			return nil
+		}
+	}
+	return fmt.Errorf("no invariant named %s", name)
+}
+
+// enforceInvariants checks the invariants after the application of update, the update of an input
+// that modified the resources in modified as described by changes, and handles their violation
+// according to the input policy of m. It returns the resources modified by the input and the
+// description of their changes, including the ones of the repair actions, which can only assign
+// the resources in workingSet. If the input is rejected then the resources are restored by means
+// of previous and an error is returned.
+// It should be called while holding m.lockMemory.
+func (m *Executer) enforceInvariants(
+	update Update,
+	previous memory.Resources,
+	modified stringset.Set,
+	changes []ResourceChange,
+	workingSet stringset.Set,
+) (stringset.Set, []ResourceChange, error) {
+	violated, err := m.violatedInvariants()
+	if err != nil {
+		m.restoreResources(previous, update)
+		return nil, nil, err
+	}
+	if len(violated) == 0 {
+		return modified, changes, nil
+	}
+	assigned := stringset.Make(update.resources()...)
+	after := m.resourceValues(assigned)
+	switch m.inputPolicy {
+	case InputFlag:
+		m.logger.Warn(fmt.Sprintf("Input %v violates the invariants %v", update, violated),
+			zap.String("act", "input"),
+			zapUpdate("update", update),
+			zap.Strings("invariants", violated))
+		m.reportViolations(violated, update, m.valuesIn(previous, assigned), after)
+		return modified, changes, nil
+	case InputRepair:
+		repairChanges, ok := m.repair(violated, workingSet)
+		if ok {
+			m.logger.Info(fmt.Sprintf("Repaired input %v violating the invariants %v", update, violated),
+				zap.String("act", "input"),
+				zapUpdate("update", update),
+				zap.Strings("invariants", violated))
+			changes = netChanges(append(changes, repairChanges...))
+			modified = stringset.Make()
+			for _, c := range changes {
+				modified.Insert(c.Resource)
+			}
+			return modified, changes, nil
+		}
+	}
+	m.restoreResources(previous, update)
+	m.reportViolations(violated, update, m.resourceValues(assigned), after)
+	m.metrics.rejected.Inc()
+	return nil, nil, &InvariantError{Invariants: violated, Update: update}
+}
+
+// repair performs the repair actions of the violated invariants, it returns the description of the
+// changes of the resources. If some invariant has no repair actions, if they assign a resource not in
+// workingSet, the working set of the input, or if the invariants do not hold after the repair then the
+// resources are left unchanged and false is returned.
+// It should be called while holding m.lockMemory.
+func (m *Executer) repair(violated []string, workingSet stringset.Set) ([]ResourceChange, bool) {
+	names := stringset.Make(violated...)
+	repair := Update{Source: SourceInput}
+	for _, inv := range m.invariants {
+		if !names.Has(inv.name) {
+			continue
+		}
+		if inv.repair == nil {
+			return nil, false
+		}
+		for _, action := range inv.repair {
+			if !workingSet.Has(action.Resource) {
+				// the repair actions were set after the working set was fixed
+				m.logger.Warn(fmt.Sprintf("Cannot repair invariant %s: %s is not in the working set of the input", inv.name, action.Resource),
+					zap.String("act", "repair_inv"))
+				return nil, false
+			}
+		}
+		actions, err := evalActions(inv.repair, m.dataContext, m.workingMemory)
+		if err != nil {
+			m.reportError(&EvalError{Step: StepInvariant, Invariant: inv.name, Err: err})
+			return nil, false
+		}
+		repair.Assignments = append(repair.Assignments, actions.Assignments...)
+	}
+	previous := m.memory.Extract(repair.resources())
+	_, changes, err := m.applyUpdate(repair)
+	if err != nil {
+		m.reportError(err)
+		return nil, false
+	}
+	violated, err = m.violatedInvariants()
+	if err != nil || len(violated) > 0 {
+		if err != nil {
+			m.reportError(err)
+		}
+		m.restoreResources(previous, repair)
+		return nil, false
+	}
+	return changes, true
+}
+
+// repairResources returns the resources that can be assigned by the repair actions of the invariants,
+// which are part of the working sets of the inputs when the input policy is InputRepair.
+// It should be called while holding m.lockMemory.
+func (m *Executer) repairResources() stringset.Set {
+	res := stringset.Make()
+	if m.inputPolicy != InputRepair {
+		return res
+	}
+	for _, inv := range m.invariants {
+		for _, action := range inv.repair {
+			res.Insert(action.Resource)
+		}
+	}
+	return res
+}
+
+// netChanges merges the changes of the same resource, keeping the first old value and the last
+// new one, and drops the resources whose value is eventually unchanged.
+func netChanges(changes []ResourceChange) []ResourceChange {
+	var res []ResourceChange
+	index := make(map[string]int)
+	for _, c := range changes {
+		i, present := index[c.Resource]
+		if !present {
+			index[c.Resource] = len(res)
+			res = append(res, c)
+			continue
+		}
+		res[i].New = c.New
+	}
+	net := res[:0]
+	for _, c := range res {
+		if !reflect.DeepEqual(c.Old, c.New) {
+			net = append(net, c)
+		}
+	}
+	return net
+}
+
+// restoreResources restores the values of the resources assigned by update from previous.
+// It should be called while holding m.lockMemory.
+func (m *Executer) restoreResources(previous memory.Resources, update Update) {
+	m.memory.Enclose(previous)
+	for _, action := range update.Assignments {
+		m.workingMemory.ResetVariable(action.variable)
+	}
+}
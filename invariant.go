@@ -0,0 +1,243 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/persistence"
+	"github.com/abu-lang/goabu/stringset"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"go.uber.org/zap"
+)
+
+// invariant is a named boolean expression on the resources of the node that holds in every correct state.
+type invariant struct {
+	name string
+	// source is the code of the invariant.
+	source string
+	exp    *ast.Expression
+	// repair contains the actions repairing the inputs that violate the invariant, if any.
+	repair []ecarule.Action
+	// repairSource is the code of the repair actions.
+	repairSource string
+}
+
+// InvariantViolation is the event sent over the channel returned by Violations when Exec discards an
+// update because it would violate an invariant, or when an input violates an invariant.
+type InvariantViolation struct {
+	// Invariant is the name of the violated invariant.
+	Invariant string
+	// Update is the discarded update or the update of the input.
+	Update Update
+	// Before contains the values of the resources assigned by Update before it was applied.
+	Before map[string]any
+	// After contains the values that the resources assigned by Update would have taken.
+	After map[string]any
+	// Dropped is the number of older events that were discarded to make room for this one
+	// because the channel was full.
+	Dropped int
+}
+
+// AddInvariant adds to the Executer an invariant with the given name. The invariant is a boolean
+// expression on the resources of the node which must hold after every execution of an update:
+// the updates that would violate it are discarded by Exec (see Violations).
+//
+// The invariants specified upon construction are named "#0", "#1" and so on in the order
+// in which they were specified.
+func (m *Executer) AddInvariant(name, expression string) error {
+	err := m.addInvariant(name, expression)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("Added invariant "+name, zap.String("act", "add_inv"), zap.String("obj", expression))
+	return nil
+}
+
+// RemoveInvariant removes the invariant with the given name from the Executer.
+func (m *Executer) RemoveInvariant(name string) error {
+	m.lockMemory.Lock()
+	defer m.lockMemory.Unlock()
+	for i, inv := range m.invariants {
+		if inv.name == name {
+			m.invariants = append(m.invariants[:i], m.invariants[i+1:]...)
+			m.persistInvariants()
+			m.logger.Info("Removed invariant "+name, zap.String("act", "remove_inv"))
+			return nil
+		}
+	}
+	return fmt.Errorf("no invariant named %s", name)
+}
+
+// Invariants returns the code of the invariants of the Executer indexed by their names.
+func (m *Executer) Invariants() map[string]string {
+	m.lockMemory.RLock()
+	defer m.lockMemory.RUnlock()
+	res := make(map[string]string, len(m.invariants))
+	for _, inv := range m.invariants {
+		res[inv.name] = inv.source
+	}
+	return res
+}
+
+// Violations returns the channel over which an [InvariantViolation] is sent for every invariant
+// violated by the updates discarded by Exec and by the inputs that are not repaired (see InputPolicy).
+// The channel is shared by all the callers.
+//
+// Like the channels returned by Subscribe, the channel has a bounded buffer and the Executer never
+// blocks on it: when the buffer is full the oldest event is discarded. The channel is closed by Close.
+func (m *Executer) Violations() <-chan InvariantViolation {
+	return m.violations
+}
+
+// addInvariants adds the invariants specified upon construction.
+func (m *Executer) addInvariants(invs ...string) error {
+	for i, inv := range invs {
+		err := m.addInvariant(fmt.Sprintf("#%d", i), inv)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addInvariant parses the given expression and adds it to m as an invariant with the specified name.
+func (m *Executer) addInvariant(name, expression string) error {
+	if name == "" {
+		return fmt.Errorf("invariant name cannot be empty")
+	}
+	parser := m.lexerParserPool.Get().(ecarule.Parser)
+	exps, errs := parser.ParseExpressions(expression)
+	m.lexerParserPool.Put(parser)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			m.logger.Error("error during parsing: "+err.Error(),
+				zap.String("act", "parse"),
+				zap.String("obj", expression))
+		}
+		m.logger.Sync()
+		return errors.Join(errs...)
+	}
+	m.lockMemory.Lock()
+	defer m.lockMemory.Unlock()
+	for _, inv := range m.invariants {
+		if inv.name == name {
+			return fmt.Errorf("an invariant named %s already exists", name)
+		}
+	}
+	val, err := exps[0].Evaluate(m.dataContext, m.workingMemory)
+	if err != nil {
+		m.logger.Error("Could not evaluate invariant: "+err.Error(),
+			zap.String("act", "eval_inv"),
+			zap.String("obj", expression))
+		return err
+	}
+	if val.Kind() != reflect.Bool {
+		m.logger.Error("Invariant with non-boolean type",
+			zap.String("act", "add_inv"),
+			zap.String("obj", expression))
+		return fmt.Errorf("type of invariant %s is not boolean", name)
+	}
+	m.invariants = append(m.invariants, invariant{name: name, source: expression, exp: exps[0]})
+	m.persistInvariants()
+	return nil
+}
+
+// violatedInvariants returns the names of the invariants, including the ranges of the resources,
+// that do not hold in the current state.
+// It should be called while holding m.lockMemory.
+func (m *Executer) violatedInvariants() ([]string, error) {
+	var res []string
+	for _, inv := range m.invariants {
+		val, err := inv.exp.Evaluate(m.dataContext, m.workingMemory)
+		if err != nil {
+			return nil, &EvalError{Step: StepInvariant, Invariant: inv.name, Err: err}
+		}
+		if !val.Bool() {
+			res = append(res, inv.name)
+		}
+	}
+	return append(res, m.violatedRanges()...), nil
+}
+
+// resourceValues returns copies of the current values of the specified resources.
+// It should be called while holding m.lockMemory.
+func (m *Executer) resourceValues(resources stringset.Set) map[string]any {
+	return m.valuesIn(m.memory.GetResources(), resources)
+}
+
+// valuesIn returns copies of the values in res of the specified resources.
+func (m *Executer) valuesIn(res memory.Resources, resources stringset.Set) map[string]any {
+	values := make(map[string]any, len(resources))
+	for r := range resources {
+		values[r] = memory.DeepCopy(reflect.ValueOf(res).FieldByName(m.types[r]).MapIndex(reflect.ValueOf(r)).Interface())
+	}
+	return values
+}
+
+// reportViolations sends an InvariantViolation for each of the violated invariants.
+func (m *Executer) reportViolations(violated []string, update Update, before, after map[string]any) {
+	m.lockViolations.Lock()
+	defer m.lockViolations.Unlock()
+	select {
+	case <-m.closed:
+		return
+	default:
+	}
+	for _, name := range violated {
+		v := InvariantViolation{
+			Invariant: name,
+			Update:    update.copy(),
+			Before:    make(map[string]any, len(before)),
+			After:     make(map[string]any, len(after)),
+		}
+		for r, val := range before {
+			v.Before[r] = memory.DeepCopy(val)
+		}
+		for r, val := range after {
+			v.After[r] = memory.DeepCopy(val)
+		}
+		m.sendViolation(v)
+	}
+}
+
+// sendViolation delivers v without blocking, discarding the oldest buffered events if needed.
+// It should be called while holding m.lockViolations.
+func (m *Executer) sendViolation(v InvariantViolation) {
+	if m.collector != nil {
+		m.collector.Violations = append(m.collector.Violations, v)
+		return
+	}
+	sendDropOldest(m.violations, v, func(v *InvariantViolation) { v.Dropped++ })
+}
+
+// closeViolations closes the channel returned by Violations.
+func (m *Executer) closeViolations() {
+	m.lockViolations.Lock()
+	defer m.lockViolations.Unlock()
+	close(m.violations)
+}
+
+// persistInvariants records the current invariants. It should be called while holding m.lockMemory.
+func (m *Executer) persistInvariants() {
+	if m.store == nil {
+		return
+	}
+	m.persist(persistence.Record{Kind: persistence.RecordInvariants, Invariants: m.persistentInvariants()})
+}
+
+// persistentInvariants returns the durable representation of the invariants of m.
+// It should be called while holding m.lockMemory.
+func (m *Executer) persistentInvariants() []persistence.Invariant {
+	res := make([]persistence.Invariant, 0, len(m.invariants))
+	for _, inv := range m.invariants {
+		res = append(res, persistence.Invariant{Name: inv.name, Source: inv.source, Repair: inv.repairSource})
+	}
+	return res
+}
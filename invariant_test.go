@@ -0,0 +1,208 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestNamedInvariants(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	e, err := NewExecuter(memory, []string{"rule double on foo for true do bar = foo * 2, foo = foo + 1"},
+		MakeMockAgent(), config.TestsLogConfig, "foo < 100")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	tests := []struct {
+		name       string
+		expression string
+		valid      bool
+	}{
+		{"small", "bar < 10", true},
+		{"small", "bar < 20", false},
+		{"", "bar < 10", false},
+		{"typed", "bar + 1", false},
+		{"absent", "qux < 1", false},
+	}
+	for _, test := range tests {
+		if err := e.AddInvariant(test.name, test.expression); (err == nil) != test.valid {
+			t.Errorf("AddInvariant(%q, %q) returned %v", test.name, test.expression, err)
+		}
+	}
+	expected := map[string]string{"#0": "foo < 100", "small": "bar < 10"}
+	if invs := e.Invariants(); !reflect.DeepEqual(invs, expected) {
+		t.Errorf("invariants should be %v, got %v", expected, invs)
+	}
+	err = e.Input("foo = 7")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	mem, pool := e.TakeState()
+	if mem.Integer["foo"] != 7 || mem.Integer["bar"] != 0 || len(pool) != 0 {
+		t.Error("the update should be discarded, got", mem, pool)
+	}
+	select {
+	case v := <-e.Violations():
+		if v.Invariant != "small" || v.Update.Rule != "double" {
+			t.Error("unexpected violation:", v)
+		}
+		if !reflect.DeepEqual(v.Before, map[string]any{"foo": int64(7), "bar": int64(0)}) ||
+			!reflect.DeepEqual(v.After, map[string]any{"foo": int64(8), "bar": int64(14)}) {
+			t.Error("unexpected values:", v.Before, v.After)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for a violation")
+	}
+	if e.RemoveInvariant("absent") == nil {
+		t.Error("removing an absent invariant should be an error")
+	}
+	err = e.RemoveInvariant("small")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input("foo = 7")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	if mem, _ = e.TakeState(); mem.Integer["foo"] != 8 || mem.Integer["bar"] != 14 {
+		t.Error("the update should be executed, got", mem)
+	}
+	select {
+	case v := <-e.Violations():
+		t.Error("unexpected violation:", v)
+	default:
+	}
+}
+
+func TestInputPolicy(t *testing.T) {
+	tests := []struct {
+		policy      InputPolicy
+		repair      string
+		rejected    bool
+		temperature int64
+		// violation reports whether an InvariantViolation should be sent
+		violation bool
+	}{
+		{InputReject, "temperature = 90", true, 20, true},
+		{InputRepair, "temperature = 90", false, 90, false},
+		{InputRepair, "", true, 20, true},
+		{InputRepair, "temperature = 100", true, 20, true},
+		{InputFlag, "", false, 120, true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v %q", test.policy, test.repair), func(t *testing.T) {
+			memory := memory.MakeResources()
+			memory.Integer["temperature"] = 20
+			memory.Bool["alarm"] = false
+			e, err := NewExecuterAdvanced(memory, []string{"rule watch on temperature for temperature >= 90 do alarm = true"},
+				MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{InputPolicy: test.policy})
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			e.SetOptimisticExec(*Optimistic)
+			e.SetOptimisticInput(*Optimistic)
+			defer e.Close()
+			err = e.AddInvariant("safe", "temperature <= 90")
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			err = e.SetInvariantRepair("safe", test.repair)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			err = e.Input("temperature = 120")
+			var invErr *InvariantError
+			if rejected := errors.As(err, &invErr); rejected != test.rejected {
+				t.Fatal("unexpected error:", err)
+			}
+			if test.rejected && (len(invErr.Invariants) != 1 || invErr.Invariants[0] != "safe") {
+				t.Error("unexpected violated invariants:", invErr.Invariants)
+			}
+			mem, pool := e.TakeState()
+			if mem.Integer["temperature"] != test.temperature {
+				t.Errorf("temperature should be %d, got %d", test.temperature, mem.Integer["temperature"])
+			}
+			if (len(pool) == 0) != test.rejected {
+				t.Error("the rule should be activated only by accepted inputs, got", pool)
+			}
+			select {
+			case v := <-e.Violations():
+				if !test.violation {
+					t.Error("unexpected violation:", v)
+				} else if v.Invariant != "safe" || v.Before["temperature"] != int64(20) || v.After["temperature"] != int64(120) {
+					t.Error("unexpected violation:", v)
+				}
+			default:
+				if test.violation {
+					t.Error("a violation should be reported")
+				}
+			}
+		})
+	}
+}
+
+func TestRepairChanges(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["temperature"] = 20
+	memory.Bool["alarm"] = false
+	e, err := NewExecuterAdvanced(memory, nil, MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{InputPolicy: InputRepair})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	err = e.AddInvariant("safe", "temperature <= 90")
+	if err == nil {
+		err = e.SetInvariantRepair("safe", "temperature = 90, alarm = true")
+	}
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	changes, err := e.Subscribe()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input("temperature = 120")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// only the net changes are published: temperature is never 120 for the subscribers
+	got := []ResourceChange{receiveChange(t, changes), receiveChange(t, changes)}
+	expected := []ResourceChange{
+		{Resource: "temperature", Old: int64(20), New: int64(90), Source: SourceInput},
+		{Resource: "alarm", Old: false, New: true, Source: SourceInput},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Error("unexpected changes:", got)
+	}
+	// the repair restores the value of temperature
+	err = e.SetInvariantRepair("safe", "temperature = 90")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input("temperature = 100")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	select {
+	case c := <-changes:
+		t.Error("unexpected change:", c)
+	default:
+	}
+}
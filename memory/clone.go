@@ -0,0 +1,112 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import "reflect"
+
+// Cloner is implemented by the values of Other resources that know how to copy themselves,
+// [DeepCopy] prefers their Clone method to copying them by means of reflection.
+type Cloner interface {
+	// Clone returns a copy of the receiver not sharing any mutable state with it,
+	// the copy must have the same type as the receiver.
+	Clone() interface{}
+}
+
+// DeepCopy returns a copy of v not sharing any mutable state with it.
+// The values implementing [Cloner] are copied by means of their Clone method, the other ones are
+// copied by following pointers, slices, maps, arrays, interfaces and the exported fields of structs.
+// The unexported fields of structs, channels and functions are copied as they are: the types
+// having unexported mutable state should implement Cloner.
+func DeepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(v), make(map[visit]reflect.Value)).Interface()
+}
+
+// visit identifies a pointer already copied by deepCopy, so that cyclic values can be copied.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func deepCopy(v reflect.Value, visited map[visit]reflect.Value) reflect.Value {
+	if res, ok := clone(v); ok {
+		return res
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := visit{ptr: v.Pointer(), typ: v.Type()}
+		if res, present := visited[key]; present {
+			return res
+		}
+		res := reflect.New(v.Type().Elem())
+		visited[key] = res
+		res.Elem().Set(deepCopy(v.Elem(), visited))
+		return res
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(deepCopy(v.Elem(), visited))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+		return res
+	case reflect.Array:
+		res := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res.SetMapIndex(deepCopy(iter.Key(), visited), deepCopy(iter.Value(), visited))
+		}
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if res.Field(i).CanSet() {
+				res.Field(i).Set(deepCopy(v.Field(i), visited))
+			}
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// clone copies v by means of its Clone method, it returns false if v does not implement [Cloner]
+// or if Clone returns a value of a different type.
+func clone(v reflect.Value) (reflect.Value, bool) {
+	if !v.CanInterface() || v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	c, ok := v.Interface().(Cloner)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	res := reflect.ValueOf(c.Clone())
+	if !res.IsValid() || res.Type() != v.Type() {
+		return reflect.Value{}, false
+	}
+	return res, true
+}
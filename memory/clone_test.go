@@ -0,0 +1,97 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package memory_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abu-lang/goabu/memory"
+)
+
+type node struct {
+	Label    string
+	Tags     []string
+	Weights  map[string]float64
+	Next     *node
+	Payload  interface{}
+	Counters [2]*int
+	private  []int
+}
+
+type calibration struct {
+	offsets []float64
+}
+
+func (c *calibration) Clone() interface{} {
+	return &calibration{offsets: append([]float64(nil), c.offsets...)}
+}
+
+func TestDeepCopy(t *testing.T) {
+	count := 1
+	original := &node{
+		Label:    "a",
+		Tags:     []string{"x", "y"},
+		Weights:  map[string]float64{"w": 0.5},
+		Payload:  []interface{}{map[string]interface{}{"k": 1}},
+		Counters: [2]*int{&count, nil},
+		private:  []int{1},
+	}
+	original.Next = original
+	copied := memory.DeepCopy(original).(*node)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatal("the copy should be equal to the original")
+	}
+	if copied == original || copied.Next != copied {
+		t.Error("the cycles should be preserved without sharing pointers")
+	}
+	copied.Tags[0] = "z"
+	copied.Weights["w"] = 1
+	copied.Payload.([]interface{})[0].(map[string]interface{})["k"] = 2
+	*copied.Counters[0] = 2
+	if original.Tags[0] != "x" || original.Weights["w"] != 0.5 || count != 1 ||
+		original.Payload.([]interface{})[0].(map[string]interface{})["k"] != 1 {
+		t.Error("modifying the copy should not affect the original:", original)
+	}
+	if &copied.private[0] != &original.private[0] {
+		t.Error("the unexported fields should be copied as they are")
+	}
+
+	c := &calibration{offsets: []float64{0.1}}
+	copiedC := memory.DeepCopy(c).(*calibration)
+	copiedC.offsets[0] = 1
+	if c.offsets[0] != 0.1 {
+		t.Error("the Cloner should be used to copy unexported state")
+	}
+	wrapped := memory.DeepCopy([]interface{}{c}).([]interface{})
+	wrapped[0].(*calibration).offsets[0] = 1
+	if c.offsets[0] != 0.1 {
+		t.Error("the Cloner should be used for nested values")
+	}
+	for _, v := range []interface{}{nil, 42, "text", (*node)(nil), []int(nil), map[string]int(nil)} {
+		if res := memory.DeepCopy(v); !reflect.DeepEqual(res, v) {
+			t.Errorf("DeepCopy(%#v) returned %#v", v, res)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	r := memory.MakeResources()
+	r.Other["calibration"] = &calibration{offsets: []float64{0.1}}
+	r.Other["settings"] = map[string]int{"speed": 1}
+	r.List["jobs"] = []interface{}{"build"}
+	r.Map["peers"] = map[string]interface{}{"n1": true}
+	copied := r.Copy().GetResources()
+	extracted := r.Extract(r.ResourceNames())
+	for _, c := range []memory.Resources{copied, extracted} {
+		c.Other["calibration"].(*calibration).offsets[0] = 1
+		c.Other["settings"].(map[string]int)["speed"] = 2
+		c.List["jobs"][0] = "test"
+		c.Map["peers"]["n2"] = true
+	}
+	if r.Other["calibration"].(*calibration).offsets[0] != 0.1 || r.Other["settings"].(map[string]int)["speed"] != 1 ||
+		r.List["jobs"][0] != "build" || len(r.Map["peers"]) != 1 {
+		t.Error("modifying the copies should not affect the original:", r)
+	}
+}
@@ -0,0 +1,462 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeKind is the kind of a [Change].
+type ChangeKind string
+
+const (
+	// ChangeAdded marks a resource that was added.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved marks a resource that was removed.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeChanged marks a resource whose value changed.
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a difference between two [Resources].
+type Change struct {
+	Kind     ChangeKind
+	Resource string
+	// Type is the type of the resource (one of the following: "Bool", "Integer", "Float", "Text", "Time",
+	// "Other", "List", "Map"). A resource whose type changed is described by a ChangeRemoved and a ChangeAdded.
+	Type string
+	// Old is the value of the resource before the change, it is nil for the ChangeAdded changes.
+	Old interface{}
+	// New is the value of the resource after the change, it is nil for the ChangeRemoved changes.
+	New interface{}
+}
+
+// ChangeSet is a list of changes, as returned by [Resources.Diff]. Its JSON encoding, provided by the
+// MarshalJSON and UnmarshalJSON methods of [Change], preserves the types of the values, except for the
+// values of the Other resources which are decoded as the generic values of encoding/json.
+type ChangeSet []Change
+
+// Diff returns the changes turning r into other sorted by resource name, the metadata is ignored.
+// The values of the Other, List and Map resources in the changes are copied by means of [DeepCopy].
+//
+// Prerequisite: !r.HasDuplicates() && !other.HasDuplicates()
+func (r Resources) Diff(other Resources) ChangeSet {
+	before, after := r.Types(), other.Types()
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, present := before[name]; !present {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	res := ChangeSet{}
+	for _, name := range names {
+		oldType, wasPresent := before[name]
+		newType, isPresent := after[name]
+		if wasPresent && isPresent && oldType == newType {
+			oldValue, newValue := r.value(oldType, name), other.value(newType, name)
+			if !sameValue(oldValue, newValue) {
+				res = append(res, Change{Kind: ChangeChanged, Resource: name, Type: newType, Old: DeepCopy(oldValue), New: DeepCopy(newValue)})
+			}
+			continue
+		}
+		if wasPresent {
+			res = append(res, Change{Kind: ChangeRemoved, Resource: name, Type: oldType, Old: DeepCopy(r.value(oldType, name))})
+		}
+		if isPresent {
+			res = append(res, Change{Kind: ChangeAdded, Resource: name, Type: newType, New: DeepCopy(other.value(newType, name))})
+		}
+	}
+	return res
+}
+
+// Patch applies changes to r, e.g. the ones returned by r.Diff. The changes are checked before applying
+// any of them: the added resources must be absent, the removed and changed ones must have the Old value
+// and all the values must have the type of their resource. If some check fails then r is left unchanged
+// and the returned error describes every failed check.
+// The values of the Other, List and Map resources are copied by means of [DeepCopy].
+//
+// Prerequisite: !r.HasDuplicates()
+func (r Resources) Patch(changes ChangeSet) error {
+	types := r.Types()
+	// touched contains the resources already modified by the checked changes
+	touched := make(map[string]bool)
+	var errs []error
+	for _, c := range changes {
+		typ, present := types[c.Resource]
+		switch c.Kind {
+		case ChangeAdded:
+			if present {
+				errs = append(errs, fmt.Errorf("cannot add resource %s: it already exists", c.Resource))
+			} else if err := validValue(c.Type, c.New); err != nil {
+				errs = append(errs, fmt.Errorf("cannot add resource %s: %w", c.Resource, err))
+			}
+			types[c.Resource] = c.Type
+		case ChangeRemoved, ChangeChanged:
+			if !present {
+				errs = append(errs, fmt.Errorf("cannot patch resource %s: it does not exist", c.Resource))
+				break
+			}
+			if typ != c.Type {
+				errs = append(errs, fmt.Errorf("cannot patch resource %s: it is a %s resource, not a %s one", c.Resource, typ, c.Type))
+				break
+			}
+			if !touched[c.Resource] && !sameValue(r.value(typ, c.Resource), c.Old) {
+				errs = append(errs, fmt.Errorf("conflict on resource %s: its value is %v, not %v", c.Resource, r.value(typ, c.Resource), c.Old))
+			}
+			if c.Kind == ChangeRemoved {
+				delete(types, c.Resource)
+			} else if err := validValue(c.Type, c.New); err != nil {
+				errs = append(errs, fmt.Errorf("cannot change resource %s: %w", c.Resource, err))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unknown kind of change %q for resource %s", c.Kind, c.Resource))
+		}
+		touched[c.Resource] = true
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, c := range changes {
+		if c.Kind == ChangeRemoved {
+			r.remove(c.Type, c.Resource)
+		} else {
+			r.set(c.Type, c.Resource, DeepCopy(c.New))
+		}
+	}
+	return nil
+}
+
+// value returns the value of the resource name of type typ.
+func (r Resources) value(typ, name string) interface{} {
+	switch typ {
+	case "Bool":
+		return r.Bool[name]
+	case "Integer":
+		return r.Integer[name]
+	case "Float":
+		return r.Float[name]
+	case "Text":
+		return r.Text[name]
+	case "Time":
+		return r.Time[name]
+	case "List":
+		return r.List[name]
+	case "Map":
+		return r.Map[name]
+	default:
+		return r.Other[name]
+	}
+}
+
+// set sets the resource name of type typ to v, which must have been checked by validValue.
+func (r Resources) set(typ, name string, v interface{}) {
+	switch typ {
+	case "Bool":
+		r.Bool[name] = v.(bool)
+	case "Integer":
+		r.Integer[name] = v.(int64)
+	case "Float":
+		r.Float[name] = v.(float64)
+	case "Text":
+		r.Text[name] = v.(string)
+	case "Time":
+		r.Time[name] = v.(time.Time)
+	case "List":
+		r.List[name] = v.([]interface{})
+	case "Map":
+		r.Map[name] = v.(map[string]interface{})
+	default:
+		r.Other[name] = v
+	}
+}
+
+// remove removes the resource name of type typ.
+func (r Resources) remove(typ, name string) {
+	switch typ {
+	case "Bool":
+		delete(r.Bool, name)
+	case "Integer":
+		delete(r.Integer, name)
+	case "Float":
+		delete(r.Float, name)
+	case "Text":
+		delete(r.Text, name)
+	case "Time":
+		delete(r.Time, name)
+	case "List":
+		delete(r.List, name)
+	case "Map":
+		delete(r.Map, name)
+	default:
+		delete(r.Other, name)
+	}
+}
+
+// validValue checks that v can be the value of a resource of type typ.
+func validValue(typ string, v interface{}) error {
+	ok := false
+	switch typ {
+	case "Bool":
+		_, ok = v.(bool)
+	case "Integer":
+		_, ok = v.(int64)
+	case "Float":
+		_, ok = v.(float64)
+	case "Text":
+		_, ok = v.(string)
+	case "Time":
+		_, ok = v.(time.Time)
+	case "List":
+		var l []interface{}
+		l, ok = v.([]interface{})
+		for _, e := range l {
+			ok = ok && validElement(e)
+		}
+	case "Map":
+		var m map[string]interface{}
+		m, ok = v.(map[string]interface{})
+		for _, e := range m {
+			ok = ok && validElement(e)
+		}
+	case "Other":
+		ok = true
+	default:
+		return fmt.Errorf("unknown type %s", typ)
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s value of type %T", typ, v)
+	}
+	return nil
+}
+
+// validElement reports whether v can be an element of a List or Map resource.
+func validElement(v interface{}) bool {
+	switch v.(type) {
+	case bool, int64, float64, string, time.Time:
+		return true
+	}
+	return false
+}
+
+// sameValue reports whether the values a and b are equal, the times are compared by means of time.Time.Equal.
+func sameValue(a, b interface{}) bool {
+	switch x := a.(type) {
+	case time.Time:
+		y, ok := b.(time.Time)
+		return ok && x.Equal(y)
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !sameValue(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, present := y[k]
+			if !present || !sameValue(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// jsonChange is the JSON encoding of a Change.
+type jsonChange struct {
+	Kind     ChangeKind      `json:"kind"`
+	Resource string          `json:"resource"`
+	Type     string          `json:"type"`
+	Old      json.RawMessage `json:"old,omitempty"`
+	New      json.RawMessage `json:"new,omitempty"`
+}
+
+// MarshalJSON encodes c as a JSON object with the fields "kind", "resource", "type", "old" and "new".
+// The elements of the List and Map values are encoded so that their types can be restored:
+// the Float elements always have a fractional part or an exponent and the Time elements are
+// encoded as objects like {"Time": "2006-01-02T15:04:05Z"}.
+func (c Change) MarshalJSON() ([]byte, error) {
+	res := jsonChange{Kind: c.Kind, Resource: c.Resource, Type: c.Type}
+	var err error
+	if c.Kind != ChangeAdded {
+		res.Old, err = marshalValue(c.Type, c.Old)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.Kind != ChangeRemoved {
+		res.New, err = marshalValue(c.Type, c.New)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON decodes a Change encoded by MarshalJSON.
+func (c *Change) UnmarshalJSON(data []byte) error {
+	var decoded jsonChange
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+	res := Change{Kind: decoded.Kind, Resource: decoded.Resource, Type: decoded.Type}
+	res.Old, err = unmarshalValue(decoded.Type, decoded.Old)
+	if err != nil {
+		return fmt.Errorf("invalid old value of resource %s: %w", decoded.Resource, err)
+	}
+	res.New, err = unmarshalValue(decoded.Type, decoded.New)
+	if err != nil {
+		return fmt.Errorf("invalid new value of resource %s: %w", decoded.Resource, err)
+	}
+	*c = res
+	return nil
+}
+
+// jsonTime is the JSON encoding of the Time elements of the List and Map values.
+type jsonTime struct {
+	Time time.Time
+}
+
+func marshalValue(typ string, v interface{}) (json.RawMessage, error) {
+	switch typ {
+	case "List":
+		if l, ok := v.([]interface{}); ok {
+			elements := make([]interface{}, len(l))
+			for i, e := range l {
+				elements[i] = marshalElement(e)
+			}
+			v = elements
+		}
+	case "Map":
+		if m, ok := v.(map[string]interface{}); ok {
+			entries := make(map[string]interface{}, len(m))
+			for k, e := range m {
+				entries[k] = marshalElement(e)
+			}
+			v = entries
+		}
+	}
+	return json.Marshal(v)
+}
+
+func marshalElement(e interface{}) interface{} {
+	switch v := e.(type) {
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return json.Number(s)
+	case time.Time:
+		return jsonTime{Time: v}
+	}
+	return e
+}
+
+func unmarshalValue(typ string, data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var err error
+	switch typ {
+	case "Bool":
+		var v bool
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "Integer":
+		var v int64
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "Float":
+		var v float64
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "Text":
+		var v string
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "Time":
+		var v time.Time
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "List":
+		var raw []json.RawMessage
+		err = json.Unmarshal(data, &raw)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]interface{}, len(raw))
+		for i, e := range raw {
+			res[i], err = unmarshalElement(e)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case "Map":
+		var raw map[string]json.RawMessage
+		err = json.Unmarshal(data, &raw)
+		if err != nil {
+			return nil, err
+		}
+		res := make(map[string]interface{}, len(raw))
+		for k, e := range raw {
+			res[k], err = unmarshalElement(e)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default:
+		var v interface{}
+		err = json.Unmarshal(data, &v)
+		return v, err
+	}
+}
+
+func unmarshalElement(data json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	err := d.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	switch e := v.(type) {
+	case json.Number:
+		if strings.ContainsAny(e.String(), ".eE") {
+			return e.Float64()
+		}
+		return e.Int64()
+	case map[string]interface{}:
+		if s, ok := e["Time"].(string); ok && len(e) == 1 {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	case bool, string:
+		return e, nil
+	}
+	return nil, fmt.Errorf("invalid element %s", data)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package memory_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestDiff(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	before := memory.MakeResources()
+	before.Integer["speed"] = 10
+	before.Bool["running"] = true
+	before.Text["label"] = "fan"
+	before.Time["start"] = start
+	before.List["jobs"] = []interface{}{"calibrate", int64(3)}
+	before.Map["limits"] = map[string]interface{}{"max": 2.0}
+	before.Float["mode"] = 1
+	after := before.Copy().GetResources()
+	after.Integer["speed"] = 20
+	delete(after.Bool, "running")
+	after.Float["ratio"] = 0.5
+	after.Time["start"] = start.In(time.FixedZone("CET", 3600))
+	after.List["jobs"] = []interface{}{"calibrate", int64(3), start, 2.0}
+	delete(after.Float, "mode")
+	after.Text["mode"] = "eco"
+	after.Meta["speed"] = memory.Metadata{Unit: "rpm"}
+	expected := memory.ChangeSet{
+		{Kind: memory.ChangeChanged, Resource: "jobs", Type: "List", Old: []interface{}{"calibrate", int64(3)}, New: []interface{}{"calibrate", int64(3), start, 2.0}},
+		{Kind: memory.ChangeRemoved, Resource: "mode", Type: "Float", Old: 1.0},
+		{Kind: memory.ChangeAdded, Resource: "mode", Type: "Text", New: "eco"},
+		{Kind: memory.ChangeAdded, Resource: "ratio", Type: "Float", New: 0.5},
+		{Kind: memory.ChangeRemoved, Resource: "running", Type: "Bool", Old: true},
+		{Kind: memory.ChangeChanged, Resource: "speed", Type: "Integer", Old: int64(10), New: int64(20)},
+	}
+	changes := before.Diff(after)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("unexpected changes:\n%v\nexpected:\n%v", changes, expected)
+	}
+	if len(after.Diff(after.Copy().GetResources())) != 0 {
+		t.Error("equal resources should have no changes")
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var decoded memory.ChangeSet
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(decoded) != len(changes) || !reflect.DeepEqual(decoded[1:], changes[1:]) ||
+		!reflect.DeepEqual(decoded[0].Old, changes[0].Old) || !decoded[0].New.([]interface{})[2].(time.Time).Equal(start) || decoded[0].New.([]interface{})[3] != 2.0 {
+		t.Errorf("the JSON encoding should preserve the changes, got:\n%v\nfrom %s", decoded, encoded)
+	}
+	patched := before.Copy().GetResources()
+	err = patched.Patch(decoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if changes := patched.Diff(after); len(changes) != 0 {
+		t.Error("the patched resources should be equal to the target ones, got", changes)
+	}
+	if patched.Metadata("speed").Unit != "" {
+		t.Error("Patch should ignore the metadata")
+	}
+}
+
+func TestPatchConflicts(t *testing.T) {
+	r := memory.MakeResources()
+	r.Integer["speed"] = 10
+	r.Bool["running"] = true
+	changes := memory.ChangeSet{
+		{Kind: memory.ChangeChanged, Resource: "running", Type: "Bool", Old: true, New: false},
+		{Kind: memory.ChangeChanged, Resource: "speed", Type: "Integer", Old: int64(5), New: int64(20)},
+		{Kind: memory.ChangeAdded, Resource: "running", Type: "Bool", New: true},
+		{Kind: memory.ChangeAdded, Resource: "ratio", Type: "Float", New: "half"},
+		{Kind: memory.ChangeRemoved, Resource: "absent", Type: "Text", Old: ""},
+	}
+	err := r.Patch(changes)
+	if err == nil {
+		t.Fatal("the conflicting changes should be rejected")
+	}
+	if errs := err.(interface{ Unwrap() []error }).Unwrap(); len(errs) != 4 {
+		t.Error("every conflict should be reported, got", errs)
+	}
+	if !r.Bool["running"] || r.Integer["speed"] != 10 || r.Has("ratio") {
+		t.Error("the resources should be unchanged, got", r)
+	}
+}
@@ -35,6 +35,14 @@ func TestHasDuplicates(t *testing.T) {
 	r6.Text["a,met"] = ""
 	r6.Bool["z"] = true
 
+	r7 := memory.MakeResources()
+	r7.List["jobs"] = []interface{}{"build"}
+	r7.Map["jobs"] = map[string]interface{}{}
+
+	r8 := memory.MakeResources()
+	r8.List["jobs"] = nil
+	r8.Map["peers"] = map[string]interface{}{"n1": true}
+
 	tests := []struct {
 		index         int
 		resources     memory.Resources
@@ -47,6 +55,8 @@ func TestHasDuplicates(t *testing.T) {
 		{4, r4, true},
 		{5, r5, true},
 		{6, r6, false},
+		{7, r7, true},
+		{8, r8, false},
 	}
 	for _, test := range tests {
 		if test.resources.HasDuplicates() != test.hasDuplicates {
@@ -54,3 +64,29 @@ func TestHasDuplicates(t *testing.T) {
 		}
 	}
 }
+
+func TestMetadata(t *testing.T) {
+	r := memory.MakeResources()
+	r.Integer["speed"] = 0
+	r.Meta["speed"] = memory.Metadata{Min: memory.Bound(-255), Max: memory.Bound(255), Unit: "pwm"}
+	meta := r.Metadata("speed")
+	if !meta.HasRange() || !meta.InRange(-255) || meta.InRange(256) || meta.Range() != "[-255, 255]" {
+		t.Error("unexpected range:", meta.Range())
+	}
+	if r.Metadata("absent") != (memory.Metadata{}) {
+		t.Error("the resources without metadata should have the zero Metadata")
+	}
+	copied := r.Copy().GetResources()
+	*copied.Meta["speed"].Max = 100
+	if *r.Meta["speed"].Max != 255 || copied.Metadata("speed").Unit != "pwm" {
+		t.Error("Copy should copy the metadata:", copied.Meta)
+	}
+	if extracted := r.Extract([]string{"speed"}); len(extracted.Meta) != 0 {
+		t.Error("Extract should ignore the metadata:", extracted.Meta)
+	}
+	other := memory.MakeResources()
+	other.Enclose(r)
+	if len(other.Meta) != 0 {
+		t.Error("Enclose should ignore the metadata:", other.Meta)
+	}
+}
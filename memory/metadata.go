@@ -0,0 +1,65 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import "fmt"
+
+// Metadata describes a resource. The zero Metadata describes a writable resource without a range.
+type Metadata struct {
+	// ReadOnly reports whether the resource can only be set by the inputs of the node: the rules and the
+	// tasks received from the other nodes cannot assign it, e.g. because it reflects the state of a sensor.
+	ReadOnly bool
+	// Min and Max, if not nil, bound the values of an Integer or Float resource. The updates setting the
+	// resource out of its range are handled as the ones violating an invariant.
+	Min *float64
+	Max *float64
+	// Unit is the unit of measurement of the values of the resource, e.g. "°C".
+	Unit string
+	// Description is a human readable description of the resource.
+	Description string
+}
+
+// Bound returns a pointer to v, for specifying the Min and Max fields of a [Metadata].
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// HasRange reports whether m bounds the values of the resource.
+func (m Metadata) HasRange() bool {
+	return m.Min != nil || m.Max != nil
+}
+
+// InRange reports whether v is within the range of m.
+func (m Metadata) InRange(v float64) bool {
+	return (m.Min == nil || v >= *m.Min) && (m.Max == nil || v <= *m.Max)
+}
+
+// Range returns a description of the range of m like "[0, 255]", the missing bounds are written as -inf and +inf.
+func (m Metadata) Range() string {
+	lower, upper := "-inf", "+inf"
+	if m.Min != nil {
+		lower = fmt.Sprint(*m.Min)
+	}
+	if m.Max != nil {
+		upper = fmt.Sprint(*m.Max)
+	}
+	return "[" + lower + ", " + upper + "]"
+}
+
+// copy returns a copy of m not sharing its bounds.
+func (m Metadata) copy() Metadata {
+	if m.Min != nil {
+		m.Min = Bound(*m.Min)
+	}
+	if m.Max != nil {
+		m.Max = Bound(*m.Max)
+	}
+	return m
+}
+
+// Metadata returns the metadata of the resource identified by the provided string,
+// the zero [Metadata] if it has none.
+func (r Resources) Metadata(resource string) Metadata {
+	return r.Meta[resource]
+}
@@ -14,7 +14,8 @@ type ResourceController interface {
 	Errors() <-chan error
 	// Modified shall be called when the resource with the given identifier is set to a different value.
 	Modified(string)
-	// Extract returns a shallow copy of only the resources specified by the provided identifiers.
+	// Extract returns a copy of only the resources specified by the provided identifiers,
+	// the values of the Other, List and Map resources are copied by means of DeepCopy.
 	Extract([]string) Resources
 	// Enclose adds the provided resources to the ResourceController, overwriting previous values if present.
 	Enclose(Resources)
@@ -22,7 +23,7 @@ type ResourceController interface {
 	HasDuplicates() bool
 	// Has checks if the ResourceController contains a resource identified by the provided string.
 	Has(string) bool
-	// Types returns a map with an entry for each resource specifying its type (one of the following: "Bool", "Integer", "Float", "Text", "Time", "Other").
+	// Types returns a map with an entry for each resource specifying its type (one of the following: "Bool", "Integer", "Float", "Text", "Time", "Other", "List", "Map").
 	// Prerequisite: !HasDuplicates()
 	Types() map[string]string
 	// GetResources provides access to the resources.
@@ -31,6 +32,31 @@ type ResourceController interface {
 	ResourceNames() []string
 	// String returns a string representation of the ResourceController for debugging purposes.
 	String() string
-	// Copy returns a shallow copy of the ResourceController.
+	// Copy returns a copy of the ResourceController whose resources do not share mutable state
+	// with the ones of the receiver (see DeepCopy).
 	Copy() ResourceController
 }
+
+// StoppableResourceController is implemented by the ResourceControllers that acquire resources in Start,
+// e.g. goroutines or connections to devices, which must be released when the node is closed.
+type StoppableResourceController interface {
+	ResourceController
+	// Stop shall be called when the node stops processing inputs from the environment, it releases the resources acquired by Start.
+	Stop() error
+}
+
+// TypedResourceController is implemented by the ResourceControllers that can provide the inputs received
+// from the environment as typed values, which do not need to be parsed, along with the ones returned by Inputs.
+type TypedResourceController interface {
+	ResourceController
+	// InputEvents returns a channel providing the inputs received from the environment as typed values.
+	InputEvents() <-chan InputEvent
+}
+
+// InputEvent is an input received from the environment setting the resource named Resource to Value.
+// The type of Value must be the one of the resource, any integer and floating point type is accepted
+// for Integer and Float resources respectively.
+type InputEvent struct {
+	Resource string
+	Value    interface{}
+}
@@ -4,14 +4,26 @@
 package memory
 
 import (
+	"encoding/gob"
 	"fmt"
 	"time"
 
 	"github.com/abu-lang/goabu/stringset"
 )
 
+func init() {
+	// the elements of the List and Map resources are encoded as interface values
+	gob.Register(time.Time{})
+}
+
 // Resources is a struct implementing the [ResourceController] interface modeling the state of a node
 // that has no sensors nor actuators.
+//
+// The elements of the List resources and the values of the Map resources must be bool, int64, float64,
+// string or time.Time values.
+//
+// Meta contains the [Metadata] of the resources that have any, it is not part of the state of the node:
+// Extract and Enclose ignore it.
 type Resources struct {
 	Bool    map[string]bool
 	Integer map[string]int64
@@ -19,6 +31,9 @@ type Resources struct {
 	Text    map[string]string
 	Time    map[string]time.Time
 	Other   map[string]interface{}
+	List    map[string][]interface{}
+	Map     map[string]map[string]interface{}
+	Meta    map[string]Metadata
 }
 
 // MakeResources returns a new empty [Resources] struct.
@@ -30,6 +45,9 @@ func MakeResources() Resources {
 		Text:    make(map[string]string),
 		Time:    make(map[string]time.Time),
 		Other:   make(map[string]interface{}),
+		List:    make(map[string][]interface{}),
+		Map:     make(map[string]map[string]interface{}),
+		Meta:    make(map[string]Metadata),
 	}
 }
 
@@ -38,6 +56,11 @@ func (r Resources) Start() error {
 	return nil
 }
 
+// Stop returns nil.
+func (r Resources) Stop() error {
+	return nil
+}
+
 // Inputs returns nil.
 func (r Resources) Inputs() <-chan string {
 	return nil
@@ -90,6 +113,18 @@ func (r Resources) HasDuplicates() bool {
 		}
 		atts.Insert(a)
 	}
+	for a := range r.List {
+		if atts.Has(a) {
+			return true
+		}
+		atts.Insert(a)
+	}
+	for a := range r.Map {
+		if atts.Has(a) {
+			return true
+		}
+		atts.Insert(a)
+	}
 	return false
 }
 
@@ -116,11 +151,19 @@ func (r Resources) Has(resource string) bool {
 		return true
 	}
 	_, present = r.Other[resource]
+	if present {
+		return true
+	}
+	_, present = r.List[resource]
+	if present {
+		return true
+	}
+	_, present = r.Map[resource]
 	return present
 }
 
 // Types returns a map with an entry for each resource specifying its type
-// (one of the following: "Bool", "Integer", "Float", "Text", "Time", "Other").
+// (one of the following: "Bool", "Integer", "Float", "Text", "Time", "Other", "List", "Map").
 //
 // Prerequisite: !HasDuplicates()
 func (r Resources) Types() map[string]string {
@@ -143,6 +186,12 @@ func (r Resources) Types() map[string]string {
 	for a := range r.Other {
 		res[a] = "Other"
 	}
+	for a := range r.List {
+		res[a] = "List"
+	}
+	for a := range r.Map {
+		res[a] = "Map"
+	}
 	return res
 }
 
@@ -172,10 +221,17 @@ func (r Resources) ResourceNames() []string {
 	for a := range r.Other {
 		atts.Insert(a)
 	}
+	for a := range r.List {
+		atts.Insert(a)
+	}
+	for a := range r.Map {
+		atts.Insert(a)
+	}
 	return atts.Slice()
 }
 
-// Extract returns a shallow copy of only the resources specified by the provided identifiers.
+// Extract returns a copy of only the resources specified by the provided identifiers,
+// the values of the Other, List and Map resources are copied by means of [DeepCopy].
 func (r Resources) Extract(resources []string) Resources {
 	s := stringset.Make(resources...)
 	res := MakeResources()
@@ -206,7 +262,17 @@ func (r Resources) Extract(resources []string) Resources {
 	}
 	for k, v := range r.Other {
 		if s.Has(k) {
-			res.Other[k] = v
+			res.Other[k] = DeepCopy(v)
+		}
+	}
+	for k, v := range r.List {
+		if s.Has(k) {
+			res.List[k] = DeepCopy(v).([]interface{})
+		}
+	}
+	for k, v := range r.Map {
+		if s.Has(k) {
+			res.Map[k] = DeepCopy(v).(map[string]interface{})
 		}
 	}
 	return res
@@ -232,6 +298,12 @@ func (r Resources) Enclose(i Resources) {
 	for k, v := range i.Other {
 		r.Other[k] = v
 	}
+	for k, v := range i.List {
+		r.List[k] = v
+	}
+	for k, v := range i.Map {
+		r.Map[k] = v
+	}
 }
 
 // String returns a string representation of the struct for debugging purposes.
@@ -255,10 +327,17 @@ func (r Resources) String() string {
 	for key, value := range r.Other {
 		str = str + fmt.Sprintf("(%T)%s->%v ", value, key, value)
 	}
+	for key, value := range r.List {
+		str = str + fmt.Sprintf("(%T)%s->%v ", value, key, value)
+	}
+	for key, value := range r.Map {
+		str = str + fmt.Sprintf("(%T)%s->%v ", value, key, value)
+	}
 	return str + "]"
 }
 
-// Copy returns a shallow copy of the struct.
+// Copy returns a copy of the struct including the metadata, the values of the Other, List and Map resources
+// are copied by means of [DeepCopy].
 func (r Resources) Copy() ResourceController {
 	res := MakeResources()
 	for k, v := range r.Bool {
@@ -277,7 +356,16 @@ func (r Resources) Copy() ResourceController {
 		res.Time[k] = v
 	}
 	for k, v := range r.Other {
-		res.Other[k] = v
+		res.Other[k] = DeepCopy(v)
+	}
+	for k, v := range r.List {
+		res.List[k] = DeepCopy(v).([]interface{})
+	}
+	for k, v := range r.Map {
+		res.Map[k] = DeepCopy(v).(map[string]interface{})
+	}
+	for k, v := range r.Meta {
+		res.Meta[k] = v.copy()
 	}
 	return res
 }
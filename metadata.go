@@ -0,0 +1,87 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/stringset"
+)
+
+// readOnlyResources is the set of the read-only resources of a node, it implements [parser.ReadOnly].
+type readOnlyResources stringset.Set
+
+// IsReadOnly reports whether the resource name is read-only.
+func (r readOnlyResources) IsReadOnly(name string) bool {
+	return stringset.Set(r).Has(name)
+}
+
+// RangeInvariant returns the name under which the violations of the range of the resource with the
+// given name are reported, e.g. in an [InvariantViolation] or in an [*InvariantError].
+func RangeInvariant(resource string) string {
+	return "range of " + resource
+}
+
+// initMetadata validates the metadata of the resources of m and records their constraints.
+func (m *Executer) initMetadata() error {
+	res := m.memory.GetResources()
+	m.readOnly = readOnlyResources(stringset.Make())
+	m.ranges = make(map[string]memory.Metadata)
+	for name, meta := range res.Meta {
+		typ, present := m.types[name]
+		if !present {
+			return fmt.Errorf("metadata of unknown resource %s", name)
+		}
+		if meta.ReadOnly {
+			stringset.Set(m.readOnly).Insert(name)
+		}
+		if !meta.HasRange() {
+			continue
+		}
+		if typ != "Integer" && typ != "Float" {
+			return fmt.Errorf("the %s resource %s cannot have a range", typ, name)
+		}
+		if meta.Min != nil && meta.Max != nil && *meta.Min > *meta.Max {
+			return fmt.Errorf("invalid range %s of resource %s", meta.Range(), name)
+		}
+		m.ranges[name] = meta
+		if v, _ := m.numericValue(name); !meta.InRange(v) {
+			return fmt.Errorf("the value %v of resource %s is out of its range %s", m.resourceValue(name), name, meta.Range())
+		}
+	}
+	return nil
+}
+
+// numericValue returns the value of the Integer or Float resource with the given name.
+// It should be called while holding m.lockMemory.
+func (m *Executer) numericValue(name string) (float64, bool) {
+	v := m.resourceValue(name)
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// violatedRanges returns the names, as by RangeInvariant, of the ranges of the resources that do not
+// hold in the current state. It should be called while holding m.lockMemory.
+func (m *Executer) violatedRanges() []string {
+	var res []string
+	for name, meta := range m.ranges {
+		if v, _ := m.numericValue(name); !meta.InRange(v) {
+			res = append(res, RangeInvariant(name))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// hasInvariants reports whether the updates of m must be checked against some invariant or range.
+func (m *Executer) hasInvariants() bool {
+	return len(m.invariants) > 0 || len(m.ranges) > 0
+}
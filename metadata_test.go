@@ -0,0 +1,98 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/parser"
+)
+
+func metadataResources() memory.Resources {
+	mem := memory.MakeResources()
+	mem.Integer["speed"] = 0
+	mem.Bool["button"] = false
+	mem.Bool["running"] = false
+	mem.Meta["speed"] = memory.Metadata{Min: memory.Bound(-255), Max: memory.Bound(255), Unit: "pwm"}
+	mem.Meta["button"] = memory.Metadata{ReadOnly: true, Description: "start button"}
+	return mem
+}
+
+func TestMetadataValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(memory.Resources)
+	}{
+		{"unknown resource", func(r memory.Resources) { r.Meta["absent"] = memory.Metadata{ReadOnly: true} }},
+		{"range of a Bool", func(r memory.Resources) { r.Meta["running"] = memory.Metadata{Max: memory.Bound(1)} }},
+		{"empty range", func(r memory.Resources) {
+			r.Meta["speed"] = memory.Metadata{Min: memory.Bound(1), Max: memory.Bound(0)}
+		}},
+		{"out of range", func(r memory.Resources) { r.Integer["speed"] = 300 }},
+	}
+	for _, test := range tests {
+		mem := metadataResources()
+		test.modify(mem)
+		if _, err := NewExecuter(mem, nil, MakeMockAgent(), config.TestsLogConfig); err == nil {
+			t.Error(test.name, "should be rejected")
+		}
+	}
+	_, err := NewExecuter(metadataResources(), []string{"rule press on running for running do button = true"},
+		MakeMockAgent(), config.TestsLogConfig)
+	var perr *parser.Error
+	if !errors.As(err, &perr) || perr.Category != parser.CategoryReadOnly {
+		t.Error("assigning a read-only resource should be a parse error, got", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	e, err := NewExecuter(metadataResources(), []string{
+		"rule start on button for button do running = true, speed = 100",
+		"rule boost on speed for speed >= 200 do speed = speed * 2",
+	}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	err = e.Input("button = true")
+	if err != nil {
+		t.Fatal("the inputs should assign the read-only resources:", err)
+	}
+	for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+		e.Exec()
+	}
+	mem, _ := e.TakeState()
+	if !mem.Bool["running"] || mem.Integer["speed"] != 100 || mem.Metadata("speed").Unit != "pwm" {
+		t.Fatal("unexpected state:", mem)
+	}
+	err = e.Input("speed = 300")
+	var invErr *InvariantError
+	if !errors.As(err, &invErr) || len(invErr.Invariants) != 1 || invErr.Invariants[0] != RangeInvariant("speed") {
+		t.Error("the input should be rejected, got", err)
+	}
+	<-e.Violations()
+	err = e.Input("speed = 200")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Exec()
+	mem, pool := e.TakeState()
+	if mem.Integer["speed"] != 200 || len(pool) != 0 {
+		t.Error("the update of boost should be discarded, got", mem, pool)
+	}
+	select {
+	case v := <-e.Violations():
+		if v.Invariant != RangeInvariant("speed") || v.Update.Rule != "boost" || v.After["speed"] != int64(400) {
+			t.Error("unexpected violation:", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for a violation")
+	}
+}
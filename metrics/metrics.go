@@ -0,0 +1,73 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics defines the registry used by GoAbU components for exposing their runtime metrics,
+// along with an implementation serving them in the Prometheus text exposition format.
+package metrics
+
+// Registry creates the metrics of a component. The labels are specified as name-value pairs:
+// the metrics having the same name and different labels belong to the same family, asking again
+// for a metric having the same name and labels returns the same metric.
+// Implementations must be safe for concurrent use.
+type Registry interface {
+	// Counter returns the counter with the given name and labels.
+	Counter(name, help string, labels ...string) Counter
+	// Gauge returns the gauge with the given name and labels.
+	Gauge(name, help string, labels ...string) Gauge
+	// GaugeFunc registers a gauge with the given name and labels whose value is obtained by calling f.
+	GaugeFunc(name, help string, f func() float64, labels ...string)
+	// Histogram returns the histogram with the given name and labels.
+	Histogram(name, help string, labels ...string) Histogram
+}
+
+// Counter is a metric whose value can only increase.
+type Counter interface {
+	// Inc increases the counter by 1.
+	Inc()
+	// Add increases the counter by v, which should not be negative.
+	Add(v float64)
+}
+
+// Gauge is a metric whose value can increase and decrease.
+type Gauge interface {
+	// Set sets the gauge to v.
+	Set(v float64)
+	// Add adds v, possibly negative, to the gauge.
+	Add(v float64)
+}
+
+// Histogram counts the observed values in buckets.
+type Histogram interface {
+	// Observe adds v to the histogram.
+	Observe(v float64)
+}
+
+// DefaultBuckets are the upper bounds of the buckets of the histograms, they are meant for latencies in seconds.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Discard is a Registry whose metrics discard every value, it is used when no Registry is specified.
+var Discard Registry = discard{}
+
+type discard struct{}
+
+func (discard) Counter(string, string, ...string) Counter {
+	return discard{}
+}
+
+func (discard) Gauge(string, string, ...string) Gauge {
+	return discard{}
+}
+
+func (discard) GaugeFunc(string, string, func() float64, ...string) {}
+
+func (discard) Histogram(string, string, ...string) Histogram {
+	return discard{}
+}
+
+func (discard) Inc() {}
+
+func (discard) Add(float64) {}
+
+func (discard) Set(float64) {}
+
+func (discard) Observe(float64) {}
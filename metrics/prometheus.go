@@ -0,0 +1,267 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusRegistry is a Registry serving its metrics over HTTP in the Prometheus text exposition format.
+type PrometheusRegistry struct {
+	families map[string]*family
+	lock     sync.Mutex
+}
+
+// family groups the metrics having the same name.
+type family struct {
+	name   string
+	help   string
+	typ    string
+	series map[string]*series
+}
+
+// series is a metric of a family, identified by its labels.
+type series struct {
+	labels string
+	value  float64
+	f      func() float64
+	// buckets contains the cumulative counts of the buckets of a histogram.
+	buckets []uint64
+	count   uint64
+	lock    sync.Mutex
+}
+
+// NewPrometheusRegistry returns an empty PrometheusRegistry.
+func NewPrometheusRegistry() *PrometheusRegistry {
+	return &PrometheusRegistry{families: make(map[string]*family)}
+}
+
+// Counter returns the counter with the given name and labels.
+// It panics if the name or the labels are invalid or if name is used by a metric of a different type.
+func (r *PrometheusRegistry) Counter(name, help string, labels ...string) Counter {
+	return (*counter)(r.series("counter", name, help, labels))
+}
+
+// Gauge returns the gauge with the given name and labels.
+// It panics if the name or the labels are invalid or if name is used by a metric of a different type.
+func (r *PrometheusRegistry) Gauge(name, help string, labels ...string) Gauge {
+	return (*gauge)(r.series("gauge", name, help, labels))
+}
+
+// GaugeFunc registers a gauge with the given name and labels whose value is obtained by calling f,
+// replacing the function of the gauge with the same name and labels (if any). f is called while serving
+// the metrics. It panics if the name or the labels are invalid or if name is used by a metric of a different type.
+func (r *PrometheusRegistry) GaugeFunc(name, help string, f func() float64, labels ...string) {
+	s := r.series("gauge", name, help, labels)
+	s.lock.Lock()
+	s.f = f
+	s.lock.Unlock()
+}
+
+// Histogram returns the histogram with the given name and labels, its buckets are [DefaultBuckets].
+// It panics if the name or the labels are invalid or if name is used by a metric of a different type.
+func (r *PrometheusRegistry) Histogram(name, help string, labels ...string) Histogram {
+	s := r.series("histogram", name, help, labels)
+	s.lock.Lock()
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(DefaultBuckets))
+	}
+	s.lock.Unlock()
+	return (*histogram)(s)
+}
+
+// series returns the series having the given name and labels, creating it if needed.
+func (r *PrometheusRegistry) series(typ, name, help string, labels []string) *series {
+	if !validName(name) {
+		panic(fmt.Sprintf("invalid metric name %q", name))
+	}
+	key := encodeLabels(labels)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	fam, ok := r.families[name]
+	if !ok {
+		fam = &family{name: name, help: help, typ: typ, series: make(map[string]*series)}
+		r.families[name] = fam
+	}
+	if fam.typ != typ {
+		panic(fmt.Sprintf("metric %s is a %s, not a %s", name, fam.typ, typ))
+	}
+	s, ok := fam.series[key]
+	if !ok {
+		s = &series{labels: key}
+		fam.series[key] = s
+	}
+	return s
+}
+
+// ServeHTTP writes the metrics of r in the Prometheus text exposition format.
+func (r *PrometheusRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	b := bufio.NewWriter(w)
+	r.lock.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]*family, 0, len(names))
+	for _, name := range names {
+		families = append(families, r.families[name])
+	}
+	all := make([][]*series, 0, len(families))
+	for _, fam := range families {
+		keys := make([]string, 0, len(fam.series))
+		for k := range fam.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		ss := make([]*series, 0, len(keys))
+		for _, k := range keys {
+			ss = append(ss, fam.series[k])
+		}
+		all = append(all, ss)
+	}
+	r.lock.Unlock()
+	// the functions of the gauges are called without holding r.lock
+	for i, fam := range families {
+		fmt.Fprintf(b, "# HELP %s %s\n", fam.name, escapeHelp(fam.help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", fam.name, fam.typ)
+		for _, s := range all[i] {
+			s.write(b, fam)
+		}
+	}
+	b.Flush()
+}
+
+// write writes the samples of s, a series of fam.
+func (s *series) write(b *bufio.Writer, fam *family) {
+	s.lock.Lock()
+	value, f := s.value, s.f
+	buckets := append([]uint64(nil), s.buckets...)
+	count := s.count
+	s.lock.Unlock()
+	if f != nil {
+		value = f()
+	}
+	if fam.typ != "histogram" {
+		fmt.Fprintf(b, "%s%s %s\n", fam.name, braces(s.labels), formatFloat(value))
+		return
+	}
+	for i, bound := range DefaultBuckets {
+		fmt.Fprintf(b, "%s_bucket%s %d\n", fam.name, braces(joinLabels(s.labels, `le="`+formatFloat(bound)+`"`)), buckets[i])
+	}
+	fmt.Fprintf(b, "%s_bucket%s %d\n", fam.name, braces(joinLabels(s.labels, `le="+Inf"`)), count)
+	fmt.Fprintf(b, "%s_sum%s %s\n", fam.name, braces(s.labels), formatFloat(value))
+	fmt.Fprintf(b, "%s_count%s %d\n", fam.name, braces(s.labels), count)
+}
+
+type counter series
+
+func (c *counter) Inc() {
+	c.Add(1)
+}
+
+func (c *counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.lock.Lock()
+	c.value += v
+	c.lock.Unlock()
+}
+
+type gauge series
+
+func (g *gauge) Set(v float64) {
+	g.lock.Lock()
+	g.value = v
+	g.lock.Unlock()
+}
+
+func (g *gauge) Add(v float64) {
+	g.lock.Lock()
+	g.value += v
+	g.lock.Unlock()
+}
+
+type histogram series
+
+func (h *histogram) Observe(v float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for i, bound := range DefaultBuckets {
+		if v <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.value += v
+}
+
+// validName reports whether name is a valid metric or label name.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !(c == '_' || c == ':' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeLabels returns the encoding of the given name-value pairs, sorted by name, as they appear
+// between the braces of a sample. It panics if the labels are invalid.
+func encodeLabels(labels []string) string {
+	if len(labels)%2 != 0 {
+		panic("labels must be name-value pairs")
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i < len(labels); i += 2 {
+		if !validName(labels[i]) || strings.Contains(labels[i], ":") || labels[i] == "le" {
+			panic(fmt.Sprintf("invalid label name %q", labels[i]))
+		}
+		pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
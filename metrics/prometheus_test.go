@@ -0,0 +1,99 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusRegistry(t *testing.T) {
+	r := NewPrometheusRegistry()
+	r.Counter("goabu_test_total", "Test counter.").Add(2)
+	r.Counter("goabu_test_total", "Test counter.").Inc()
+	r.Counter("goabu_labelled_total", "Labelled counter.", "phase", "first", "role", "coordinator").Inc()
+	r.Counter("goabu_labelled_total", "Labelled counter.", "role", "coordinator", "phase", "second").Add(4)
+	g := r.Gauge("goabu_gauge", "Test gauge.")
+	g.Set(10)
+	g.Add(-2.5)
+	length := 3
+	r.GaugeFunc("goabu_length", "Test gauge function.", func() float64 { return float64(length) })
+	h := r.Histogram("goabu_latency_seconds", "Test histogram.", "phase", "interest")
+	h.Observe(0.003)
+	h.Observe(0.2)
+	h.Observe(20)
+	length = 7
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Error("unexpected content type:", resp.Header.Get("Content-Type"))
+	}
+	expected := `# HELP goabu_gauge Test gauge.
+# TYPE goabu_gauge gauge
+goabu_gauge 7.5
+# HELP goabu_labelled_total Labelled counter.
+# TYPE goabu_labelled_total counter
+goabu_labelled_total{phase="first",role="coordinator"} 1
+goabu_labelled_total{phase="second",role="coordinator"} 4
+# HELP goabu_latency_seconds Test histogram.
+# TYPE goabu_latency_seconds histogram
+goabu_latency_seconds_bucket{phase="interest",le="0.001"} 0
+goabu_latency_seconds_bucket{phase="interest",le="0.005"} 1
+goabu_latency_seconds_bucket{phase="interest",le="0.01"} 1
+goabu_latency_seconds_bucket{phase="interest",le="0.025"} 1
+goabu_latency_seconds_bucket{phase="interest",le="0.05"} 1
+goabu_latency_seconds_bucket{phase="interest",le="0.1"} 1
+goabu_latency_seconds_bucket{phase="interest",le="0.25"} 2
+goabu_latency_seconds_bucket{phase="interest",le="0.5"} 2
+goabu_latency_seconds_bucket{phase="interest",le="1"} 2
+goabu_latency_seconds_bucket{phase="interest",le="2.5"} 2
+goabu_latency_seconds_bucket{phase="interest",le="5"} 2
+goabu_latency_seconds_bucket{phase="interest",le="10"} 2
+goabu_latency_seconds_bucket{phase="interest",le="+Inf"} 3
+goabu_latency_seconds_sum{phase="interest"} 20.203
+goabu_latency_seconds_count{phase="interest"} 3
+# HELP goabu_length Test gauge function.
+# TYPE goabu_length gauge
+goabu_length 7
+# HELP goabu_test_total Test counter.
+# TYPE goabu_test_total counter
+goabu_test_total 3
+`
+	if string(body) != expected {
+		t.Errorf("unexpected exposition:\n%s\nexpected:\n%s", body, expected)
+	}
+}
+
+func TestPrometheusRegistryMisuse(t *testing.T) {
+	r := NewPrometheusRegistry()
+	r.Counter("goabu_total", "Counter.")
+	for name, f := range map[string]func(){
+		"type":   func() { r.Gauge("goabu_total", "Gauge.") },
+		"name":   func() { r.Counter("goabu-total", "Counter.") },
+		"labels": func() { r.Counter("goabu_total", "Counter.", "phase") },
+		"le":     func() { r.Histogram("goabu_seconds", "Histogram.", "le", "1") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("misuse %s should panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
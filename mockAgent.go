@@ -5,12 +5,19 @@ package goabu
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 )
 
 type MockAgent struct {
 	running           bool
 	operations        chan chan []byte
 	operationCommands chan chan string
+	// transactions counts the transactions initiated by the MockAgent.
+	transactions atomic.Int64
+	// received maps the commands channels provided by ReceivedActions to the identifiers of their transactions.
+	received sync.Map
 }
 
 func MakeMockAgent() Agent {
@@ -49,6 +56,7 @@ func (a *MockAgent) ForAll(actions []byte) error {
 	}
 	actionsCh := make(chan []byte)
 	commandsCh := make(chan string)
+	a.received.Store(commandsCh, fmt.Sprintf("%s->%d", a.ID(), a.transactions.Add(1)-1))
 	a.operations <- actionsCh
 	a.operationCommands <- commandsCh
 	actionsCh <- actions
@@ -66,6 +74,14 @@ func (a *MockAgent) ReceivedActions() (<-chan chan []byte, <-chan chan string) {
 	return a.operations, a.operationCommands
 }
 
+// ReceivedTransaction returns the identifier of the transaction whose commands are exchanged over commands,
+// a channel provided by ReceivedActions, and forgets it.
+func (a *MockAgent) ReceivedTransaction(commands chan string) string {
+	id, _ := a.received.LoadAndDelete(commands)
+	res, _ := id.(string)
+	return res
+}
+
 func (a *MockAgent) Stop() error {
 	if !a.running {
 		return errors.New("agent is not running")
@@ -76,3 +92,8 @@ func (a *MockAgent) Stop() error {
 }
 
 func (a *MockAgent) SetLogLevel(l int) {}
+
+// ID returns the identifier shared by every MockAgent.
+func (a *MockAgent) ID() string {
+	return "mock"
+}
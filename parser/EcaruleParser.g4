@@ -11,11 +11,17 @@ options {tokenVocab=EcaruleLexer;}
 prules : prule+ ;
 
 /* Rule. */
-prule : RULE SIMPLENAME ON events defaultActions? task+ ;
+prule : RULE SIMPLENAME salience? (ON events | timer) defaultActions? task+ ;
 
 /* Events. */
 events : SIMPLENAME+ ;
 
+/* Timer: "every" duration or "after" duration ON events (every and after are not reserved words). */
+timer : SIMPLENAME duration (ON events)? ;
+
+/* Duration: e.g. 1h30m or 1.5s. */
+duration : ((DEC_LIT | DECIMAL_FLOAT_LIT) SIMPLENAME)+ ;
+
 /* Default actions. */
 defaultActions : DEFAULT actions ;
 
@@ -73,6 +73,18 @@ func (l baseParserState) EnterEvents(ctx *antlr_parser.EventsContext) {}
 // ExitEvents is called when production events is exited.
 func (l baseParserState) ExitEvents(ctx *antlr_parser.EventsContext) {}
 
+// EnterTimer is called when production timer is entered.
+func (l baseParserState) EnterTimer(ctx *antlr_parser.TimerContext) {}
+
+// ExitTimer is called when production timer is exited.
+func (l baseParserState) ExitTimer(ctx *antlr_parser.TimerContext) {}
+
+// EnterDuration is called when production duration is entered.
+func (l baseParserState) EnterDuration(ctx *antlr_parser.DurationContext) {}
+
+// ExitDuration is called when production duration is exited.
+func (l baseParserState) ExitDuration(ctx *antlr_parser.DurationContext) {}
+
 // EnterDefaultActions is called when production defaultActions is entered.
 func (l baseParserState) EnterDefaultActions(ctx *antlr_parser.DefaultActionsContext) {}
 
@@ -0,0 +1,199 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	antlr_parser "github.com/abu-lang/goabu/parser/internal/antlr"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+)
+
+// Category classifies the errors found by the parser.
+type Category int
+
+const (
+	// CategorySyntax marks the inputs that do not follow the grammar of the GoAbU rules.
+	CategorySyntax Category = iota
+	// CategoryUnknownResource marks the references to resources that the node does not have.
+	CategoryUnknownResource
+	// CategoryTypeMismatch marks the values whose type does not fit their use, e.g. a Text value
+	// assigned to an Integer resource.
+	CategoryTypeMismatch
+	// CategoryReservedWord marks the reserved words used where an identifier is expected.
+	CategoryReservedWord
+	// CategoryUndefinedFunction marks the calls to functions that are neither built-in nor registered.
+	CategoryUndefinedFunction
+	// CategoryInvalid marks the other constructs that are not allowed, e.g. a local action in a 'for all' task.
+	CategoryInvalid
+	// CategoryReadOnly marks the assignments of read-only resources by the rules and by the received tasks.
+	CategoryReadOnly
+)
+
+// String returns the name of the category.
+func (c Category) String() string {
+	switch c {
+	case CategorySyntax:
+		return "syntax"
+	case CategoryUnknownResource:
+		return "unknown resource"
+	case CategoryTypeMismatch:
+		return "type mismatch"
+	case CategoryReservedWord:
+		return "reserved word"
+	case CategoryUndefinedFunction:
+		return "undefined function"
+	case CategoryInvalid:
+		return "invalid"
+	case CategoryReadOnly:
+		return "read-only"
+	default:
+		return fmt.Sprintf("Category(%d)", int(c))
+	}
+}
+
+// Error is an error found by the parser, the methods of the parser return an Error for each
+// of the errors that they find.
+type Error struct {
+	// Rule is the index of the argument of Parse or ParseExpressions, or of the task passed to ParseRemoteTasks,
+	// containing the error; it is -1 for the errors of ParseActions.
+	Rule int
+	// Line is the line of the error, starting from 1, it is 0 when the position is unknown.
+	Line int
+	// Column is the column of the error, starting from 0.
+	Column int
+	// Token is the text of the offending token.
+	Token string
+	// Category classifies the error.
+	Category Category
+	// Message describes the error.
+	Message string
+	// Suggestions contains the possible replacements of Token, e.g. the names of the resources
+	// that are the closest to an unknown one.
+	Suggestions []string
+}
+
+// Error returns a description of the error including its position.
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Rule >= 0 {
+		fmt.Fprintf(&b, "rule #%d ", e.Rule)
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "at %d:%d", e.Line, e.Column)
+	}
+	if b.Len() > 0 {
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	if len(e.Suggestions) > 0 {
+		fmt.Fprintf(&b, ", did you mean %s?", strings.Join(e.Suggestions, " or "))
+	}
+	return b.String()
+}
+
+// locate sets the position of e to the one of token, unless it is already known.
+func (e *Error) locate(token antlr.Token) {
+	if e.Line > 0 || token == nil {
+		return
+	}
+	e.Line = token.GetLine()
+	e.Column = token.GetColumn()
+	if e.Token == "" {
+		e.Token = token.GetText()
+	}
+}
+
+// newError returns an Error of the given category whose position is not known yet.
+func newError(category Category, format string, args ...any) *Error {
+	return &Error{Rule: -1, Category: category, Message: fmt.Sprintf(format, args...)}
+}
+
+// unknownResourceError returns the error for the unknown resource name, suggesting the closest names in types.
+func unknownResourceError(name string, types map[string]string) *Error {
+	res := newError(CategoryUnknownResource, "could not determine the type of %s", name)
+	res.Token = name
+	res.Suggestions = closest(name, types)
+	return res
+}
+
+// closest returns at most three of the keys of candidates that are the closest to name, i.e. that can be
+// obtained from it by at most a third of its length of insertions, deletions and substitutions of characters.
+func closest[T any](name string, candidates map[string]T) []string {
+	threshold := max(1, len(name)/3)
+	var res []string
+	distances := make(map[string]int)
+	for c := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d <= threshold {
+			res = append(res, c)
+			distances[c] = d
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if distances[res[i]] != distances[res[j]] {
+			return distances[res[i]] < distances[res[j]]
+		}
+		return res[i] < res[j]
+	})
+	if len(res) > 3 {
+		res = res[:3]
+	}
+	return res
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	r, s := []rune(a), []rune(b)
+	prev := make([]int, len(s)+1)
+	curr := make([]int, len(s)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(r); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s); j++ {
+			cost := 1
+			if r[i-1] == s[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(s)]
+}
+
+// errorListener is the antlr.ErrorListener of goabuParser, it reports the syntax errors as Errors to reporter.
+type errorListener struct {
+	*antlr.DefaultErrorListener
+	reporter *pkg.GruleErrorReporter
+}
+
+// SyntaxError is called by ANTLR upon a syntax error.
+func (l errorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
+	res := &Error{Rule: -1, Line: line, Column: column, Category: CategorySyntax, Message: msg}
+	if token, ok := offendingSymbol.(antlr.Token); ok {
+		res.Token = token.GetText()
+	}
+	if p, ok := recognizer.(antlr.Parser); ok && res.Token != "" && isReservedWord(res.Token) &&
+		p.IsExpectedToken(antlr_parser.EcaruleParserSIMPLENAME) {
+		res.Category = CategoryReservedWord
+		res.Message = fmt.Sprintf("%s is a reserved word and cannot be used as an identifier", res.Token)
+	}
+	l.reporter.AddError(res)
+}
+
+// isReservedWord reports whether word looks like an identifier but cannot be used as one.
+func isReservedWord(word string) bool {
+	for i, c := range word {
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return !ValidateIdentifiers(word)[0]
+}
@@ -32,6 +32,10 @@ func (t *expressionReceiver) AcceptAssignment(a *ast.Assignment) error {
 	if !a.IsAssign {
 		return fmt.Errorf("assignment %s only assignment operator '=' is supported", a.GetGrlText())
 	}
+	if isElement(a.Variable) {
+		return newError(CategoryInvalid, "cannot assign %s: the elements of List and Map resources cannot be assigned, "+
+			"assign the whole resource instead (e.g. by means of Append or Put)", a.Variable.GetGrlText())
+	}
 	n := ""
 	if t.validAssignment(a) {
 		n = strings.Trim(a.Variable.ArrayMapSelector.Expression.ExpressionAtom.Constant.GetGrlText(), `"`)
@@ -39,6 +43,10 @@ func (t *expressionReceiver) AcceptAssignment(a *ast.Assignment) error {
 	if n == "" {
 		return errors.New("invalid assignment: " + a.GetGrlText())
 	}
+	if typ, value := resourceType(a.Variable), staticType(a.Expression); !compatibleTypes(typ, value) {
+		return newError(CategoryTypeMismatch, "type mismatch: cannot assign the %s value %s to the %s resource %s",
+			value, a.Expression.GetGrlText(), typ, n)
+	}
 	t.Actions = append(t.Actions, ecarule.Action{Resource: n, Assignment: a})
 	return nil
 }
@@ -79,7 +87,7 @@ func (t *expressionReceiver) validAssignment(a *ast.Assignment) bool {
 // isTypeOk reports whether the resource type is coherent with the current task.
 func (t *expressionReceiver) isTypeOk(prefix, typ string) bool {
 	switch typ {
-	case "Bool", "Integer", "Float", "Text", "Time", "Other":
+	case "Bool", "Integer", "Float", "Text", "Time", "Other", "List", "Map":
 		if prefix == "this" || (t.isReceivedTask && prefix == "ext") {
 			return true
 		}
@@ -4,6 +4,7 @@
 package parser
 
 import (
+	"reflect"
 	"sync"
 
 	"github.com/abu-lang/goabu/ecarule"
@@ -39,34 +40,108 @@ type goabuParser struct {
 	lockMemory sync.Locker
 }
 
+// Functions reports which functions can be called in the parsed rules.
+type Functions interface {
+	// HasFunction reports whether the function name can be called.
+	HasFunction(name string) bool
+}
+
+// ReadOnly reports which local resources cannot be assigned by the rules and by the tasks received from
+// the other nodes, e.g. the ones reflecting the state of a sensor.
+type ReadOnly interface {
+	// IsReadOnly reports whether the resource name is read-only.
+	IsReadOnly(name string) bool
+}
+
+// Option configures the parsers created by New.
+type Option func(*options)
+
+// options contains the configuration of a parser.
+type options struct {
+	locker    sync.Locker
+	functions Functions
+	readOnly  ReadOnly
+	// invalid reports whether some option was given a nil value.
+	invalid bool
+}
+
+// WithLocker makes the parser acquire l before performing actions on the [*ast.WorkingMemory].
+func WithLocker(l sync.Locker) Option {
+	return func(o *options) {
+		o.locker = l
+		o.invalid = o.invalid || isNil(l)
+	}
+}
+
+// WithFunctions makes calling a function that f does not have a parse error, except in the remote tasks
+// of the rules which are evaluated by the other nodes.
+func WithFunctions(f Functions) Option {
+	return func(o *options) {
+		o.functions = f
+		o.invalid = o.invalid || isNil(f)
+	}
+}
+
+// WithReadOnly makes assigning a resource that r reports as read-only a parse error in the local tasks
+// of the rules and in the received tasks, whereas the actions parsed by ParseActions can assign it.
+func WithReadOnly(r ReadOnly) Option {
+	return func(o *options) {
+		o.readOnly = r
+		o.invalid = o.invalid || isNil(r)
+	}
+}
+
+// isNil reports whether v is nil or holds a nil pointer, map, slice, function or channel.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
 // New takes as arguments the types of the local resources specified as [github.com/abu-lang/goabu/memory.Resources.Types]
-// and an [*ast.WorkingMemory] and creates an [ecarule.Parser]. The parsed expressions will be added to the
-// [*ast.WorkingMemory].
+// and an [*ast.WorkingMemory] and creates an [ecarule.Parser] configured by the [Option]s in args, if an option is given
+// more than once the last one is used. The parsed expressions will be added to the [*ast.WorkingMemory].
 //
-// If a single [sync.Locker] is passed as argument then the parser will acquire the lock before performing
-// actions on the [*ast.WorkingMemory]. Only a single [sync.Locker] can be passed as argument.
+// For compatibility, a [sync.Locker] passed in args is equivalent to passing it to WithLocker.
+// New returns nil if args contains something else or if an option is given a nil value, also if it is a nil pointer or map.
 func New(types map[string]string, workingMemory *ast.WorkingMemory, args ...any) ecarule.Parser {
-	if len(args) > 1 {
-		return nil
-	}
-	res := &goabuParser{errListener: &pkg.GruleErrorReporter{Errors: make([]error, 0)}}
-	if len(args) > 0 {
-		locker, ok := args[0].(sync.Locker)
-		if !ok || locker == nil {
+	var o options
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case Option:
+			if a == nil {
+				return nil
+			}
+			a(&o)
+		case sync.Locker:
+			WithLocker(a)(&o)
+		default:
 			return nil
 		}
-		res.lockMemory = locker
-	} else {
+	}
+	if o.invalid {
+		return nil
+	}
+	res := &goabuParser{errListener: &pkg.GruleErrorReporter{Errors: make([]error, 0)}, lockMemory: o.locker}
+	functions, readOnly := o.functions, o.readOnly
+	if res.lockMemory == nil {
 		res.lockMemory = brokenLock{}
 	}
 	res.lexer = antlr_parser.NewEcaruleLexer(antlr.NewInputStream(""))
 	res.lexer.RemoveErrorListeners()
-	res.lexer.AddErrorListener(res.errListener)
+	res.lexer.AddErrorListener(errorListener{reporter: res.errListener})
 	res.parser = antlr_parser.NewEcaruleParser(antlr.NewCommonTokenStream(res.lexer, antlr.TokenDefaultChannel))
 	res.parser.BuildParseTrees = true
 	res.parser.RemoveErrorListeners()
-	res.parser.AddErrorListener(res.errListener)
+	res.parser.AddErrorListener(errorListener{reporter: res.errListener})
 	res.listener = newRuleParser(types, workingMemory, res.errListener)
+	res.listener.functions = functions
+	res.listener.readOnly = readOnly
 	return res
 }
 
@@ -78,30 +153,43 @@ func (p *goabuParser) reset(input string) {
 	p.parser.SetInputStream(ts)
 }
 
-// errors retrieves the errors encountered during the parsing.
-func (lp *goabuParser) errors() []error {
-	return lp.errListener.Errors
+// errors retrieves the errors encountered during the parsing as [*Error]s related to the argument with index rule.
+func (lp *goabuParser) errors(rule int) []error {
+	res := make([]error, 0, len(lp.errListener.Errors))
+	for _, err := range lp.errListener.Errors {
+		e, ok := err.(*Error)
+		if !ok {
+			e = newError(CategoryInvalid, "%s", err.Error())
+		}
+		e.Rule = rule
+		res = append(res, e)
+	}
+	return res
 }
 
-// Parse parses a series of GoAbU rules.
+// Parse parses a series of GoAbU rules, it returns the errors found in all of them.
 func (p *goabuParser) Parse(rules ...string) ([]ecarule.Rule, []error) {
 	res := make([]ecarule.Rule, 0, len(rules))
 	p.lockMemory.Lock()
 	defer p.lockMemory.Unlock()
-	for _, r := range rules {
+	var errs []error
+	for i, r := range rules {
 		p.reset(r)
 		tree := p.parser.Prules()
-		errs := p.errors()
-		if len(errs) > 0 {
-			return nil, errs
+		if ruleErrs := p.errors(i); len(ruleErrs) > 0 {
+			errs = append(errs, ruleErrs...)
+			continue
 		}
 		antlr.ParseTreeWalkerDefault.Walk(p.listener, tree)
-		errs = p.errors()
-		if len(errs) > 0 {
-			return nil, errs
+		if ruleErrs := p.errors(i); len(ruleErrs) > 0 {
+			errs = append(errs, ruleErrs...)
+			continue
 		}
 		res = append(res, p.listener.rules...)
 	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
 	// update WorkingMemory
 	p.listener.local.KnowledgeBase.WorkingMemory.IndexVariables()
 	return res, nil
@@ -111,18 +199,21 @@ func (p *goabuParser) Parse(rules ...string) ([]ecarule.Rule, []error) {
 func (p *goabuParser) ParseActions(actions string) ([]ecarule.Action, []error) {
 	p.reset(actions)
 	tree := p.parser.Actions()
-	errs := p.errors()
+	errs := p.errors(-1)
 	if len(errs) > 0 {
 		return nil, errs
 	}
 	task := ecarule.LocalTask{}
 	p.listener.push(&expressionReceiver{&task, nil, false})
 	p.lockMemory.Lock()
+	// the inputs can assign the read-only resources
+	p.listener.inputs = true
 	antlr.ParseTreeWalkerDefault.Walk(p.listener, tree)
+	p.listener.inputs = false
 	// update WorkingMemory
 	p.listener.local.KnowledgeBase.WorkingMemory.IndexVariables()
 	p.lockMemory.Unlock()
-	errs = p.errors()
+	errs = p.errors(-1)
 	if len(errs) > 0 {
 		return nil, errs
 	}
@@ -135,16 +226,16 @@ func (p *goabuParser) ParseExpressions(exps ...string) ([]*ast.Expression, []err
 	p.lockMemory.Lock()
 	defer p.lockMemory.Unlock()
 	task := ecarule.LocalTask{}
-	for _, exp := range exps {
+	for i, exp := range exps {
 		p.reset(exp)
 		p.listener.push(&expressionReceiver{&task, nil, false})
 		tree := p.parser.Expression()
-		errs := p.errors()
+		errs := p.errors(i)
 		if len(errs) > 0 {
 			return nil, errs
 		}
 		antlr.ParseTreeWalkerDefault.Walk(p.listener, tree)
-		errs = p.errors()
+		errs = p.errors(i)
 		if len(errs) > 0 {
 			return nil, errs
 		}
@@ -161,7 +252,7 @@ func (p *goabuParser) ParseRemoteTasks(remoteTypes map[string]string, tasks ...e
 	res := make([]ecarule.LocalTask, 0)
 	p.lockMemory.Lock()
 	defer p.lockMemory.Unlock()
-	for _, rTask := range tasks {
+	for i, rTask := range tasks {
 		str := "for " + rTask.Condition + " do "
 		for _, act := range rTask.Actions {
 			str += act
@@ -169,14 +260,14 @@ func (p *goabuParser) ParseRemoteTasks(remoteTypes map[string]string, tasks ...e
 		}
 		p.reset(str)
 		tree := p.parser.Task()
-		errs := p.errors()
+		errs := p.errors(i)
 		if len(errs) > 0 {
 			return nil, errs
 		}
 		p.listener.received.remoteTypes = remoteTypes
 		p.listener.parserState = p.listener.received
 		antlr.ParseTreeWalkerDefault.Walk(p.listener, tree)
-		errs = p.errors()
+		errs = p.errors(i)
 		if len(errs) > 0 {
 			return nil, errs
 		}
@@ -52,11 +52,11 @@ func ecaruleparserParserInit() {
 		"variable", "arrayMapSelector", "memberVariable", "functionCall", "methodCall",
 		"argumentList", "floatLiteral", "decimalFloatLiteral", "hexadecimalFloatLiteral",
 		"integerLiteral", "decimalLiteral", "hexadecimalLiteral", "octalLiteral",
-		"stringLiteral", "booleanLiteral",
+		"stringLiteral", "booleanLiteral", "timer", "duration",
 	}
 	staticData.predictionContextCache = antlr.NewPredictionContextCache()
 	staticData.serializedATN = []int32{
-		4, 1, 55, 324, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
+		4, 1, 55, 346, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
 		4, 2, 5, 7, 5, 2, 6, 7, 6, 2, 7, 7, 7, 2, 8, 7, 8, 2, 9, 7, 9, 2, 10, 7,
 		10, 2, 11, 7, 11, 2, 12, 7, 12, 2, 13, 7, 13, 2, 14, 7, 14, 2, 15, 7, 15,
 		2, 16, 7, 16, 2, 17, 7, 17, 2, 18, 7, 18, 2, 19, 7, 19, 2, 20, 7, 20, 2,
@@ -89,15 +89,18 @@ func ecaruleparserParserInit() {
 		34, 296, 8, 34, 1, 34, 1, 34, 1, 35, 1, 35, 1, 35, 3, 35, 303, 8, 35, 1,
 		36, 3, 36, 306, 8, 36, 1, 36, 1, 36, 1, 37, 3, 37, 311, 8, 37, 1, 37, 1,
 		37, 1, 38, 3, 38, 316, 8, 38, 1, 38, 1, 38, 1, 39, 1, 39, 1, 40, 1, 40,
-		1, 40, 0, 3, 36, 48, 52, 41, 0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22,
-		24, 26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58,
-		60, 62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 0, 6, 1, 0, 39, 40, 1, 0, 26,
-		30, 1, 0, 4, 6, 2, 0, 2, 3, 36, 37, 2, 0, 25, 25, 31, 35, 1, 0, 20, 21,
-		323, 0, 83, 1, 0, 0, 0, 2, 87, 1, 0, 0, 0, 4, 100, 1, 0, 0, 0, 6, 104,
-		1, 0, 0, 0, 8, 107, 1, 0, 0, 0, 10, 115, 1, 0, 0, 0, 12, 121, 1, 0, 0,
-		0, 14, 125, 1, 0, 0, 0, 16, 130, 1, 0, 0, 0, 18, 135, 1, 0, 0, 0, 20, 148,
-		1, 0, 0, 0, 22, 151, 1, 0, 0, 0, 24, 153, 1, 0, 0, 0, 26, 155, 1, 0, 0,
-		0, 28, 158, 1, 0, 0, 0, 30, 164, 1, 0, 0, 0, 32, 170, 1, 0, 0, 0, 34, 172,
+		1, 40, 1, 1, 8, 1, 3, 1, 325, 2, 41, 7, 41, 2, 42, 7, 42, 1, 1, 8, 1, 3,
+		1, 332, 1, 41, 1, 41, 1, 41, 1, 41, 8, 41, 3, 41, 338, 1, 42, 1, 42, 8,
+		42, 4, 42, 342, 11, 42, 12, 42, 344, 0, 3, 36, 48, 52, 43, 0, 2, 4, 6,
+		8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40, 42,
+		44, 46, 48, 50, 52, 54, 56, 58, 60, 62, 64, 66, 68, 70, 72, 74, 76, 78,
+		80, 327, 329, 0, 7, 1, 0, 39, 40, 1, 0, 26, 30, 1, 0, 4, 6, 2, 0, 2, 3,
+		36, 37, 2, 0, 25, 25, 31, 35, 1, 0, 20, 21, 2, 0, 41, 41, 45, 45, 347,
+		0, 83, 1, 0, 0, 0, 2, 87, 1, 0, 0, 0, 4, 100, 1, 0, 0, 0, 6, 104, 1, 0,
+		0, 0, 8, 107, 1, 0, 0, 0, 10, 115, 1, 0, 0, 0, 12, 121, 1, 0, 0, 0, 14,
+		125, 1, 0, 0, 0, 16, 130, 1, 0, 0, 0, 18, 135, 1, 0, 0, 0, 20, 148, 1,
+		0, 0, 0, 22, 151, 1, 0, 0, 0, 24, 153, 1, 0, 0, 0, 26, 155, 1, 0, 0, 0,
+		28, 158, 1, 0, 0, 0, 30, 164, 1, 0, 0, 0, 32, 170, 1, 0, 0, 0, 34, 172,
 		1, 0, 0, 0, 36, 185, 1, 0, 0, 0, 38, 212, 1, 0, 0, 0, 40, 214, 1, 0, 0,
 		0, 42, 216, 1, 0, 0, 0, 44, 218, 1, 0, 0, 0, 46, 220, 1, 0, 0, 0, 48, 228,
 		1, 0, 0, 0, 50, 246, 1, 0, 0, 0, 52, 248, 1, 0, 0, 0, 54, 260, 1, 0, 0,
@@ -106,94 +109,102 @@ func ecaruleparserParserInit() {
 		0, 70, 302, 1, 0, 0, 0, 72, 305, 1, 0, 0, 0, 74, 310, 1, 0, 0, 0, 76, 315,
 		1, 0, 0, 0, 78, 319, 1, 0, 0, 0, 80, 321, 1, 0, 0, 0, 82, 84, 3, 2, 1,
 		0, 83, 82, 1, 0, 0, 0, 84, 85, 1, 0, 0, 0, 85, 83, 1, 0, 0, 0, 85, 86,
-		1, 0, 0, 0, 86, 1, 1, 0, 0, 0, 87, 88, 5, 15, 0, 0, 88, 89, 5, 38, 0, 0,
-		89, 90, 5, 51, 0, 0, 90, 92, 3, 4, 2, 0, 91, 93, 3, 6, 3, 0, 92, 91, 1,
-		0, 0, 0, 92, 93, 1, 0, 0, 0, 93, 95, 1, 0, 0, 0, 94, 96, 3, 8, 4, 0, 95,
-		94, 1, 0, 0, 0, 96, 97, 1, 0, 0, 0, 97, 95, 1, 0, 0, 0, 97, 98, 1, 0, 0,
-		0, 98, 3, 1, 0, 0, 0, 99, 101, 5, 38, 0, 0, 100, 99, 1, 0, 0, 0, 101, 102,
-		1, 0, 0, 0, 102, 100, 1, 0, 0, 0, 102, 103, 1, 0, 0, 0, 103, 5, 1, 0, 0,
-		0, 104, 105, 5, 52, 0, 0, 105, 106, 3, 10, 5, 0, 106, 7, 1, 0, 0, 0, 107,
-		109, 5, 53, 0, 0, 108, 110, 5, 54, 0, 0, 109, 108, 1, 0, 0, 0, 109, 110,
-		1, 0, 0, 0, 110, 111, 1, 0, 0, 0, 111, 112, 3, 36, 18, 0, 112, 113, 5,
-		55, 0, 0, 113, 114, 3, 10, 5, 0, 114, 9, 1, 0, 0, 0, 115, 116, 3, 34, 17,
-		0, 116, 117, 3, 12, 6, 0, 117, 11, 1, 0, 0, 0, 118, 119, 5, 1, 0, 0, 119,
-		122, 3, 14, 7, 0, 120, 122, 1, 0, 0, 0, 121, 118, 1, 0, 0, 0, 121, 120,
-		1, 0, 0, 0, 122, 13, 1, 0, 0, 0, 123, 126, 3, 10, 5, 0, 124, 126, 1, 0,
-		0, 0, 125, 123, 1, 0, 0, 0, 125, 124, 1, 0, 0, 0, 126, 15, 1, 0, 0, 0,
-		127, 129, 3, 18, 9, 0, 128, 127, 1, 0, 0, 0, 129, 132, 1, 0, 0, 0, 130,
-		128, 1, 0, 0, 0, 130, 131, 1, 0, 0, 0, 131, 133, 1, 0, 0, 0, 132, 130,
-		1, 0, 0, 0, 133, 134, 5, 0, 0, 1, 134, 17, 1, 0, 0, 0, 135, 136, 5, 15,
-		0, 0, 136, 138, 3, 22, 11, 0, 137, 139, 3, 24, 12, 0, 138, 137, 1, 0, 0,
-		0, 138, 139, 1, 0, 0, 0, 139, 141, 1, 0, 0, 0, 140, 142, 3, 20, 10, 0,
-		141, 140, 1, 0, 0, 0, 141, 142, 1, 0, 0, 0, 142, 143, 1, 0, 0, 0, 143,
-		144, 5, 9, 0, 0, 144, 145, 3, 26, 13, 0, 145, 146, 3, 28, 14, 0, 146, 147,
-		5, 10, 0, 0, 147, 19, 1, 0, 0, 0, 148, 149, 5, 24, 0, 0, 149, 150, 3, 70,
-		35, 0, 150, 21, 1, 0, 0, 0, 151, 152, 5, 38, 0, 0, 152, 23, 1, 0, 0, 0,
-		153, 154, 7, 0, 0, 0, 154, 25, 1, 0, 0, 0, 155, 156, 5, 16, 0, 0, 156,
-		157, 3, 36, 18, 0, 157, 27, 1, 0, 0, 0, 158, 159, 5, 17, 0, 0, 159, 160,
-		3, 30, 15, 0, 160, 29, 1, 0, 0, 0, 161, 162, 3, 32, 16, 0, 162, 163, 5,
-		8, 0, 0, 163, 165, 1, 0, 0, 0, 164, 161, 1, 0, 0, 0, 165, 166, 1, 0, 0,
-		0, 166, 164, 1, 0, 0, 0, 166, 167, 1, 0, 0, 0, 167, 31, 1, 0, 0, 0, 168,
-		171, 3, 34, 17, 0, 169, 171, 3, 48, 24, 0, 170, 168, 1, 0, 0, 0, 170, 169,
-		1, 0, 0, 0, 171, 33, 1, 0, 0, 0, 172, 173, 3, 52, 26, 0, 173, 174, 7, 1,
-		0, 0, 174, 175, 3, 36, 18, 0, 175, 35, 1, 0, 0, 0, 176, 178, 6, 18, -1,
-		0, 177, 179, 5, 23, 0, 0, 178, 177, 1, 0, 0, 0, 178, 179, 1, 0, 0, 0, 179,
-		180, 1, 0, 0, 0, 180, 181, 5, 11, 0, 0, 181, 182, 3, 36, 18, 0, 182, 183,
-		5, 12, 0, 0, 183, 186, 1, 0, 0, 0, 184, 186, 3, 48, 24, 0, 185, 176, 1,
-		0, 0, 0, 185, 184, 1, 0, 0, 0, 186, 209, 1, 0, 0, 0, 187, 188, 10, 7, 0,
-		0, 188, 189, 3, 38, 19, 0, 189, 190, 3, 36, 18, 8, 190, 208, 1, 0, 0, 0,
-		191, 192, 10, 6, 0, 0, 192, 193, 3, 40, 20, 0, 193, 194, 3, 36, 18, 7,
-		194, 208, 1, 0, 0, 0, 195, 196, 10, 5, 0, 0, 196, 197, 3, 42, 21, 0, 197,
-		198, 3, 36, 18, 6, 198, 208, 1, 0, 0, 0, 199, 200, 10, 4, 0, 0, 200, 201,
-		3, 44, 22, 0, 201, 202, 3, 36, 18, 5, 202, 208, 1, 0, 0, 0, 203, 204, 10,
-		3, 0, 0, 204, 205, 3, 46, 23, 0, 205, 206, 3, 36, 18, 4, 206, 208, 1, 0,
-		0, 0, 207, 187, 1, 0, 0, 0, 207, 191, 1, 0, 0, 0, 207, 195, 1, 0, 0, 0,
-		207, 199, 1, 0, 0, 0, 207, 203, 1, 0, 0, 0, 208, 211, 1, 0, 0, 0, 209,
-		207, 1, 0, 0, 0, 209, 210, 1, 0, 0, 0, 210, 37, 1, 0, 0, 0, 211, 209, 1,
-		0, 0, 0, 212, 213, 7, 2, 0, 0, 213, 39, 1, 0, 0, 0, 214, 215, 7, 3, 0,
-		0, 215, 41, 1, 0, 0, 0, 216, 217, 7, 4, 0, 0, 217, 43, 1, 0, 0, 0, 218,
-		219, 5, 18, 0, 0, 219, 45, 1, 0, 0, 0, 220, 221, 5, 19, 0, 0, 221, 47,
-		1, 0, 0, 0, 222, 223, 6, 24, -1, 0, 223, 229, 3, 50, 25, 0, 224, 229, 3,
-		52, 26, 0, 225, 229, 3, 58, 29, 0, 226, 227, 5, 23, 0, 0, 227, 229, 3,
-		48, 24, 1, 228, 222, 1, 0, 0, 0, 228, 224, 1, 0, 0, 0, 228, 225, 1, 0,
-		0, 0, 228, 226, 1, 0, 0, 0, 229, 238, 1, 0, 0, 0, 230, 231, 10, 4, 0, 0,
-		231, 237, 3, 60, 30, 0, 232, 233, 10, 3, 0, 0, 233, 237, 3, 56, 28, 0,
-		234, 235, 10, 2, 0, 0, 235, 237, 3, 54, 27, 0, 236, 230, 1, 0, 0, 0, 236,
-		232, 1, 0, 0, 0, 236, 234, 1, 0, 0, 0, 237, 240, 1, 0, 0, 0, 238, 236,
-		1, 0, 0, 0, 238, 239, 1, 0, 0, 0, 239, 49, 1, 0, 0, 0, 240, 238, 1, 0,
-		0, 0, 241, 247, 3, 78, 39, 0, 242, 247, 3, 70, 35, 0, 243, 247, 3, 64,
-		32, 0, 244, 247, 3, 80, 40, 0, 245, 247, 5, 22, 0, 0, 246, 241, 1, 0, 0,
-		0, 246, 242, 1, 0, 0, 0, 246, 243, 1, 0, 0, 0, 246, 244, 1, 0, 0, 0, 246,
-		245, 1, 0, 0, 0, 247, 51, 1, 0, 0, 0, 248, 249, 6, 26, -1, 0, 249, 250,
-		5, 38, 0, 0, 250, 257, 1, 0, 0, 0, 251, 252, 10, 3, 0, 0, 252, 256, 3,
-		56, 28, 0, 253, 254, 10, 2, 0, 0, 254, 256, 3, 54, 27, 0, 255, 251, 1,
-		0, 0, 0, 255, 253, 1, 0, 0, 0, 256, 259, 1, 0, 0, 0, 257, 255, 1, 0, 0,
-		0, 257, 258, 1, 0, 0, 0, 258, 53, 1, 0, 0, 0, 259, 257, 1, 0, 0, 0, 260,
-		261, 5, 13, 0, 0, 261, 262, 3, 36, 18, 0, 262, 263, 5, 14, 0, 0, 263, 55,
-		1, 0, 0, 0, 264, 265, 5, 7, 0, 0, 265, 266, 5, 38, 0, 0, 266, 57, 1, 0,
-		0, 0, 267, 268, 5, 38, 0, 0, 268, 270, 5, 11, 0, 0, 269, 271, 3, 62, 31,
-		0, 270, 269, 1, 0, 0, 0, 270, 271, 1, 0, 0, 0, 271, 272, 1, 0, 0, 0, 272,
-		273, 5, 12, 0, 0, 273, 59, 1, 0, 0, 0, 274, 275, 5, 7, 0, 0, 275, 276,
-		3, 58, 29, 0, 276, 61, 1, 0, 0, 0, 277, 282, 3, 36, 18, 0, 278, 279, 5,
-		1, 0, 0, 279, 281, 3, 36, 18, 0, 280, 278, 1, 0, 0, 0, 281, 284, 1, 0,
-		0, 0, 282, 280, 1, 0, 0, 0, 282, 283, 1, 0, 0, 0, 283, 63, 1, 0, 0, 0,
-		284, 282, 1, 0, 0, 0, 285, 288, 3, 66, 33, 0, 286, 288, 3, 68, 34, 0, 287,
-		285, 1, 0, 0, 0, 287, 286, 1, 0, 0, 0, 288, 65, 1, 0, 0, 0, 289, 291, 5,
-		3, 0, 0, 290, 289, 1, 0, 0, 0, 290, 291, 1, 0, 0, 0, 291, 292, 1, 0, 0,
-		0, 292, 293, 5, 41, 0, 0, 293, 67, 1, 0, 0, 0, 294, 296, 5, 3, 0, 0, 295,
-		294, 1, 0, 0, 0, 295, 296, 1, 0, 0, 0, 296, 297, 1, 0, 0, 0, 297, 298,
-		5, 43, 0, 0, 298, 69, 1, 0, 0, 0, 299, 303, 3, 72, 36, 0, 300, 303, 3,
-		74, 37, 0, 301, 303, 3, 76, 38, 0, 302, 299, 1, 0, 0, 0, 302, 300, 1, 0,
-		0, 0, 302, 301, 1, 0, 0, 0, 303, 71, 1, 0, 0, 0, 304, 306, 5, 3, 0, 0,
-		305, 304, 1, 0, 0, 0, 305, 306, 1, 0, 0, 0, 306, 307, 1, 0, 0, 0, 307,
-		308, 5, 45, 0, 0, 308, 73, 1, 0, 0, 0, 309, 311, 5, 3, 0, 0, 310, 309,
-		1, 0, 0, 0, 310, 311, 1, 0, 0, 0, 311, 312, 1, 0, 0, 0, 312, 313, 5, 46,
-		0, 0, 313, 75, 1, 0, 0, 0, 314, 316, 5, 3, 0, 0, 315, 314, 1, 0, 0, 0,
-		315, 316, 1, 0, 0, 0, 316, 317, 1, 0, 0, 0, 317, 318, 5, 47, 0, 0, 318,
-		77, 1, 0, 0, 0, 319, 320, 7, 0, 0, 0, 320, 79, 1, 0, 0, 0, 321, 322, 7,
-		5, 0, 0, 322, 81, 1, 0, 0, 0, 31, 85, 92, 97, 102, 109, 121, 125, 130,
-		138, 141, 166, 170, 178, 185, 207, 209, 228, 236, 238, 246, 255, 257, 270,
-		282, 287, 290, 295, 302, 305, 310, 315,
+		1, 0, 0, 0, 86, 1, 1, 0, 0, 0, 87, 88, 5, 15, 0, 0, 88, 326, 5, 38, 0,
+		0, 89, 90, 5, 51, 0, 0, 90, 332, 3, 4, 2, 0, 91, 93, 3, 6, 3, 0, 92, 91,
+		1, 0, 0, 0, 92, 93, 1, 0, 0, 0, 93, 95, 1, 0, 0, 0, 94, 96, 3, 8, 4, 0,
+		95, 94, 1, 0, 0, 0, 96, 97, 1, 0, 0, 0, 97, 95, 1, 0, 0, 0, 97, 98, 1,
+		0, 0, 0, 98, 3, 1, 0, 0, 0, 99, 101, 5, 38, 0, 0, 100, 99, 1, 0, 0, 0,
+		101, 102, 1, 0, 0, 0, 102, 100, 1, 0, 0, 0, 102, 103, 1, 0, 0, 0, 103,
+		5, 1, 0, 0, 0, 104, 105, 5, 52, 0, 0, 105, 106, 3, 10, 5, 0, 106, 7, 1,
+		0, 0, 0, 107, 109, 5, 53, 0, 0, 108, 110, 5, 54, 0, 0, 109, 108, 1, 0,
+		0, 0, 109, 110, 1, 0, 0, 0, 110, 111, 1, 0, 0, 0, 111, 112, 3, 36, 18,
+		0, 112, 113, 5, 55, 0, 0, 113, 114, 3, 10, 5, 0, 114, 9, 1, 0, 0, 0, 115,
+		116, 3, 34, 17, 0, 116, 117, 3, 12, 6, 0, 117, 11, 1, 0, 0, 0, 118, 119,
+		5, 1, 0, 0, 119, 122, 3, 14, 7, 0, 120, 122, 1, 0, 0, 0, 121, 118, 1, 0,
+		0, 0, 121, 120, 1, 0, 0, 0, 122, 13, 1, 0, 0, 0, 123, 126, 3, 10, 5, 0,
+		124, 126, 1, 0, 0, 0, 125, 123, 1, 0, 0, 0, 125, 124, 1, 0, 0, 0, 126,
+		15, 1, 0, 0, 0, 127, 129, 3, 18, 9, 0, 128, 127, 1, 0, 0, 0, 129, 132,
+		1, 0, 0, 0, 130, 128, 1, 0, 0, 0, 130, 131, 1, 0, 0, 0, 131, 133, 1, 0,
+		0, 0, 132, 130, 1, 0, 0, 0, 133, 134, 5, 0, 0, 1, 134, 17, 1, 0, 0, 0,
+		135, 136, 5, 15, 0, 0, 136, 138, 3, 22, 11, 0, 137, 139, 3, 24, 12, 0,
+		138, 137, 1, 0, 0, 0, 138, 139, 1, 0, 0, 0, 139, 141, 1, 0, 0, 0, 140,
+		142, 3, 20, 10, 0, 141, 140, 1, 0, 0, 0, 141, 142, 1, 0, 0, 0, 142, 143,
+		1, 0, 0, 0, 143, 144, 5, 9, 0, 0, 144, 145, 3, 26, 13, 0, 145, 146, 3,
+		28, 14, 0, 146, 147, 5, 10, 0, 0, 147, 19, 1, 0, 0, 0, 148, 149, 5, 24,
+		0, 0, 149, 150, 3, 70, 35, 0, 150, 21, 1, 0, 0, 0, 151, 152, 5, 38, 0,
+		0, 152, 23, 1, 0, 0, 0, 153, 154, 7, 0, 0, 0, 154, 25, 1, 0, 0, 0, 155,
+		156, 5, 16, 0, 0, 156, 157, 3, 36, 18, 0, 157, 27, 1, 0, 0, 0, 158, 159,
+		5, 17, 0, 0, 159, 160, 3, 30, 15, 0, 160, 29, 1, 0, 0, 0, 161, 162, 3,
+		32, 16, 0, 162, 163, 5, 8, 0, 0, 163, 165, 1, 0, 0, 0, 164, 161, 1, 0,
+		0, 0, 165, 166, 1, 0, 0, 0, 166, 164, 1, 0, 0, 0, 166, 167, 1, 0, 0, 0,
+		167, 31, 1, 0, 0, 0, 168, 171, 3, 34, 17, 0, 169, 171, 3, 48, 24, 0, 170,
+		168, 1, 0, 0, 0, 170, 169, 1, 0, 0, 0, 171, 33, 1, 0, 0, 0, 172, 173, 3,
+		52, 26, 0, 173, 174, 7, 1, 0, 0, 174, 175, 3, 36, 18, 0, 175, 35, 1, 0,
+		0, 0, 176, 178, 6, 18, -1, 0, 177, 179, 5, 23, 0, 0, 178, 177, 1, 0, 0,
+		0, 178, 179, 1, 0, 0, 0, 179, 180, 1, 0, 0, 0, 180, 181, 5, 11, 0, 0, 181,
+		182, 3, 36, 18, 0, 182, 183, 5, 12, 0, 0, 183, 186, 1, 0, 0, 0, 184, 186,
+		3, 48, 24, 0, 185, 176, 1, 0, 0, 0, 185, 184, 1, 0, 0, 0, 186, 209, 1,
+		0, 0, 0, 187, 188, 10, 7, 0, 0, 188, 189, 3, 38, 19, 0, 189, 190, 3, 36,
+		18, 8, 190, 208, 1, 0, 0, 0, 191, 192, 10, 6, 0, 0, 192, 193, 3, 40, 20,
+		0, 193, 194, 3, 36, 18, 7, 194, 208, 1, 0, 0, 0, 195, 196, 10, 5, 0, 0,
+		196, 197, 3, 42, 21, 0, 197, 198, 3, 36, 18, 6, 198, 208, 1, 0, 0, 0, 199,
+		200, 10, 4, 0, 0, 200, 201, 3, 44, 22, 0, 201, 202, 3, 36, 18, 5, 202,
+		208, 1, 0, 0, 0, 203, 204, 10, 3, 0, 0, 204, 205, 3, 46, 23, 0, 205, 206,
+		3, 36, 18, 4, 206, 208, 1, 0, 0, 0, 207, 187, 1, 0, 0, 0, 207, 191, 1,
+		0, 0, 0, 207, 195, 1, 0, 0, 0, 207, 199, 1, 0, 0, 0, 207, 203, 1, 0, 0,
+		0, 208, 211, 1, 0, 0, 0, 209, 207, 1, 0, 0, 0, 209, 210, 1, 0, 0, 0, 210,
+		37, 1, 0, 0, 0, 211, 209, 1, 0, 0, 0, 212, 213, 7, 2, 0, 0, 213, 39, 1,
+		0, 0, 0, 214, 215, 7, 3, 0, 0, 215, 41, 1, 0, 0, 0, 216, 217, 7, 4, 0,
+		0, 217, 43, 1, 0, 0, 0, 218, 219, 5, 18, 0, 0, 219, 45, 1, 0, 0, 0, 220,
+		221, 5, 19, 0, 0, 221, 47, 1, 0, 0, 0, 222, 223, 6, 24, -1, 0, 223, 229,
+		3, 50, 25, 0, 224, 229, 3, 52, 26, 0, 225, 229, 3, 58, 29, 0, 226, 227,
+		5, 23, 0, 0, 227, 229, 3, 48, 24, 1, 228, 222, 1, 0, 0, 0, 228, 224, 1,
+		0, 0, 0, 228, 225, 1, 0, 0, 0, 228, 226, 1, 0, 0, 0, 229, 238, 1, 0, 0,
+		0, 230, 231, 10, 4, 0, 0, 231, 237, 3, 60, 30, 0, 232, 233, 10, 3, 0, 0,
+		233, 237, 3, 56, 28, 0, 234, 235, 10, 2, 0, 0, 235, 237, 3, 54, 27, 0,
+		236, 230, 1, 0, 0, 0, 236, 232, 1, 0, 0, 0, 236, 234, 1, 0, 0, 0, 237,
+		240, 1, 0, 0, 0, 238, 236, 1, 0, 0, 0, 238, 239, 1, 0, 0, 0, 239, 49, 1,
+		0, 0, 0, 240, 238, 1, 0, 0, 0, 241, 247, 3, 78, 39, 0, 242, 247, 3, 70,
+		35, 0, 243, 247, 3, 64, 32, 0, 244, 247, 3, 80, 40, 0, 245, 247, 5, 22,
+		0, 0, 246, 241, 1, 0, 0, 0, 246, 242, 1, 0, 0, 0, 246, 243, 1, 0, 0, 0,
+		246, 244, 1, 0, 0, 0, 246, 245, 1, 0, 0, 0, 247, 51, 1, 0, 0, 0, 248, 249,
+		6, 26, -1, 0, 249, 250, 5, 38, 0, 0, 250, 257, 1, 0, 0, 0, 251, 252, 10,
+		3, 0, 0, 252, 256, 3, 56, 28, 0, 253, 254, 10, 2, 0, 0, 254, 256, 3, 54,
+		27, 0, 255, 251, 1, 0, 0, 0, 255, 253, 1, 0, 0, 0, 256, 259, 1, 0, 0, 0,
+		257, 255, 1, 0, 0, 0, 257, 258, 1, 0, 0, 0, 258, 53, 1, 0, 0, 0, 259, 257,
+		1, 0, 0, 0, 260, 261, 5, 13, 0, 0, 261, 262, 3, 36, 18, 0, 262, 263, 5,
+		14, 0, 0, 263, 55, 1, 0, 0, 0, 264, 265, 5, 7, 0, 0, 265, 266, 5, 38, 0,
+		0, 266, 57, 1, 0, 0, 0, 267, 268, 5, 38, 0, 0, 268, 270, 5, 11, 0, 0, 269,
+		271, 3, 62, 31, 0, 270, 269, 1, 0, 0, 0, 270, 271, 1, 0, 0, 0, 271, 272,
+		1, 0, 0, 0, 272, 273, 5, 12, 0, 0, 273, 59, 1, 0, 0, 0, 274, 275, 5, 7,
+		0, 0, 275, 276, 3, 58, 29, 0, 276, 61, 1, 0, 0, 0, 277, 282, 3, 36, 18,
+		0, 278, 279, 5, 1, 0, 0, 279, 281, 3, 36, 18, 0, 280, 278, 1, 0, 0, 0,
+		281, 284, 1, 0, 0, 0, 282, 280, 1, 0, 0, 0, 282, 283, 1, 0, 0, 0, 283,
+		63, 1, 0, 0, 0, 284, 282, 1, 0, 0, 0, 285, 288, 3, 66, 33, 0, 286, 288,
+		3, 68, 34, 0, 287, 285, 1, 0, 0, 0, 287, 286, 1, 0, 0, 0, 288, 65, 1, 0,
+		0, 0, 289, 291, 5, 3, 0, 0, 290, 289, 1, 0, 0, 0, 290, 291, 1, 0, 0, 0,
+		291, 292, 1, 0, 0, 0, 292, 293, 5, 41, 0, 0, 293, 67, 1, 0, 0, 0, 294,
+		296, 5, 3, 0, 0, 295, 294, 1, 0, 0, 0, 295, 296, 1, 0, 0, 0, 296, 297,
+		1, 0, 0, 0, 297, 298, 5, 43, 0, 0, 298, 69, 1, 0, 0, 0, 299, 303, 3, 72,
+		36, 0, 300, 303, 3, 74, 37, 0, 301, 303, 3, 76, 38, 0, 302, 299, 1, 0,
+		0, 0, 302, 300, 1, 0, 0, 0, 302, 301, 1, 0, 0, 0, 303, 71, 1, 0, 0, 0,
+		304, 306, 5, 3, 0, 0, 305, 304, 1, 0, 0, 0, 305, 306, 1, 0, 0, 0, 306,
+		307, 1, 0, 0, 0, 307, 308, 5, 45, 0, 0, 308, 73, 1, 0, 0, 0, 309, 311,
+		5, 3, 0, 0, 310, 309, 1, 0, 0, 0, 310, 311, 1, 0, 0, 0, 311, 312, 1, 0,
+		0, 0, 312, 313, 5, 46, 0, 0, 313, 75, 1, 0, 0, 0, 314, 316, 5, 3, 0, 0,
+		315, 314, 1, 0, 0, 0, 315, 316, 1, 0, 0, 0, 316, 317, 1, 0, 0, 0, 317,
+		318, 5, 47, 0, 0, 318, 77, 1, 0, 0, 0, 319, 320, 7, 0, 0, 0, 320, 79, 1,
+		0, 0, 0, 321, 322, 7, 5, 0, 0, 322, 81, 1, 0, 0, 0, 326, 324, 1, 0, 0,
+		0, 326, 325, 1, 0, 0, 0, 324, 325, 3, 20, 10, 0, 325, 333, 1, 0, 0, 0,
+		333, 89, 1, 0, 0, 0, 333, 331, 1, 0, 0, 0, 331, 332, 3, 327, 41, 0, 332,
+		92, 1, 0, 0, 0, 327, 334, 1, 0, 0, 0, 334, 335, 5, 38, 0, 0, 335, 339,
+		3, 329, 42, 0, 339, 336, 1, 0, 0, 0, 339, 338, 1, 0, 0, 0, 336, 337, 5,
+		51, 0, 0, 337, 338, 3, 4, 2, 0, 338, 328, 1, 0, 0, 0, 329, 343, 1, 0, 0,
+		0, 343, 340, 1, 0, 0, 0, 340, 341, 7, 6, 0, 0, 341, 342, 5, 38, 0, 0, 342,
+		344, 1, 0, 0, 0, 344, 343, 1, 0, 0, 0, 344, 345, 1, 0, 0, 0, 345, 330,
+		1, 0, 0, 0, 35, 85, 92, 97, 102, 109, 121, 125, 130, 138, 141, 166, 170,
+		178, 185, 207, 209, 228, 236, 238, 246, 255, 257, 270, 282, 287, 290, 295,
+		302, 305, 310, 315, 326, 333, 339, 344,
 	}
 	deserializer := antlr.NewATNDeserializer(nil)
 	staticData.atn = deserializer.Deserialize(staticData.serializedATN)
@@ -332,6 +343,8 @@ const (
 	EcaruleParserRULE_octalLiteral            = 38
 	EcaruleParserRULE_stringLiteral           = 39
 	EcaruleParserRULE_booleanLiteral          = 40
+	EcaruleParserRULE_timer                   = 41
+	EcaruleParserRULE_duration                = 42
 )
 
 // IPrulesContext is an interface to support dynamic dispatch.
@@ -522,6 +535,22 @@ func (s *PruleContext) SIMPLENAME() antlr.TerminalNode {
 	return s.GetToken(EcaruleParserSIMPLENAME, 0)
 }
 
+func (s *PruleContext) Salience() ISalienceContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ISalienceContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ISalienceContext)
+}
+
 func (s *PruleContext) ON() antlr.TerminalNode {
 	return s.GetToken(EcaruleParserON, 0)
 }
@@ -542,6 +571,22 @@ func (s *PruleContext) Events() IEventsContext {
 	return t.(IEventsContext)
 }
 
+func (s *PruleContext) Timer() ITimerContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ITimerContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ITimerContext)
+}
+
 func (s *PruleContext) DefaultActions() IDefaultActionsContext {
 	var t antlr.RuleContext
 	for _, ctx := range s.GetChildren() {
@@ -652,13 +697,39 @@ func (p *EcaruleParser) Prule() (localctx IPruleContext) {
 		p.SetState(88)
 		p.Match(EcaruleParserSIMPLENAME)
 	}
-	{
-		p.SetState(89)
-		p.Match(EcaruleParserON)
+	p.SetState(326)
+	p.GetErrorHandler().Sync(p)
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == EcaruleParserSALIENCE {
+		{
+			p.SetState(324)
+			p.Salience()
+		}
+
 	}
-	{
-		p.SetState(90)
-		p.Events()
+	p.SetState(333)
+	p.GetErrorHandler().Sync(p)
+
+	switch p.GetTokenStream().LA(1) {
+	case EcaruleParserON:
+		{
+			p.SetState(89)
+			p.Match(EcaruleParserON)
+		}
+		{
+			p.SetState(90)
+			p.Events()
+		}
+
+	case EcaruleParserSIMPLENAME:
+		{
+			p.SetState(331)
+			p.Timer()
+		}
+
+	default:
+		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
 	p.SetState(92)
 	p.GetErrorHandler().Sync(p)
@@ -6233,6 +6304,292 @@ func (p *EcaruleParser) BooleanLiteral() (localctx IBooleanLiteralContext) {
 	return localctx
 }
 
+// ITimerContext is an interface to support dynamic dispatch.
+type ITimerContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsTimerContext differentiates from other interfaces.
+	IsTimerContext()
+}
+
+type TimerContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyTimerContext() *TimerContext {
+	var p = new(TimerContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = EcaruleParserRULE_timer
+	return p
+}
+
+func (*TimerContext) IsTimerContext() {}
+
+func NewTimerContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *TimerContext {
+	var p = new(TimerContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = EcaruleParserRULE_timer
+
+	return p
+}
+
+func (s *TimerContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *TimerContext) SIMPLENAME() antlr.TerminalNode {
+	return s.GetToken(EcaruleParserSIMPLENAME, 0)
+}
+
+func (s *TimerContext) Duration() IDurationContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IDurationContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IDurationContext)
+}
+
+func (s *TimerContext) ON() antlr.TerminalNode {
+	return s.GetToken(EcaruleParserON, 0)
+}
+
+func (s *TimerContext) Events() IEventsContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IEventsContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IEventsContext)
+}
+
+func (s *TimerContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *TimerContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *TimerContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(EcaruleParserListener); ok {
+		listenerT.EnterTimer(s)
+	}
+}
+
+func (s *TimerContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(EcaruleParserListener); ok {
+		listenerT.ExitTimer(s)
+	}
+}
+
+func (p *EcaruleParser) Timer() (localctx ITimerContext) {
+	this := p
+	_ = this
+
+	localctx = NewTimerContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 327, EcaruleParserRULE_timer)
+	var _la int
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(334)
+		p.Match(EcaruleParserSIMPLENAME)
+	}
+	{
+		p.SetState(335)
+		p.Duration()
+	}
+	p.SetState(339)
+	p.GetErrorHandler().Sync(p)
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == EcaruleParserON {
+		{
+			p.SetState(336)
+			p.Match(EcaruleParserON)
+		}
+		{
+			p.SetState(337)
+			p.Events()
+		}
+
+	}
+
+	return localctx
+}
+
+// IDurationContext is an interface to support dynamic dispatch.
+type IDurationContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsDurationContext differentiates from other interfaces.
+	IsDurationContext()
+}
+
+type DurationContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyDurationContext() *DurationContext {
+	var p = new(DurationContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = EcaruleParserRULE_duration
+	return p
+}
+
+func (*DurationContext) IsDurationContext() {}
+
+func NewDurationContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *DurationContext {
+	var p = new(DurationContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = EcaruleParserRULE_duration
+
+	return p
+}
+
+func (s *DurationContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *DurationContext) AllSIMPLENAME() []antlr.TerminalNode {
+	return s.GetTokens(EcaruleParserSIMPLENAME)
+}
+
+func (s *DurationContext) SIMPLENAME(i int) antlr.TerminalNode {
+	return s.GetToken(EcaruleParserSIMPLENAME, i)
+}
+
+func (s *DurationContext) AllDECIMAL_FLOAT_LIT() []antlr.TerminalNode {
+	return s.GetTokens(EcaruleParserDECIMAL_FLOAT_LIT)
+}
+
+func (s *DurationContext) DECIMAL_FLOAT_LIT(i int) antlr.TerminalNode {
+	return s.GetToken(EcaruleParserDECIMAL_FLOAT_LIT, i)
+}
+
+func (s *DurationContext) AllDEC_LIT() []antlr.TerminalNode {
+	return s.GetTokens(EcaruleParserDEC_LIT)
+}
+
+func (s *DurationContext) DEC_LIT(i int) antlr.TerminalNode {
+	return s.GetToken(EcaruleParserDEC_LIT, i)
+}
+
+func (s *DurationContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *DurationContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *DurationContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(EcaruleParserListener); ok {
+		listenerT.EnterDuration(s)
+	}
+}
+
+func (s *DurationContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(EcaruleParserListener); ok {
+		listenerT.ExitDuration(s)
+	}
+}
+
+func (p *EcaruleParser) Duration() (localctx IDurationContext) {
+	this := p
+	_ = this
+
+	localctx = NewDurationContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 329, EcaruleParserRULE_duration)
+	var _la int
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	p.SetState(343)
+	p.GetErrorHandler().Sync(p)
+	_la = p.GetTokenStream().LA(1)
+
+	for ok := true; ok; ok = _la == EcaruleParserDECIMAL_FLOAT_LIT || _la == EcaruleParserDEC_LIT {
+		{
+			p.SetState(340)
+			_la = p.GetTokenStream().LA(1)
+
+			if !(_la == EcaruleParserDECIMAL_FLOAT_LIT || _la == EcaruleParserDEC_LIT) {
+				p.GetErrorHandler().RecoverInline(p)
+			} else {
+				p.GetErrorHandler().ReportMatch(p)
+				p.Consume()
+			}
+		}
+		{
+			p.SetState(341)
+			p.Match(EcaruleParserSIMPLENAME)
+		}
+
+		p.SetState(344)
+		p.GetErrorHandler().Sync(p)
+		_la = p.GetTokenStream().LA(1)
+	}
+
+	return localctx
+}
+
 func (p *EcaruleParser) Sempred(localctx antlr.RuleContext, ruleIndex, predIndex int) bool {
 	switch ruleIndex {
 	case 18:
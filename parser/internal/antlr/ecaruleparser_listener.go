@@ -133,6 +133,12 @@ type EcaruleParserListener interface {
 	// EnterBooleanLiteral is called when entering the booleanLiteral production.
 	EnterBooleanLiteral(c *grulev3.BooleanLiteralContext)
 
+	// EnterTimer is called when entering the timer production.
+	EnterTimer(c *TimerContext)
+
+	// EnterDuration is called when entering the duration production.
+	EnterDuration(c *DurationContext)
+
 	// ExitPrules is called when exiting the prules production.
 	ExitPrules(c *PrulesContext)
 
@@ -255,4 +261,10 @@ type EcaruleParserListener interface {
 
 	// ExitBooleanLiteral is called when exiting the booleanLiteral production.
 	ExitBooleanLiteral(c *grulev3.BooleanLiteralContext)
+
+	// ExitTimer is called when exiting the timer production.
+	ExitTimer(c *TimerContext)
+
+	// ExitDuration is called when exiting the duration production.
+	ExitDuration(c *DurationContext)
 }
@@ -4,11 +4,13 @@
 package parser
 
 import (
-	"fmt"
+	"slices"
+	"sort"
 
 	"github.com/abu-lang/goabu/ecarule"
 	antlr_parser "github.com/abu-lang/goabu/parser/internal/antlr"
 
+	"github.com/antlr/antlr4/runtime/Go/antlr"
 	grule_parser "github.com/hyperjumptech/grule-rule-engine/antlr"
 	"github.com/hyperjumptech/grule-rule-engine/antlr/parser/grulev3"
 	"github.com/hyperjumptech/grule-rule-engine/ast"
@@ -51,6 +53,18 @@ func (l *localParserState) ExitTask(ctx *antlr_parser.TaskContext) {
 		return
 	}
 	l.Stack.Pop()
+	cond := l.localTasks[len(l.localTasks)-1].Condition
+	if typ := staticType(cond); !compatibleTypes("Bool", typ) {
+		err := newError(CategoryTypeMismatch, "type mismatch: the condition %s is %s, not Bool", cond.GetGrlText(), typ)
+		// the first child that is not a terminal is the condition
+		for _, c := range ctx.GetChildren() {
+			if exp, ok := c.(antlr.ParserRuleContext); ok {
+				err.locate(exp.GetStart())
+				break
+			}
+		}
+		l.parseError(err)
+	}
 }
 
 // ExitVariable is called when production variable is exited.
@@ -71,8 +85,11 @@ func (l *localParserState) ExitVariable(ctx *grulev3.VariableContext) {
 		if name == "this" || name == "ext" {
 			return
 		}
+	case e.ArrayMapSelector != nil:
+		// element of a List or Map resource, the resource has already been encoded
+		return
 	case e.Variable != nil && e.Variable.Name == "ext":
-		l.parseError(fmt.Errorf("external variable %s is not allowed in this context", e.GetGrlText()))
+		l.parseError(newError(CategoryInvalid, "external variable %s is not allowed in this context", e.GetGrlText()))
 		return
 	case e.Variable != nil && e.Variable.Name == "this":
 		name = e.Name
@@ -80,9 +97,40 @@ func (l *localParserState) ExitVariable(ctx *grulev3.VariableContext) {
 	presentType := false
 	typ, presentType = l.types[name]
 	if !presentType {
-		l.parseError(fmt.Errorf("could not determine the type of %s", name))
+		l.parseError(unknownResourceError(name, l.types))
+	} else if err := l.readOnlyError(name); err != nil {
+		l.parseError(err)
+	}
+	if !l.inAssignLeft {
+		l.addRead(name)
 	}
 	r := newAssignVariable(l.KnowledgeBase.WorkingMemory, "this", typ, name)
 	l.Stack.Pop()
 	l.Stack.Push(r)
 }
+
+// EnterAssignment is called when production assignment is entered.
+func (l *localParserState) EnterAssignment(ctx *grulev3.AssignmentContext) {
+	l.inAssignLeft = true
+
+	l.GruleV3ParserListener.EnterAssignment(ctx)
+}
+
+// EnterExpression is called when production expression is entered.
+func (l *localParserState) EnterExpression(ctx *grulev3.ExpressionContext) {
+	l.inAssignLeft = false
+
+	l.GruleV3ParserListener.EnterExpression(ctx)
+}
+
+// addRead records that the local task currently being processed reads the resource with the given name.
+func (l *localParserState) addRead(name string) {
+	if len(l.localTasks) == 0 {
+		return
+	}
+	task := &l.localTasks[len(l.localTasks)-1]
+	i := sort.SearchStrings(task.ReadResources, name)
+	if i == len(task.ReadResources) || task.ReadResources[i] != name {
+		task.ReadResources = slices.Insert(task.ReadResources, i, name)
+	}
+}
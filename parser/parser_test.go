@@ -4,7 +4,11 @@
 package parser
 
 import (
+	"reflect"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 )
@@ -31,7 +35,7 @@ func TestReset(t *testing.T) {
 	}
 	types := map[string]string{
 		"foo": "Integer",
-		"bar": "Integer",
+		"bar": "Text",
 	}
 	wm := ast.NewWorkingMemory("", "")
 	p := New(types, wm).(*goabuParser)
@@ -179,6 +183,9 @@ func TestMultipleRules(t *testing.T) {
 			for _, rule := range parsed {
 				if rule.Name == name {
 					found = true
+					if rule.Source != rules[i] {
+						t.Error(test.idx, "->", "wrong source for rule: ", name, rule.Source)
+					}
 					break
 				}
 			}
@@ -188,3 +195,302 @@ func TestMultipleRules(t *testing.T) {
 		}
 	}
 }
+
+// TestSalience tests the parsing of the rules' salience.
+func TestSalience(t *testing.T) {
+	tests := []struct {
+		idx       int
+		rules     string
+		saliences []int
+	}{
+		//  {_, rules, saliences},
+		{1, "rule R salience 10 on foo for true do foo = 1", []int{10}},
+		{2, "rule R on foo for true do foo = 1", []int{0}},
+		{3, "rule R salience -3 on foo for all true do ext.foo = this.foo", []int{-3}},
+		{4, "rule A salience 7 on foo for true do foo = 1 rule B on foo for true do foo = 2", []int{7, 0}},
+		{5, "rule R salience 0x10 on foo default foo = 0 for all true do ext.foo = 1", []int{16}},
+	}
+	types := map[string]string{
+		"foo": "Integer",
+	}
+	wm := ast.NewWorkingMemory("", "")
+	p := New(types, wm).(*goabuParser)
+	for _, test := range tests {
+		rules, errs := p.Parse(test.rules)
+		if len(errs) > 0 {
+			t.Fatal(test.idx, "->", "error in parsing rules", errs)
+		}
+		if len(rules) != len(test.saliences) {
+			t.Fatal(test.idx, "->", "mismatched parsed rules number")
+		}
+		for i, rule := range rules {
+			if rule.Salience != test.saliences[i] {
+				t.Error(test.idx, "->", "rule", rule.Name, "should have salience", test.saliences[i], "got", rule.Salience)
+			}
+			for _, task := range rule.RemoteTasks {
+				if task.Salience != test.saliences[i] {
+					t.Error(test.idx, "->", "remote task of rule", rule.Name, "should have salience", test.saliences[i], "got", task.Salience)
+				}
+			}
+		}
+	}
+	_, errs := p.Parse("rule R salience on foo for true do foo = 1")
+	if len(errs) == 0 {
+		t.Error("missing salience value should be an error")
+	}
+}
+
+// TestTimers tests the parsing of the periodic and delayed rules.
+func TestTimers(t *testing.T) {
+	tests := []struct {
+		idx    int
+		rules  string
+		events [][]string
+		period []time.Duration
+		delay  []time.Duration
+	}{
+		//  {_, rules, events, period, delay},
+		{1, "rule Heartbeat every 5s for true do foo = foo + 1", [][]string{nil}, []time.Duration{5 * time.Second}, []time.Duration{0}},
+		{2, "rule T after 30s on foo bar for true do bar = 0", [][]string{{"foo", "bar"}}, []time.Duration{0}, []time.Duration{30 * time.Second}},
+		{3, "rule T salience 2 Every 1m30s default foo = 0 for all true do ext.foo = 1", [][]string{nil}, []time.Duration{90 * time.Second}, []time.Duration{0}},
+		{4, "rule A every 1.5h for true do foo = 1 rule B on foo for true do bar = 2 rule C after 500ms on bar for true do foo = 3",
+			[][]string{nil, {"foo"}, {"bar"}}, []time.Duration{90 * time.Minute, 0, 0}, []time.Duration{0, 0, 500 * time.Millisecond}},
+	}
+	types := map[string]string{
+		"foo": "Integer",
+		"bar": "Integer",
+	}
+	wm := ast.NewWorkingMemory("", "")
+	p := New(types, wm).(*goabuParser)
+	for _, test := range tests {
+		rules, errs := p.Parse(test.rules)
+		if len(errs) > 0 {
+			t.Fatal(test.idx, "->", "error in parsing rules", errs)
+		}
+		if len(rules) != len(test.events) {
+			t.Fatal(test.idx, "->", "mismatched parsed rules number")
+		}
+		for i, rule := range rules {
+			if !reflect.DeepEqual(rule.Events, test.events[i]) || rule.Period != test.period[i] || rule.Delay != test.delay[i] {
+				t.Error(test.idx, "->", "unexpected rule", rule.Name, rule.Events, rule.Period, rule.Delay)
+			}
+		}
+	}
+	for _, rule := range []string{
+		"rule R every 5s on foo for true do foo = 1",
+		"rule R after 5s for true do foo = 1",
+		"rule R every 0s for true do foo = 1",
+		"rule R every 5 for true do foo = 1",
+		"rule R every 5parsecs for true do foo = 1",
+		"rule R sometimes 5s for true do foo = 1",
+	} {
+		if _, errs := p.Parse(rule); len(errs) == 0 {
+			t.Errorf("parsing %q should fail", rule)
+		}
+	}
+}
+
+// TestReadResources tests the tracking of the resources read by the local tasks and of the remote
+// resources read and assigned by the remote tasks.
+func TestReadResources(t *testing.T) {
+	types := map[string]string{
+		"foo": "Integer",
+		"bar": "Integer",
+		"baz": "Bool",
+	}
+	wm := ast.NewWorkingMemory("", "")
+	p := New(types, wm).(*goabuParser)
+	rules, errs := p.Parse("rule R on foo default foo = 0 for baz && bar > 0 do foo = bar + this.foo, bar = 1 for all ext.foo > foo do ext.bar = this.bar")
+	if len(errs) > 0 {
+		t.Fatal("error in parsing rule", errs)
+	}
+	tasks := rules[0].LocalTasks
+	if len(tasks) != 2 || len(tasks[0].ReadResources) != 0 || !reflect.DeepEqual(tasks[1].ReadResources, []string{"bar", "baz", "foo"}) {
+		t.Error("unexpected read resources:", tasks)
+	}
+	remote := rules[0].RemoteTasks
+	if len(remote) != 1 || !reflect.DeepEqual(remote[0].ReadRemoteResources, []string{"foo"}) ||
+		!reflect.DeepEqual(remote[0].AssignedResources, []string{"bar"}) {
+		t.Error("unexpected remote resources:", remote)
+	}
+	rules, errs = p.Parse("rule S on foo for all ext.baz do bar = ext.bar + foo, ext.foo = Abs(ext.foo - 1)")
+	if len(errs) > 0 {
+		t.Fatal("error in parsing rule", errs)
+	}
+	remote = rules[0].RemoteTasks
+	if !reflect.DeepEqual(remote[0].ReadRemoteResources, []string{"bar", "baz", "foo"}) ||
+		!reflect.DeepEqual(remote[0].AssignedResources, []string{"bar", "foo"}) {
+		t.Error("unexpected remote resources:", remote)
+	}
+}
+
+// TestNewArguments tests the arguments accepted by New.
+func TestNewArguments(t *testing.T) {
+	types := map[string]string{"foo": "Integer"}
+	wm := ast.NewWorkingMemory("", "")
+	lock := &sync.Mutex{}
+	if p, ok := New(types, wm, lock).(*goabuParser); !ok || p.lockMemory != lock {
+		t.Error("New should accept a sync.Locker")
+	}
+	if p, ok := New(types, wm, WithLocker(lock)).(*goabuParser); !ok || p.lockMemory != lock {
+		t.Error("New should accept WithLocker")
+	}
+	if New(types, wm, nil) != nil || New(types, wm, 1) != nil || New(types, wm, (*sync.Mutex)(nil)) != nil {
+		t.Error("New should not accept invalid arguments")
+	}
+}
+
+// functionSet is a Functions containing the functions in the set.
+type functionSet map[string]bool
+
+func (s functionSet) HasFunction(name string) bool {
+	return s[name]
+}
+
+// TestFunctions tests the rejection of the calls to undefined functions.
+func TestFunctions(t *testing.T) {
+	types := map[string]string{
+		"foo": "Float",
+		"bar": "Text",
+	}
+	wm := ast.NewWorkingMemory("", "")
+	if New(types, wm, WithFunctions(nil)) != nil || New(types, wm, WithFunctions(functionSet(nil))) != nil {
+		t.Error("New should not accept a nil Functions")
+	}
+	p := New(types, wm, WithFunctions(functionSet{"Calibrate": true}))
+	_, errs := p.Parse("rule R on foo for Calibrate(foo) > 1.0 && bar.Len() > 0 do foo = Calibrate(foo)")
+	if len(errs) > 0 {
+		t.Error("error in parsing rule", errs)
+	}
+	_, errs = p.Parse("rule R on foo for true do foo = Lookup(foo)")
+	if len(errs) != 1 || errs[0].(*Error).Category != CategoryUndefinedFunction || errs[0].(*Error).Token != "Lookup" {
+		t.Error("calling an undefined function should be a parse error:", errs)
+	}
+	rules, errs := p.Parse("rule R on foo for all ext.foo < Lookup(this.foo) do ext.foo = Lookup(this.foo)")
+	if len(errs) > 0 {
+		t.Fatal("the remote tasks are evaluated by the other nodes:", errs)
+	}
+	_, errs = p.ParseRemoteTasks(types, rules[0].RemoteTasks...)
+	if len(errs) != 1 || errs[0].(*Error).Category != CategoryUndefinedFunction || errs[0].(*Error).Token != "Lookup" {
+		t.Error("calling an undefined function in a received task should be a parse error:", errs)
+	}
+	if _, errs = New(types, wm).Parse("rule R on foo for true do foo = Lookup(foo)"); len(errs) > 0 {
+		t.Error("without Functions every function should be accepted:", errs)
+	}
+}
+
+// readOnlySet is a ReadOnly containing the resources in the set.
+type readOnlySet map[string]bool
+
+func (s readOnlySet) IsReadOnly(name string) bool {
+	return s[name]
+}
+
+// TestReadOnly tests the rejection of the assignments of read-only resources.
+func TestReadOnly(t *testing.T) {
+	types := map[string]string{
+		"button": "Bool",
+		"led":    "Bool",
+	}
+	wm := ast.NewWorkingMemory("", "")
+	if New(types, wm, WithReadOnly(nil)) != nil || New(types, wm, WithReadOnly((*readOnlySet)(nil))) != nil {
+		t.Error("New should not accept a nil ReadOnly")
+	}
+	p := New(types, wm, WithReadOnly(readOnlySet{"button": true}))
+	_, errs := p.Parse("rule R on button for button do led = true")
+	if len(errs) > 0 {
+		t.Error("reading a read-only resource should be allowed:", errs)
+	}
+	for _, rule := range []string{"rule R on led for led do button = true", "rule R on led for led do this.button = true"} {
+		_, errs = p.Parse(rule)
+		if len(errs) != 1 || errs[0].(*Error).Category != CategoryReadOnly || errs[0].(*Error).Token != "button" {
+			t.Error("assigning a read-only resource should be a parse error:", errs)
+		}
+	}
+	rules, errs := p.Parse("rule R on led for all ext.led do ext.button = this.led")
+	if len(errs) > 0 {
+		t.Fatal("the read-only resources of the other nodes are checked by them:", errs)
+	}
+	_, errs = p.ParseRemoteTasks(types, rules[0].RemoteTasks...)
+	if len(errs) != 1 || errs[0].(*Error).Category != CategoryReadOnly {
+		t.Error("assigning a read-only resource in a received task should be a parse error:", errs)
+	}
+	if _, errs = p.ParseActions("button = true, led = false"); len(errs) > 0 {
+		t.Error("the inputs should assign the read-only resources:", errs)
+	}
+	if _, errs = p.Parse("rule R on led for led do button = true"); len(errs) != 1 {
+		t.Error("ParseActions should not affect the following calls to Parse:", errs)
+	}
+}
+
+// TestErrors tests the position, the category and the suggestions of the parse errors.
+func TestErrors(t *testing.T) {
+	types := map[string]string{
+		"foo":         "Integer",
+		"bar":         "Text",
+		"temperature": "Float",
+		"tempo":       "Time",
+	}
+	tests := []struct {
+		idx         int
+		rule        string
+		line        int
+		column      int
+		token       string
+		category    Category
+		suggestions []string
+	}{
+		//  {_, rule, line, column, token, category, suggestions},
+		{1, "rule R on foo for true", 1, 22, "<EOF>", CategorySyntax, nil},
+		{2, "rule R on foo for true do foo = 1,\n\ton = 2", 2, 1, "on", CategoryReservedWord, nil},
+		{3, "rule R on foo for true do\n  tempreature = 1.0", 2, 2, "tempreature", CategoryUnknownResource, []string{"temperature"}},
+		{4, "rule R on foo for tmpo == tempo do foo = 1", 1, 18, "tmpo", CategoryUnknownResource, []string{"tempo"}},
+		{5, "rule R on foo for true do foo = Foo + 1", 1, 32, "Foo", CategoryUnknownResource, []string{"foo"}},
+		{6, `rule R on foo for true do foo = "one"`, 1, 26, "foo", CategoryTypeMismatch, nil},
+		{7, "rule R on foo for true do bar = foo > 1", 1, 26, "bar", CategoryTypeMismatch, nil},
+		{8, "rule R on foo for foo * 2 do bar = bar", 1, 18, "foo", CategoryTypeMismatch, nil},
+		{9, "rule R every 5y for true do foo = 1", 1, 13, "5", CategorySyntax, nil},
+		{10, "rule R on foo for true do foo = ext.foo", 1, 32, "ext", CategoryInvalid, nil},
+		{11, "rule R on foo for true do foo = temperature", 1, 26, "foo", CategoryTypeMismatch, nil},
+		{12, "rule R on foo for true do foo = foo * 1.5", 1, 26, "foo", CategoryTypeMismatch, nil},
+		{13, "rule R on foo for true do foo = foo / 2", 1, 26, "foo", CategoryTypeMismatch, nil},
+	}
+	p := New(types, ast.NewWorkingMemory("", ""))
+	for _, test := range tests {
+		_, errs := p.Parse(test.rule)
+		if len(errs) != 1 {
+			t.Error(test.idx, "->", "expected one error, got", errs)
+			continue
+		}
+		err, ok := errs[0].(*Error)
+		if !ok {
+			t.Error(test.idx, "->", "expected an *Error, got", errs[0])
+			continue
+		}
+		if err.Rule != 0 || err.Line != test.line || err.Column != test.column || err.Token != test.token ||
+			err.Category != test.category || !slices.Equal(err.Suggestions, test.suggestions) {
+			t.Errorf("%d -> unexpected error: %+v", test.idx, *err)
+		}
+	}
+	for _, valid := range []string{
+		"rule R on foo for true do temperature = foo / 2, bar = bar + foo",
+		"rule R on foo for true do temperature = foo, foo = foo * 2 - AbsInt(foo)",
+		"rule R on foo for foo > 1 && !(bar == \"\") do foo = AbsInt(foo)",
+		"rule R on foo for all ext.foo > foo do ext.bar = \"unchecked on the sender\"",
+	} {
+		if _, errs := p.Parse(valid); len(errs) > 0 {
+			t.Error("unexpected errors:", errs)
+		}
+	}
+	_, errs := p.Parse("rule A on foo for true do foo = 1", "rule B on foo for true do fooo = 1", "rule C on foo do foo = 1")
+	if len(errs) != 2 || errs[0].(*Error).Rule != 1 || errs[1].(*Error).Rule != 2 {
+		t.Error("the errors of all the rules should be returned, got", errs)
+	}
+	if msg := errs[0].Error(); msg != "rule #1 at 1:26: could not determine the type of fooo, did you mean foo?" {
+		t.Error("unexpected message:", msg)
+	}
+	_, errs = p.ParseActions("foo = 1, bar = 2")
+	if len(errs) != 1 || errs[0].(*Error).Rule != -1 || errs[0].(*Error).Category != CategoryTypeMismatch {
+		t.Error("unexpected errors:", errs)
+	}
+}
@@ -4,8 +4,6 @@
 package parser
 
 import (
-	"fmt"
-
 	"github.com/abu-lang/goabu/ecarule"
 	antlr_parser "github.com/abu-lang/goabu/parser/internal/antlr"
 
@@ -89,6 +87,9 @@ func (l *receivedParserState) ExitVariable(ctx *grulev3.VariableContext) {
 		if l.inAssignLeft {
 			remote = true
 		}
+	case e.ArrayMapSelector != nil:
+		// element of a List or Map resource, the resource has already been encoded
+		return
 	case e.Variable != nil && e.Variable.Name == "ext":
 		name = e.Name
 		remote = true
@@ -103,10 +104,12 @@ func (l *receivedParserState) ExitVariable(ctx *grulev3.VariableContext) {
 	}
 	if !presentType {
 		if remote {
-			l.parseError(fmt.Errorf("could not determine the type of %s", name))
+			l.parseError(unknownResourceError(name, l.remoteTypes))
 		} else {
 			l.isAccepting = false
 		}
+	} else if err := l.readOnlyError(name); !remote && err != nil {
+		l.parseError(err)
 	}
 	var r *ast.Variable
 	if !remote {
@@ -4,7 +4,7 @@
 package parser
 
 import (
-	"fmt"
+	"sort"
 
 	"github.com/abu-lang/goabu/ecarule"
 	antlr_parser "github.com/abu-lang/goabu/parser/internal/antlr"
@@ -28,6 +28,10 @@ type remoteParserState struct {
 	localResources stringset.Set
 	// remoteResources contains the names of the current task's remote resources (before the swap performed by rewriter).
 	remoteResources stringset.Set
+	// assigned contains the names of the remote resources assigned by the current task.
+	assigned stringset.Set
+	// remoteReads contains the names of the remote resources read by the current task.
+	remoteReads stringset.Set
 }
 
 // newRemoteParserState constructs a remoteParserState given a [*grule_parser.GruleV3ParserListener]
@@ -40,6 +44,8 @@ func newRemoteParserState(parser *grule_parser.GruleV3ParserListener, proc *proc
 		processing:      proc,
 		localResources:  stringset.Make(),
 		remoteResources: stringset.Make(),
+		assigned:        stringset.Make(),
+		remoteReads:     stringset.Make(),
 	}
 }
 
@@ -58,6 +64,8 @@ func (l *remoteParserState) EnterTask(ctx *antlr_parser.TaskContext) {
 	l.Stack.Push(nullExpressionReceiver{&l.remoteTasks[len(l.remoteTasks)-1]})
 	l.localResources = stringset.Make()
 	l.remoteResources = stringset.Make()
+	l.assigned = stringset.Make()
+	l.remoteReads = stringset.Make()
 }
 
 // ExitTask is called when production task is exited.
@@ -75,6 +83,8 @@ func (l *remoteParserState) ExitTask(ctx *antlr_parser.TaskContext) {
 	task.Condition = modifiedExp
 	task.LocalResources = l.localResources.Slice()
 	task.RemoteResources = l.remoteResources.Slice()
+	task.AssignedResources = sortedSlice(l.assigned)
+	task.ReadRemoteResources = sortedSlice(l.remoteReads)
 	l.Stack.Pop()
 }
 
@@ -127,12 +137,15 @@ func (l *remoteParserState) ExitVariable(ctx *grulev3.VariableContext) {
 		} else {
 			l.rewriter.InsertBeforeToken(antlr.Default_Program_Name, ctx.SIMPLENAME().GetSymbol(), "ext.")
 		}
+	case e.ArrayMapSelector != nil:
+		// element of a List or Map resource, the resource has already been encoded
+		return
 	case e.Variable != nil && e.Variable.Name == "ext":
 		name = e.Name
 		remote = true
 	case e.Variable != nil && e.Variable.Name == "this":
 		if l.inAssignLeft {
-			l.parseError(fmt.Errorf("local actions are not allowed in 'for all' tasks"))
+			l.parseError(newError(CategoryInvalid, "local actions are not allowed in 'for all' tasks"))
 			return
 		}
 		name = e.Name
@@ -145,7 +158,7 @@ func (l *remoteParserState) ExitVariable(ctx *grulev3.VariableContext) {
 		typ, presentType = l.types[name]
 	}
 	if !presentType {
-		l.parseError(fmt.Errorf("could not determine the type of %s", name))
+		l.parseError(unknownResourceError(name, l.types))
 	}
 	var r *ast.Variable
 	if !remote {
@@ -154,6 +167,11 @@ func (l *remoteParserState) ExitVariable(ctx *grulev3.VariableContext) {
 	} else {
 		r = newAssignVariable(l.KnowledgeBase.WorkingMemory, "ext", typ, name)
 		l.remoteResources.Insert(name)
+		if l.inAssignLeft {
+			l.assigned.Insert(name)
+		} else {
+			l.remoteReads.Insert(name)
+		}
 	}
 	l.Stack.Pop()
 	l.Stack.Push(r)
@@ -165,3 +183,10 @@ func (l *remoteParserState) EnterExpression(ctx *grulev3.ExpressionContext) {
 
 	l.GruleV3ParserListener.EnterExpression(ctx)
 }
+
+// sortedSlice returns the sorted elements of set.
+func sortedSlice(set stringset.Set) []string {
+	res := set.Slice()
+	sort.Strings(res)
+	return res
+}
@@ -5,15 +5,17 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/abu-lang/goabu/ecarule"
 	antlr_parser "github.com/abu-lang/goabu/parser/internal/antlr"
 
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	grule_parser "github.com/hyperjumptech/grule-rule-engine/antlr"
+	"github.com/hyperjumptech/grule-rule-engine/antlr/parser/grulev3"
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 	"github.com/hyperjumptech/grule-rule-engine/pkg"
 )
@@ -26,6 +28,29 @@ type localProcessing struct {
 	events []string
 	// localTasks contains the local tasks of the rule currently being processed.
 	localTasks []ecarule.LocalTask
+	// salience contains the salience of the rule currently being processed.
+	salience int
+	// period contains the period of the rule currently being processed, if it is an "every" rule.
+	period time.Duration
+	// delay contains the delay of the rule currently being processed, if it is an "after" rule.
+	delay time.Duration
+	// inAssignLeft reports whether the parser is currently processing an l-value expression.
+	inAssignLeft bool
+	// readOnly, if not nil, contains the resources that the rules and the received tasks cannot assign.
+	readOnly ReadOnly
+	// inputs is true while parsing the actions of an input, which can assign the read-only resources.
+	inputs bool
+}
+
+// readOnlyError returns the error for the assignment of the local resource name if it is read-only
+// and the current actions cannot assign it, otherwise it returns nil.
+func (l *localProcessing) readOnlyError(name string) *Error {
+	if !l.inAssignLeft || l.inputs || l.readOnly == nil || !l.readOnly.IsReadOnly(name) {
+		return nil
+	}
+	res := newError(CategoryReadOnly, "cannot assign %s: the resource is read-only", name)
+	res.Token = name
+	return res
 }
 
 // processing will contain the events and the tasks of the rule currently being processed.
@@ -34,8 +59,6 @@ type processing struct {
 	localProcessing
 	// remoteTasks contains the remote tasks of the rule currently being processed.
 	remoteTasks []ecarule.RemoteTask
-	// inAssignLeft reports whether the parser is currently processing an l-value expression.
-	inAssignLeft bool
 }
 
 // ruleParser is responsible for the parsing step of goabuParser by implementing an ANTLR listener.
@@ -55,6 +78,12 @@ type ruleParser struct {
 	rules []ecarule.Rule
 	// processing contains the events and the tasks of the rule currently being processed.
 	processing
+	// functions, if not nil, contains the functions that can be called.
+	functions Functions
+	// contexts contains the contexts being walked, the innermost last, for locating the errors.
+	contexts []antlr.ParserRuleContext
+	// located is the number of the reported errors that have been located.
+	located int
 }
 
 // newRuleParser constructs a ruleParser given the node resource names, along with the resource types, and the node's [*ast.WorkingMemory].
@@ -82,7 +111,48 @@ func (l *ruleParser) reset(tokenStream antlr.TokenStream) {
 	l.local.reset(tokenStream)
 	l.remote.reset(tokenStream)
 	l.received.reset(tokenStream)
+	l.parserState = l.local
+	l.contexts = l.contexts[:0]
+	l.located = 0
 	l.rules = nil
+	l.events = nil
+	l.salience = 0
+	l.period = 0
+	l.delay = 0
+}
+
+// EnterEveryRule is called when any production is entered.
+func (l *ruleParser) EnterEveryRule(ctx antlr.ParserRuleContext) {
+	l.locateErrors()
+	l.contexts = append(l.contexts, ctx)
+}
+
+// ExitEveryRule is called when any production is exited.
+func (l *ruleParser) ExitEveryRule(ctx antlr.ParserRuleContext) {
+	l.locateErrors()
+	l.contexts = l.contexts[:len(l.contexts)-1]
+}
+
+// VisitTerminal is called when a terminal node is visited.
+func (l *ruleParser) VisitTerminal(node antlr.TerminalNode) {
+	l.locateErrors()
+	l.parserState.VisitTerminal(node)
+}
+
+// locateErrors converts the errors reported since its last call into [*Error]s located at the
+// start of the innermost context being walked, which is the one that caused them.
+func (l *ruleParser) locateErrors() {
+	errs := l.local.ErrorCallback.Errors
+	for ; l.located < len(errs); l.located++ {
+		e, ok := errs[l.located].(*Error)
+		if !ok {
+			e = newError(CategoryInvalid, "%s", errs[l.located].Error())
+			errs[l.located] = e
+		}
+		if len(l.contexts) > 0 {
+			e.locate(l.contexts[len(l.contexts)-1].GetStart())
+		}
+	}
 }
 
 // EnterPrule is called when production prule is entered.
@@ -99,15 +169,50 @@ func (l *ruleParser) ExitPrule(ctx *antlr_parser.PruleContext) {
 	if l.isParsingHalted() {
 		return
 	}
+	name := ctx.SIMPLENAME().GetText()
+	for i := range l.remoteTasks {
+		l.remoteTasks[i].Salience = l.salience
+		l.remoteTasks[i].Rule = name
+		l.remoteTasks[i].Index = i
+	}
+	start, stop := ctx.GetStart(), ctx.GetStop()
 	l.rules = append(l.rules, ecarule.Rule{
-		Name:        ctx.SIMPLENAME().GetText(),
+		Name:        name,
+		Source:      start.GetInputStream().GetTextFromInterval(antlr.NewInterval(start.GetStart(), stop.GetStop())),
+		Salience:    l.salience,
 		Events:      l.events,
+		Period:      l.period,
+		Delay:       l.delay,
 		LocalTasks:  l.localTasks,
 		RemoteTasks: l.remoteTasks,
 	})
 	l.events = nil
 	l.localTasks = nil
 	l.remoteTasks = nil
+	l.salience = 0
+	l.period = 0
+	l.delay = 0
+}
+
+// EnterSalience is called when production salience is entered.
+func (l *ruleParser) EnterSalience(ctx *grulev3.SalienceContext) {
+	if l.isParsingHalted() {
+		return
+	}
+	l.push(salienceReceiver{salience: &l.salience})
+	l.parserState.EnterSalience(ctx)
+}
+
+// ExitSalience is called when production salience is exited.
+func (l *ruleParser) ExitSalience(ctx *grulev3.SalienceContext) {
+	if l.isParsingHalted() {
+		return
+	}
+	l.parserState.ExitSalience(ctx)
+	if l.isParsingHalted() {
+		return
+	}
+	l.pop()
 }
 
 // EnterEvents is called when production events is entered.
@@ -116,7 +221,7 @@ func (l *ruleParser) EnterEvents(ctx *antlr_parser.EventsContext) {
 		return
 	}
 	if len(l.events) > 0 {
-		l.parseError(errors.New("syntax error"))
+		l.parseError(newError(CategorySyntax, "syntax error: events already specified"))
 		return
 	}
 	for i := 0; ctx.SIMPLENAME(i) != nil; i++ {
@@ -124,6 +229,39 @@ func (l *ruleParser) EnterEvents(ctx *antlr_parser.EventsContext) {
 	}
 }
 
+// EnterTimer is called when production timer is entered.
+func (l *ruleParser) EnterTimer(ctx *antlr_parser.TimerContext) {
+	if l.isParsingHalted() {
+		return
+	}
+	d, err := time.ParseDuration(ctx.Duration().GetText())
+	if err != nil || d <= 0 {
+		e := newError(CategorySyntax, "invalid duration %s", ctx.Duration().GetText())
+		if err == nil {
+			e = newError(CategoryInvalid, "duration %s is not positive", ctx.Duration().GetText())
+		}
+		e.locate(ctx.Duration().GetStart())
+		l.parseError(e)
+		return
+	}
+	switch keyword := ctx.SIMPLENAME().GetText(); strings.ToLower(keyword) {
+	case "every":
+		if ctx.ON() != nil {
+			l.parseError(newError(CategoryInvalid, "periodic rules cannot have events"))
+			return
+		}
+		l.period = d
+	case "after":
+		if ctx.ON() == nil {
+			l.parseError(newError(CategoryInvalid, "delayed rules must have events"))
+			return
+		}
+		l.delay = d
+	default:
+		l.parseError(newError(CategorySyntax, "syntax error: unexpected %s, expecting on, every or after", keyword))
+	}
+}
+
 // EnterDefaultActions is called when production defaultActions is entered.
 func (l *ruleParser) EnterDefaultActions(ctx *antlr_parser.DefaultActionsContext) {
 	if l.isParsingHalted() {
@@ -131,7 +269,7 @@ func (l *ruleParser) EnterDefaultActions(ctx *antlr_parser.DefaultActionsContext
 	}
 	cond, err := newBooleanLiteralExpression(l.local.KnowledgeBase.WorkingMemory, true)
 	if err != nil {
-		l.parseError(errors.New("error during default actions parsing"))
+		l.parseError(newError(CategoryInvalid, "error during default actions parsing"))
 		return
 	}
 	l.localTasks = append(l.localTasks, ecarule.LocalTask{Condition: cond})
@@ -173,6 +311,17 @@ func (l *ruleParser) ExitTask(ctx *antlr_parser.TaskContext) {
 	}
 }
 
+// salienceReceiver implements [ast.SalienceReceiver] by storing the accepted salience value.
+type salienceReceiver struct {
+	salience *int
+}
+
+// AcceptSalience stores the value of the accepted [*ast.Salience].
+func (r salienceReceiver) AcceptSalience(salience *ast.Salience) error {
+	*r.salience = salience.SalienceValue
+	return nil
+}
+
 // newAssignVariable constructs a [*ast.Variable] encoding a GoAbU resource.
 func newAssignVariable(workingMemory *ast.WorkingMemory, prefix, typ, name string) *ast.Variable {
 	pre := ast.NewVariable()
@@ -225,3 +374,19 @@ func newBooleanLiteralExpression(workingMemory *ast.WorkingMemory, b bool) (*ast
 	}
 	return workingMemory.AddExpression(exp), nil
 }
+
+// EnterFunctionCall is called when production functionCall is entered.
+func (l *ruleParser) EnterFunctionCall(ctx *grulev3.FunctionCallContext) {
+	if l.isParsingHalted() {
+		return
+	}
+	// the functions of the remote tasks are called by the other nodes
+	if _, method := ctx.GetParent().(*antlr_parser.MethodCallContext); !method && l.functions != nil && l.parserState != parserState(l.remote) {
+		name := ctx.GetStart().GetText()
+		if !l.functions.HasFunction(name) {
+			l.parseError(newError(CategoryUndefinedFunction, "undefined function %s", name))
+			return
+		}
+	}
+	l.parserState.EnterFunctionCall(ctx)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package parser
+
+import (
+	"reflect"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// staticType returns the type of the values of exp (one of "Bool", "Integer", "Float", "Text", "Time", "List" and "Map")
+// if it can be determined without evaluating exp, otherwise it returns the empty string.
+func staticType(exp *ast.Expression) string {
+	switch {
+	case exp == nil:
+		return ""
+	case exp.Negated:
+		return "Bool"
+	case exp.SingleExpression != nil:
+		return staticType(exp.SingleExpression)
+	case exp.ExpressionAtom != nil:
+		return atomType(exp.ExpressionAtom)
+	case exp.LeftExpression == nil || exp.RightExpression == nil:
+		return ""
+	}
+	left, right := staticType(exp.LeftExpression), staticType(exp.RightExpression)
+	switch exp.Operator {
+	case ast.OpAnd, ast.OpOr, ast.OpEq, ast.OpNEq, ast.OpGT, ast.OpGTE, ast.OpLT, ast.OpLTE:
+		return "Bool"
+	case ast.OpAdd:
+		if left == "Text" || right == "Text" {
+			return "Text"
+		}
+		fallthrough
+	case ast.OpSub, ast.OpMul, ast.OpDiv, ast.OpMod:
+		switch {
+		case !isNumeric(left) || !isNumeric(right):
+			return ""
+		case left == "Integer" && right == "Integer" && exp.Operator != ast.OpDiv:
+			return "Integer"
+		default:
+			return "Float"
+		}
+	case ast.OpBitAnd, ast.OpBitOr:
+		return "Integer"
+	}
+	return ""
+}
+
+// atomType returns the type of the values of atom, if it can be determined without evaluating atom.
+func atomType(atom *ast.ExpressionAtom) string {
+	switch {
+	case atom.Negated:
+		return "Bool"
+	case atom.Constant != nil && !atom.Constant.IsNil:
+		switch atom.Constant.Value.Kind() {
+		case reflect.Bool:
+			return "Bool"
+		case reflect.Int64:
+			return "Integer"
+		case reflect.Float64:
+			return "Float"
+		case reflect.String:
+			return "Text"
+		}
+	case atom.Variable != nil:
+		return resourceType(atom.Variable)
+	}
+	return ""
+}
+
+// resourceType returns the type of the resource denoted by v (see newAssignVariable), if it is known.
+func resourceType(v *ast.Variable) string {
+	if v.ArrayMapSelector == nil || v.Variable == nil || v.Variable.Variable == nil || v.Variable.Variable.Variable != nil {
+		return ""
+	}
+	switch v.Variable.Name {
+	case "Bool", "Integer", "Float", "Text", "Time", "List", "Map":
+		return v.Variable.Name
+	}
+	return ""
+}
+
+// isElement reports whether v denotes an element of a resource (see newAssignVariable).
+func isElement(v *ast.Variable) bool {
+	return v != nil && v.ArrayMapSelector != nil && v.Variable != nil && v.Variable.ArrayMapSelector != nil
+}
+
+func isNumeric(typ string) bool {
+	return typ == "Integer" || typ == "Float"
+}
+
+// compatibleTypes reports whether a value of type value may be assigned to a resource of type resource,
+// the unknown types are compatible with every type. An Integer may be assigned to a Float but not vice versa.
+func compatibleTypes(resource, value string) bool {
+	return resource == "" || value == "" || resource == value || resource == "Float" && value == "Integer"
+}
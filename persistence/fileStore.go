@@ -0,0 +1,245 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	snapshotFile = "snapshot"
+	walFile      = "wal"
+	// headerSize is the size of the header of a record: its length followed by its checksum.
+	headerSize = 8
+	// maxRecordSize bounds the length read from the header of a possibly corrupted record.
+	maxRecordSize = 1 << 26
+)
+
+// FileStore is a [Store] keeping the last snapshot and the write-ahead log as files in a directory.
+//
+// Every record is framed with its length and its CRC-32 checksum and the file is synced after
+// each write, so a record is durable when Append returns. A record that was only partially
+// written because of a crash is detected and discarded, along with anything following it,
+// when the FileStore is opened.
+type FileStore struct {
+	dir      string
+	wal      *os.File
+	sequence uint64
+	lock     sync.Mutex
+}
+
+// NewFileStore opens the FileStore in dir creating the directory if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	res := &FileStore{dir: dir}
+	snapshot, err := res.readSnapshot()
+	if err != nil && !errors.Is(err, ErrNoSnapshot) {
+		return nil, err
+	}
+	res.sequence = snapshot.Sequence
+	res.wal, err = os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	records, valid, err := readRecords(res.wal)
+	if err == nil {
+		// discards a torn tail
+		err = res.wal.Truncate(valid)
+	}
+	if err == nil {
+		_, err = res.wal.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		res.wal.Close()
+		return nil, err
+	}
+	if len(records) > 0 && records[len(records)-1].Sequence > res.sequence {
+		res.sequence = records[len(records)-1].Sequence
+	}
+	return res, nil
+}
+
+// Append durably appends r to the write-ahead log assigning it the next sequence number.
+func (f *FileStore) Append(r Record) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	r.Sequence = f.sequence + 1
+	var payload bytes.Buffer
+	err := gob.NewEncoder(&payload).Encode(r)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, headerSize, headerSize+payload.Len())
+	binary.BigEndian.PutUint32(frame, uint32(payload.Len()))
+	binary.BigEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(payload.Bytes()))
+	frame = append(frame, payload.Bytes()...)
+	_, err = f.wal.Write(frame)
+	if err != nil {
+		return err
+	}
+	err = f.wal.Sync()
+	if err != nil {
+		return err
+	}
+	f.sequence = r.Sequence
+	return nil
+}
+
+// SaveSnapshot durably replaces the last snapshot with s and empties the write-ahead log.
+// The snapshot is written to a temporary file that is then renamed, so a crash never
+// leaves a partially written snapshot.
+func (f *FileStore) SaveSnapshot(s Snapshot) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	s.Sequence = f.sequence
+	tmp, err := os.CreateTemp(f.dir, snapshotFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	err = gob.NewEncoder(w).Encode(s)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp.Name(), filepath.Join(f.dir, snapshotFile))
+	if err != nil {
+		return err
+	}
+	err = syncDir(f.dir)
+	if err != nil {
+		return err
+	}
+	// records preceding the snapshot are ignored by Load even if the truncation is lost
+	err = f.wal.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = f.wal.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	return f.wal.Sync()
+}
+
+// Load returns the last snapshot along with the records appended after it in order.
+func (f *FileStore) Load() (Snapshot, []Record, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	snapshot, err := f.readSnapshot()
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	_, err = f.wal.Seek(0, io.SeekStart)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	records, valid, err := readRecords(f.wal)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	_, err = f.wal.Seek(valid, io.SeekStart)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	var res []Record
+	for _, r := range records {
+		if r.Sequence > snapshot.Sequence {
+			res = append(res, r)
+		}
+	}
+	return snapshot, res, nil
+}
+
+// Close closes the write-ahead log.
+func (f *FileStore) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.wal.Close()
+}
+
+// readSnapshot reads the snapshot file of f, it returns ErrNoSnapshot if the file does not exist.
+func (f *FileStore) readSnapshot() (Snapshot, error) {
+	var res Snapshot
+	file, err := os.Open(filepath.Join(f.dir, snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return res, ErrNoSnapshot
+	}
+	if err != nil {
+		return res, err
+	}
+	defer file.Close()
+	err = gob.NewDecoder(bufio.NewReader(file)).Decode(&res)
+	return res, err
+}
+
+// readRecords reads the records from r until the end of the input or the first invalid record.
+// It returns the valid records along with the number of bytes they occupy.
+func readRecords(r io.Reader) ([]Record, int64, error) {
+	var res []Record
+	var valid int64 = 0
+	reader := bufio.NewReader(r)
+	header := make([]byte, headerSize)
+	for {
+		_, err := io.ReadFull(reader, header)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return res, valid, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		length := binary.BigEndian.Uint32(header)
+		if length > maxRecordSize {
+			return res, valid, nil
+		}
+		payload := make([]byte, length)
+		_, err = io.ReadFull(reader, payload)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return res, valid, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			return res, valid, nil
+		}
+		var record Record
+		if gob.NewDecoder(bytes.NewReader(payload)).Decode(&record) != nil {
+			return res, valid, nil
+		}
+		res = append(res, record)
+		valid += int64(headerSize + len(payload))
+	}
+}
+
+// syncDir makes the last changes to the entries of the directory dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
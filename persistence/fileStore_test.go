@@ -0,0 +1,157 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package persistence_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/persistence"
+)
+
+func enqueued(id uint64, resource string, value interface{}) persistence.Record {
+	return persistence.Record{
+		Kind: persistence.RecordEnqueued,
+		Updates: []persistence.Update{{
+			ID:          id,
+			Assignments: []persistence.Assignment{{Resource: resource, Value: value}},
+		}},
+	}
+}
+
+func openStore(t *testing.T, dir string) *persistence.FileStore {
+	s, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir)
+	if _, _, err := s.Load(); err != persistence.ErrNoSnapshot {
+		t.Fatal("Load should return ErrNoSnapshot, got", err)
+	}
+	r := memory.MakeResources()
+	r.Integer["foo"] = 42
+	r.Time["bar"] = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := persistence.Snapshot{
+		Resources:  r,
+		Rules:      []persistence.Rule{{Name: "r", Source: "rule r on foo for true do foo = 0", Enabled: true}},
+		Invariants: []persistence.Invariant{{Name: "positive", Source: "foo >= 0"}},
+	}
+	err := s.Append(enqueued(1, "foo", int64(1)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = s.SaveSnapshot(snapshot)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	records := []persistence.Record{
+		enqueued(2, "foo", int64(2)),
+		enqueued(3, "bar", time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	for _, rec := range records {
+		err = s.Append(rec)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	s.Close()
+	s = openStore(t, dir)
+	loaded, loadedRecords, err := s.Load()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if loaded.Sequence != 1 {
+		t.Error("snapshot should follow record #1, got", loaded.Sequence)
+	}
+	if loaded.Resources.Integer["foo"] != 42 || !loaded.Resources.Time["bar"].Equal(r.Time["bar"]) {
+		t.Error("unexpected resources:", loaded.Resources)
+	}
+	if !reflect.DeepEqual(loaded.Rules, snapshot.Rules) || !reflect.DeepEqual(loaded.Invariants, snapshot.Invariants) {
+		t.Error("unexpected rules or invariants:", loaded.Rules, loaded.Invariants)
+	}
+	if len(loadedRecords) != 2 {
+		t.Fatal("records appended before the snapshot should be discarded, got", loadedRecords)
+	}
+	for i, rec := range loadedRecords {
+		if rec.Sequence != uint64(i+2) {
+			t.Errorf("record #%d should have sequence %d, got %d", i, i+2, rec.Sequence)
+		}
+		rec.Sequence = 0
+		if !reflect.DeepEqual(rec, records[i]) {
+			t.Errorf("record #%d should be %v, got %v", i, records[i], rec)
+		}
+	}
+}
+
+func TestTornLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(wal []byte) []byte
+		// records is the number of valid records after the corruption
+		records int
+	}{
+		{"truncated", func(wal []byte) []byte { return wal[:len(wal)-3] }, 2},
+		{"garbage", func(wal []byte) []byte { return append(wal, 0, 0, 0, 9, 1, 2) }, 3},
+		{"flipped", func(wal []byte) []byte { wal[len(wal)-1] ^= 0xff; return wal }, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := openStore(t, dir)
+			err := s.SaveSnapshot(persistence.Snapshot{Resources: memory.MakeResources()})
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for i := 1; i <= 3; i++ {
+				err = s.Append(enqueued(uint64(i), "foo", int64(i)))
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+			}
+			s.Close()
+			path := filepath.Join(dir, "wal")
+			wal, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			err = os.WriteFile(path, test.corrupt(wal), 0o644)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			s = openStore(t, dir)
+			_, records, err := s.Load()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			expected := test.records
+			if len(records) != expected {
+				t.Fatalf("expected %d records, got %d", expected, len(records))
+			}
+			// appending after recovery does not leave the corrupted data in between
+			err = s.Append(enqueued(4, "foo", int64(4)))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			s.Close()
+			s = openStore(t, dir)
+			_, records, err = s.Load()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if len(records) != expected+1 || records[expected].Updates[0].ID != 4 {
+				t.Error("record appended after recovery should be loaded, got", records)
+			}
+		})
+	}
+}
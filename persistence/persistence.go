@@ -0,0 +1,119 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+// Package persistence implements the durable storage of the state of a GoAbU node.
+//
+// The state is stored as a [Snapshot] of the resources, of the pool of pending updates and of the
+// rules of the node, followed by a write-ahead log of the [Record]s describing the operations
+// performed since the snapshot was taken.
+package persistence
+
+import (
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"github.com/abu-lang/goabu/memory"
+)
+
+// ErrNoSnapshot is returned by [Store.Load] when no snapshot has been saved.
+var ErrNoSnapshot = errors.New("no snapshot found")
+
+func init() {
+	// values of the Time resources are stored as interface{} in Assignments
+	gob.Register(time.Time{})
+}
+
+// Assignment is the durable representation of an assignment of a value to a resource.
+//
+// Value is encoded by means of encoding/gob: the concrete types of the values of Other resources
+// must be registered with [gob.Register].
+type Assignment struct {
+	Resource string
+	Value    interface{}
+}
+
+// Update is the durable representation of a list of assignments that are to be performed atomically.
+type Update struct {
+	// ID identifies the update among the ones of the node, it is 0 for the updates
+	// produced by inputs.
+	ID          uint64
+	Assignments []Assignment
+	Rule        string
+	Task        int
+	Salience    int
+	// Source specifies where the update originated.
+	Source      int
+	Initiator   string
+	Transaction string
+	Enqueued    time.Time
+	Depth       int
+	// TraceID and SpanID identify the span that caused the update.
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// Rule is the durable representation of a rule of the node.
+type Rule struct {
+	Name string
+	// Source is the code of the rule.
+	Source  string
+	Enabled bool
+}
+
+// Invariant is the durable representation of a named invariant of the node.
+type Invariant struct {
+	Name string
+	// Source is the code of the invariant.
+	Source string
+	// Repair is the code of the actions repairing the inputs that violate the invariant, if any.
+	Repair string
+}
+
+// Snapshot contains the whole state of a node at a given moment.
+type Snapshot struct {
+	// Sequence is the sequence number of the last Record preceding the snapshot, it is set by the Store.
+	Sequence   uint64
+	Resources  memory.Resources
+	Pool       []Update
+	Rules      []Rule
+	Invariants []Invariant
+}
+
+// RecordKind specifies the operation described by a [Record].
+type RecordKind int
+
+const (
+	// RecordEnqueued marks the addition of Record.Updates to the pool.
+	RecordEnqueued RecordKind = iota + 1
+	// RecordApplied marks the execution of Record.Updates[0]: its ID identifies the update removed
+	// from the pool (if different from 0) and its Assignments are the ones that modified the resources.
+	RecordApplied
+	// RecordRules marks the substitution of the rules of the node with Record.Rules.
+	RecordRules
+	// RecordInvariants marks the substitution of the invariants of the node with Record.Invariants.
+	RecordInvariants
+)
+
+// Record is an entry of the write-ahead log.
+type Record struct {
+	// Sequence is the sequence number of the record, it is set by the Store.
+	Sequence   uint64
+	Kind       RecordKind
+	Updates    []Update
+	Rules      []Rule
+	Invariants []Invariant
+}
+
+// Store is the interface of the backends providing durable storage for snapshots and records.
+type Store interface {
+	// Append durably appends r to the log assigning it the next sequence number.
+	Append(r Record) error
+	// SaveSnapshot durably replaces the last snapshot with s and discards the records appended before s.
+	SaveSnapshot(s Snapshot) error
+	// Load returns the last snapshot along with the records appended after it in order.
+	// If no snapshot has been saved it returns ErrNoSnapshot.
+	Load() (Snapshot, []Record, error)
+	// Close releases the resources held by the Store.
+	Close() error
+}
@@ -11,3 +11,17 @@ type IOdelegate interface {
 	Start(IOadaptor, chan<- string, chan<- error) error
 	Modified(IOadaptor, string, memory.Resources, chan<- error) *memory.Resources
 }
+
+// TypedIOdelegate is implemented by the IOdelegates that can provide their inputs as typed events
+// rather than as strings: IOresources starts them by means of StartTyped instead of Start.
+type TypedIOdelegate interface {
+	IOdelegate
+	StartTyped(IOadaptor, chan<- memory.InputEvent, chan<- error) error
+}
+
+// StoppableIOdelegate is implemented by the IOdelegates that acquire resources in Start, e.g. goroutines
+// reading their inputs: IOresources stops them by means of Stop when it is stopped.
+type StoppableIOdelegate interface {
+	IOdelegate
+	Stop(IOadaptor) error
+}
@@ -33,6 +33,7 @@ type IOresources struct {
 	memory.Resources
 	adaptor   IOadaptor
 	inputs    chan string
+	events    chan memory.InputEvent
 	errors    chan error
 	delegates []*resource
 	managers  map[string]*resource
@@ -44,6 +45,7 @@ func MakeEmptyIOresources(a IOadaptor) *IOresources {
 		Resources: memory.MakeResources(),
 		adaptor:   a,
 		inputs:    make(chan string),
+		events:    make(chan memory.InputEvent),
 		errors:    make(chan error),
 		managers:  make(map[string]*resource),
 		frames:    make(map[string]frame),
@@ -56,7 +58,11 @@ func (i *IOresources) Start() error {
 		return err
 	}
 	for _, r := range i.delegates {
-		err = r.Start(i.adaptor, i.inputs, i.errors)
+		if typed, ok := r.IOdelegate.(TypedIOdelegate); ok {
+			err = typed.StartTyped(i.adaptor, i.events, i.errors)
+		} else {
+			err = r.Start(i.adaptor, i.inputs, i.errors)
+		}
 		if err != nil {
 			return err
 		}
@@ -64,10 +70,33 @@ func (i *IOresources) Start() error {
 	return nil
 }
 
+func (i *IOresources) Stop() error {
+	var res error
+	for _, r := range i.delegates {
+		stoppable, ok := r.IOdelegate.(StoppableIOdelegate)
+		if !ok {
+			continue
+		}
+		err := stoppable.Stop(i.adaptor)
+		if err != nil && res == nil {
+			res = err
+		}
+	}
+	err := i.adaptor.Finalize()
+	if res == nil {
+		res = err
+	}
+	return res
+}
+
 func (i *IOresources) Inputs() <-chan string {
 	return i.inputs
 }
 
+func (i *IOresources) InputEvents() <-chan memory.InputEvent {
+	return i.events
+}
+
 func (i *IOresources) Errors() <-chan error {
 	return i.errors
 }
@@ -91,6 +120,8 @@ func (i *IOresources) Copy() memory.ResourceController {
 		Resources: i.Resources.Copy().GetResources(),
 		adaptor:   i.adaptor,
 		inputs:    i.inputs,
+		events:    i.events,
+		errors:    i.errors,
 		delegates: i.delegates,
 		managers:  i.managers,
 		frames:    i.frames,
@@ -133,6 +164,9 @@ func (i *IOresources) Add(t string, name string, args ...interface{}) error {
 		}
 	}
 	i.Enclose(newResources)
+	for k, m := range newResources.Meta {
+		i.Meta[k] = m
+	}
 	resource.IOdelegate = delegate
 	resource.managed = managed
 	i.delegates = append(i.delegates, resource)
@@ -160,7 +194,7 @@ func (i *IOresources) addFrame(input, output bool, t string, c func(IOadaptor, s
 
 // nestResources returns the Resources argument if it contains at most one resource
 // otherwise it returns a Resources struct where the names of the resources are prefixed
-// with the string argument and an '_'.
+// with the string argument and an '_', along with their metadata.
 func nestResources(name string, r memory.Resources) memory.Resources {
 	if len(r.ResourceNames()) < 2 {
 		return r
@@ -184,5 +218,14 @@ func nestResources(name string, r memory.Resources) memory.Resources {
 	for k, v := range r.Other {
 		res.Other[name+"_"+k] = v
 	}
+	for k, v := range r.List {
+		res.List[name+"_"+k] = v
+	}
+	for k, v := range r.Map {
+		res.Map[name+"_"+k] = v
+	}
+	for k, v := range r.Meta {
+		res.Meta[name+"_"+k] = v
+	}
 	return res
 }
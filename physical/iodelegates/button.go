@@ -6,16 +6,21 @@ package iodelegates
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/abu-lang/goabu/memory"
 	"github.com/abu-lang/goabu/physical"
 
+	"gobot.io/x/gobot/v2"
 	"gobot.io/x/gobot/v2/drivers/gpio"
 )
 
 type Button struct {
 	name   string
 	driver *gpio.ButtonDriver
+	quit   chan bool
+	// stop guarantees that quit is closed only once
+	stop *sync.Once
 }
 
 func MakeButton(adaptor physical.IOadaptor, name string, args ...interface{}) (physical.IOdelegate, memory.Resources, error) {
@@ -28,7 +33,8 @@ func MakeButton(adaptor physical.IOadaptor, name string, args ...interface{}) (p
 	}
 	resources := memory.MakeResources()
 	resources.Bool[name] = false
-	return Button{name: name, driver: gpio.NewButtonDriver(adaptor, pin)}, resources, nil
+	resources.Meta[name] = memory.Metadata{ReadOnly: true, Description: "whether the button is pressed"}
+	return Button{name: name, driver: gpio.NewButtonDriver(adaptor, pin), quit: make(chan bool), stop: &sync.Once{}}, resources, nil
 }
 
 func (b Button) Start(adaptor physical.IOadaptor, inputs chan<- string, errors chan<- error) error {
@@ -36,23 +42,48 @@ func (b Button) Start(adaptor physical.IOadaptor, inputs chan<- string, errors c
 	if err != nil {
 		return err
 	}
-	go b.getButtonInput(inputs, errors)
+	go getButtonInput(b, inputs, b.name+" = true,", b.name+" = false,", errors)
 	return nil
 }
 
+func (b Button) StartTyped(adaptor physical.IOadaptor, events chan<- memory.InputEvent, errors chan<- error) error {
+	err := b.driver.Start()
+	if err != nil {
+		return err
+	}
+	push := memory.InputEvent{Resource: b.name, Value: true}
+	release := memory.InputEvent{Resource: b.name, Value: false}
+	go getButtonInput(b, events, push, release, errors)
+	return nil
+}
+
+// Stop stops the goroutine reading the inputs of b and halts its driver, it can be called more than once.
+func (b Button) Stop(adaptor physical.IOadaptor) error {
+	var err error
+	b.stop.Do(func() {
+		close(b.quit)
+		err = b.driver.Halt()
+	})
+	return err
+}
+
 func (b Button) Modified(adaptor physical.IOadaptor, name string, resources memory.Resources, errors chan<- error) *memory.Resources {
 	return nil
 }
 
-func (b Button) getButtonInput(in chan<- string, errs chan<- error) {
+// getButtonInput sends push and release over in when b is pushed and released.
+func getButtonInput[T any](b Button, in chan<- T, push, release T, errs chan<- error) {
 	events := b.driver.Subscribe()
 	status := false
-	push := b.name + " = true,"
-	release := b.name + " = false,"
-	event := <-events
+	var event *gobot.Event
+	select {
+	case event = <-events:
+	case <-b.quit:
+		return
+	}
 	for {
-		var inputs chan<- string = nil
-		var action string
+		var inputs chan<- T = nil
+		var action T
 		switch event.Name {
 		case gpio.ButtonPush:
 			action = push
@@ -71,6 +102,8 @@ func (b Button) getButtonInput(in chan<- string, errs chan<- error) {
 		case inputs <- action:
 			status = !status
 		case event = <-events:
+		case <-b.quit:
+			return
 		}
 	}
 }
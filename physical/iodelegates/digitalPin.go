@@ -24,6 +24,7 @@ func MakeDigitalPin(adaptor physical.IOadaptor, name string, args ...interface{}
 	}
 	resources := memory.MakeResources()
 	resources.Bool[name] = false
+	resources.Meta[name] = memory.Metadata{Description: "level of the digital output pin " + pin}
 	return DigitalPin{pin: pin}, resources, nil
 }
 
@@ -31,6 +31,11 @@ func MakeMotor(adaptor physical.IOadaptor, name string, args ...interface{}) (ph
 	}
 	resources := memory.MakeResources()
 	resources.Integer[name] = 0
+	resources.Meta[name] = memory.Metadata{
+		Min:         memory.Bound(-255),
+		Max:         memory.Bound(255),
+		Description: "speed of the motor, negative when turning backward",
+	}
 	return Motor{forwardPin: forward, backwardPin: backward}, resources, nil
 }
 
@@ -38,6 +43,10 @@ func (m Motor) Start(adaptor physical.IOadaptor, inputs chan<- string, errors ch
 	return nil
 }
 
+func (m Motor) Stop(adaptor physical.IOadaptor) error {
+	return m.set(adaptor, 0, true)
+}
+
 func (m Motor) Modified(adaptor physical.IOadaptor, name string, resources memory.Resources, errors chan<- error) *memory.Resources {
 	speed := resources.Integer[name]
 	forward := speed >= 0
@@ -0,0 +1,179 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ruleanalysis implements a static analysis of sets of GoAbU rules.
+//
+// The analysis assumes, as usual in GoAbU systems, that every node runs the same rules: the resources
+// written by remote tasks on the other nodes are therefore the resources with the same name of the node
+// whose rules are analysed.
+package ruleanalysis
+
+import (
+	"sort"
+
+	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/parser"
+	"github.com/abu-lang/goabu/stringset"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Access describes how a rule uses the resources, all the lists are sorted.
+type Access struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Events contains the resources whose changes activate the rule.
+	Events []string
+	// LocalReads contains the local resources read by the conditions and by the actions of the tasks.
+	LocalReads []string
+	// LocalWrites contains the local resources assigned by the local tasks.
+	LocalWrites []string
+	// RemoteReads contains the resources of the other nodes read by the remote tasks.
+	RemoteReads []string
+	// RemoteWrites contains the resources of the other nodes assigned by the remote tasks.
+	RemoteWrites []string
+}
+
+// Conflict reports the rules that could assign the same resource.
+type Conflict struct {
+	// Resource is the name of the resource.
+	Resource string
+	// Rules contains the sorted names of the rules assigning Resource, locally or remotely.
+	Rules []string
+}
+
+// Report is the result of the analysis of a set of rules.
+type Report struct {
+	// Resources contains the sorted names of the analysed resources.
+	Resources []string
+	// Inputs contains the sorted names of the resources fed by inputs.
+	Inputs []string
+	// Rules describes the use of the resources of each rule, sorted by rule name.
+	Rules []Access
+	// Unreachable contains the sorted names of the rules that are never activated:
+	// none of their events is fed by inputs or written by some rule.
+	Unreachable []string
+	// NeverRead contains the sorted names of the resources that are neither read by any rule
+	// nor used as an event.
+	NeverRead []string
+	// Conflicts contains the resources assigned by more than one rule, sorted by resource name.
+	Conflicts []Conflict
+}
+
+// Analyze analyses rules given the types of the node's resources, as returned by the Types method of
+// memory.ResourceController, and the names of the resources fed by inputs. The rules must have been
+// parsed, since the analysis relies on the resources read and assigned by their tasks as recorded by the parser.
+func Analyze(types map[string]string, inputs []string, rules ...ecarule.Rule) Report {
+	res := Report{
+		Resources: sortedKeys(types),
+		Inputs:    sorted(stringset.Make(inputs...)),
+	}
+	read := stringset.Make()
+	written := stringset.Make(inputs...)
+	writers := make(map[string]stringset.Set)
+	addWriter := func(resource, rule string) {
+		if writers[resource] == nil {
+			writers[resource] = stringset.Make()
+		}
+		writers[resource].Insert(rule)
+	}
+	for _, rule := range rules {
+		a := access(rule)
+		res.Rules = append(res.Rules, a)
+		read.Add(stringset.Make(a.Events...))
+		read.Add(stringset.Make(a.LocalReads...))
+		read.Add(stringset.Make(a.RemoteReads...))
+		for _, r := range a.LocalWrites {
+			written.Insert(r)
+			addWriter(r, a.Rule)
+		}
+		for _, r := range a.RemoteWrites {
+			written.Insert(r)
+			addWriter(r, a.Rule)
+		}
+	}
+	sort.Slice(res.Rules, func(i, j int) bool {
+		return res.Rules[i].Rule < res.Rules[j].Rule
+	})
+	for _, rule := range rules {
+		if rule.Period > 0 {
+			continue
+		}
+		reachable := false
+		for _, evt := range rule.Events {
+			if written.Has(evt) {
+				reachable = true
+				break
+			}
+		}
+		if !reachable {
+			res.Unreachable = append(res.Unreachable, rule.Name)
+		}
+	}
+	sort.Strings(res.Unreachable)
+	for _, r := range res.Resources {
+		if !read.Has(r) {
+			res.NeverRead = append(res.NeverRead, r)
+		}
+	}
+	for r, rules := range writers {
+		if rules.Size() > 1 {
+			res.Conflicts = append(res.Conflicts, Conflict{Resource: r, Rules: sorted(rules)})
+		}
+	}
+	sort.Slice(res.Conflicts, func(i, j int) bool {
+		return res.Conflicts[i].Resource < res.Conflicts[j].Resource
+	})
+	return res
+}
+
+// AnalyzeSources parses the given rules, according to the types of the node's resources, and analyses them
+// as Analyze does. If the rules cannot be parsed the first error is returned.
+func AnalyzeSources(types map[string]string, inputs []string, rules ...string) (Report, error) {
+	p := parser.New(types, ast.NewWorkingMemory("", ""))
+	parsed, errs := p.Parse(rules...)
+	if len(errs) > 0 {
+		return Report{}, errs[0]
+	}
+	return Analyze(types, inputs, parsed...), nil
+}
+
+// access returns how rule uses the resources.
+func access(rule ecarule.Rule) Access {
+	localReads := stringset.Make()
+	remoteReads := stringset.Make()
+	remoteWrites := stringset.Make()
+	for _, task := range rule.LocalTasks {
+		localReads.Add(stringset.Make(task.ReadResources...))
+	}
+	for _, task := range rule.RemoteTasks {
+		localReads.Add(stringset.Make(task.LocalResources...))
+		remoteReads.Add(stringset.Make(task.ReadRemoteResources...))
+		remoteWrites.Add(stringset.Make(task.AssignedResources...))
+	}
+	return Access{
+		Rule:         rule.Name,
+		Events:       sorted(stringset.Make(rule.Events...)),
+		LocalReads:   sorted(localReads),
+		LocalWrites:  rule.LocalWrites(),
+		RemoteReads:  sorted(remoteReads),
+		RemoteWrites: sorted(remoteWrites),
+	}
+}
+
+// sorted returns the sorted elements of set.
+func sorted(set stringset.Set) []string {
+	res := set.Slice()
+	sort.Strings(res)
+	return res
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package ruleanalysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	types := map[string]string{
+		"level":  "Integer",
+		"pump":   "Integer",
+		"alarm":  "Bool",
+		"orphan": "Integer",
+		"unused": "Integer",
+		"beats":  "Integer",
+	}
+	rules := []string{
+		"rule fill on level for level > 5 do pump = level * 10",
+		"rule limit on pump for pump > 50 do alarm = true for all ext.alarm == false do ext.alarm = this.pump, ext.level += 1",
+		"rule lost on orphan for true do pump = 0",
+		"rule Heartbeat every 5s for true do beats = beats + 1",
+	}
+	report, err := AnalyzeSources(types, []string{"level"}, rules...)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []Access{
+		{Rule: "Heartbeat", Events: []string{}, LocalReads: []string{"beats"}, LocalWrites: []string{"beats"}, RemoteReads: []string{}, RemoteWrites: []string{}},
+		{Rule: "fill", Events: []string{"level"}, LocalReads: []string{"level"}, LocalWrites: []string{"pump"}, RemoteReads: []string{}, RemoteWrites: []string{}},
+		{Rule: "limit", Events: []string{"pump"}, LocalReads: []string{"pump"}, LocalWrites: []string{"alarm"}, RemoteReads: []string{"alarm"}, RemoteWrites: []string{"alarm", "level"}},
+		{Rule: "lost", Events: []string{"orphan"}, LocalReads: []string{}, LocalWrites: []string{"pump"}, RemoteReads: []string{}, RemoteWrites: []string{}},
+	}
+	if !reflect.DeepEqual(report.Rules, expected) {
+		t.Errorf("unexpected accesses:\n%+v\nexpected:\n%+v", report.Rules, expected)
+	}
+	if !reflect.DeepEqual(report.Unreachable, []string{"lost"}) {
+		t.Error("unexpected unreachable rules:", report.Unreachable)
+	}
+	if !reflect.DeepEqual(report.NeverRead, []string{"unused"}) {
+		t.Error("unexpected never read resources:", report.NeverRead)
+	}
+	conflicts := []Conflict{{Resource: "pump", Rules: []string{"fill", "lost"}}}
+	if !reflect.DeepEqual(report.Conflicts, conflicts) {
+		t.Error("unexpected conflicts:", report.Conflicts)
+	}
+
+	var b strings.Builder
+	if err = report.WriteDOT(&b); err != nil {
+		t.Fatal(err.Error())
+	}
+	dot := b.String()
+	for _, s := range []string{
+		"digraph rules {",
+		`"level" [shape=ellipse, peripheries=2];`,
+		`"rule:lost" [shape=box, label="lost", color=red];`,
+		`"level" -> "rule:fill";`,
+		`"rule:fill" -> "pump" [style=bold];`,
+		`"alarm" -> "rule:limit" [style=dashed, label="remote"];`,
+		`"rule:limit" -> "level" [style=bold, label="remote"];`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("the graph should contain %s, got:\n%s", s, dot)
+		}
+	}
+
+	if _, err = AnalyzeSources(types, nil, "rule bad on pump for true do absent = 1"); err == nil {
+		t.Error("analysing invalid rules should fail")
+	}
+}
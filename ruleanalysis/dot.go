@@ -0,0 +1,80 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package ruleanalysis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/abu-lang/goabu/stringset"
+)
+
+// WriteDOT writes to w the dependency graph of the analysed rules in the Graphviz DOT language.
+// Rules are drawn as boxes and resources as ellipses: a solid edge goes from each event to the rules it
+// activates, a dashed edge from each read resource to the reading rule and a bold edge from each rule to
+// the resources it assigns. The edges of remote reads and writes are labelled "remote".
+// The unreachable rules are drawn in red and the resources fed by inputs with a double border.
+func (r Report) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph rules {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+	inputs := stringset.Make(r.Inputs...)
+	resources := stringset.Make(r.Resources...)
+	for _, a := range r.Rules {
+		resources.Add(stringset.Make(a.Events...))
+		resources.Add(stringset.Make(a.LocalReads...))
+		resources.Add(stringset.Make(a.LocalWrites...))
+		resources.Add(stringset.Make(a.RemoteReads...))
+		resources.Add(stringset.Make(a.RemoteWrites...))
+	}
+	for _, res := range sorted(resources) {
+		attrs := "shape=ellipse"
+		if inputs.Has(res) {
+			attrs += ", peripheries=2"
+		}
+		fmt.Fprintf(b, "\t%s [%s];\n", resourceNode(res), attrs)
+	}
+	unreachable := stringset.Make(r.Unreachable...)
+	for _, a := range r.Rules {
+		attrs := "shape=box, label=" + strconv.Quote(a.Rule)
+		if unreachable.Has(a.Rule) {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(b, "\t%s [%s];\n", ruleNode(a.Rule), attrs)
+	}
+	for _, a := range r.Rules {
+		rule := ruleNode(a.Rule)
+		for _, res := range a.Events {
+			fmt.Fprintf(b, "\t%s -> %s;\n", resourceNode(res), rule)
+		}
+		for _, res := range a.LocalReads {
+			fmt.Fprintf(b, "\t%s -> %s [style=dashed];\n", resourceNode(res), rule)
+		}
+		for _, res := range a.RemoteReads {
+			fmt.Fprintf(b, "\t%s -> %s [style=dashed, label=\"remote\"];\n", resourceNode(res), rule)
+		}
+		for _, res := range a.LocalWrites {
+			fmt.Fprintf(b, "\t%s -> %s [style=bold];\n", rule, resourceNode(res))
+		}
+		for _, res := range a.RemoteWrites {
+			fmt.Fprintf(b, "\t%s -> %s [style=bold, label=\"remote\"];\n", rule, resourceNode(res))
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// ruleNode returns the identifier of the node of the rule with the given name.
+// Rules and resources can have the same name, hence their identifiers are prefixed.
+func ruleNode(name string) string {
+	return strconv.Quote("rule:" + name)
+}
+
+// resourceNode returns the identifier of the node of the resource with the given name,
+// the name is used as the label of the node.
+func resourceNode(name string) string {
+	return strconv.Quote(name)
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SchedulingPolicy is the interface implemented by the strategies an [Executer] can use for
+// choosing which [Update] of its pool is to be executed by the next call to Exec.
+// Salience takes precedence over the policy: the policy only chooses among the updates of the
+// pool having the highest salience.
+//
+// Choose is always called by the Executer while holding the pool's lock, so an implementation does
+// not need to be safe for concurrent use as long as it is not shared among multiple Executers.
+type SchedulingPolicy interface {
+	// Choose returns the index in pool of the next update to execute. The pool is never empty,
+	// its updates have the same salience and are sorted by arrival order (i.e. pool[0] is the oldest update).
+	Choose(pool []Update) int
+}
+
+// fifoPolicy is a [SchedulingPolicy] executing the updates in their arrival order.
+type fifoPolicy struct{}
+
+// FIFO returns a [SchedulingPolicy] that executes the updates in their arrival order.
+// It is the default policy of an [Executer].
+func FIFO() SchedulingPolicy {
+	return fifoPolicy{}
+}
+
+// Choose returns the index of the oldest update.
+func (p fifoPolicy) Choose(pool []Update) int {
+	return 0
+}
+
+// lifoPolicy is a [SchedulingPolicy] executing the most recent updates first.
+type lifoPolicy struct{}
+
+// LIFO returns a [SchedulingPolicy] that executes the most recent update first.
+func LIFO() SchedulingPolicy {
+	return lifoPolicy{}
+}
+
+// Choose returns the index of the most recent update.
+func (p lifoPolicy) Choose(pool []Update) int {
+	return len(pool) - 1
+}
+
+// randomPolicy is a [SchedulingPolicy] choosing the next update uniformly at random.
+type randomPolicy struct {
+	rand *rand.Rand
+}
+
+// Random returns a [SchedulingPolicy] that chooses the next update uniformly at random.
+// The choices are determined by seed, so policies created with the same seed
+// produce the same sequence of choices given the same sequence of pools.
+func Random(seed int64) SchedulingPolicy {
+	return &randomPolicy{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Choose returns the index of an update chosen uniformly at random.
+func (p *randomPolicy) Choose(pool []Update) int {
+	return p.rand.Intn(len(pool))
+}
+
+// rulePriorityPolicy is a [SchedulingPolicy] that executes first the updates produced by the rules
+// with the highest priority.
+type rulePriorityPolicy struct {
+	priorities map[string]int
+	fallback   int
+}
+
+// RulePriority returns a [SchedulingPolicy] that executes first the updates produced by the rules with the
+// highest priority, updates with the same priority are executed in their arrival order.
+//
+// priorities maps rule names to their priority, the updates produced by rules not in priorities have
+// priority fallback. The updates received from other nodes are matched by the name of the rule of the
+// node that produced them.
+func RulePriority(priorities map[string]int, fallback int) SchedulingPolicy {
+	res := rulePriorityPolicy{
+		priorities: make(map[string]int, len(priorities)),
+		fallback:   fallback,
+	}
+	for r, p := range priorities {
+		res.priorities[r] = p
+	}
+	return res
+}
+
+// Choose returns the index of the oldest update having the highest priority.
+func (p rulePriorityPolicy) Choose(pool []Update) int {
+	res := 0
+	max := math.MinInt
+	for i, u := range pool {
+		priority, present := p.priorities[u.Rule]
+		if !present {
+			priority = p.fallback
+		}
+		if priority > max {
+			res = i
+			max = priority
+		}
+	}
+	return res
+}
+
+// oldestPerResourcePolicy is a [SchedulingPolicy] that serves the resources of the node in a round-robin fashion.
+type oldestPerResourcePolicy struct {
+	// served associates each resource with the last tick in which an update modifying it was chosen.
+	served map[string]uint64
+	// tick counts the choices performed by the policy.
+	tick uint64
+}
+
+// OldestPerResource returns a [SchedulingPolicy] preventing bursts of updates on some resources from
+// delaying the updates on the others: it chooses the oldest update among the ones modifying the
+// resource that has been waiting the most since it was last modified by a chosen update.
+func OldestPerResource() SchedulingPolicy {
+	return &oldestPerResourcePolicy{served: make(map[string]uint64)}
+}
+
+// Choose returns the index of the oldest update modifying the least recently served resource.
+func (p *oldestPerResourcePolicy) Choose(pool []Update) int {
+	res := 0
+	var min uint64 = math.MaxUint64
+	for i, u := range pool {
+		for _, a := range u.Assignments {
+			if p.served[a.Resource] < min {
+				res = i
+				min = p.served[a.Resource]
+			}
+		}
+	}
+	p.tick++
+	for _, a := range pool[res].Assignments {
+		p.served[a.Resource] = p.tick
+	}
+	return res
+}
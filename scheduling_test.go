@@ -0,0 +1,199 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+// mixedPoolInputs are the inputs that newMixedPoolExecuter uses for filling the pool.
+var mixedPoolInputs = []string{"t1 = 1", "t1 = 2", "t3 = 1", "t2 = 1"}
+
+// mixedPoolRules are the rules that newMixedPoolExecuter uses for filling the pool.
+var mixedPoolRules = []string{
+	"rule cosmetic on t1 for true do a = t1",
+	"rule safety on t2 for true do b = t2",
+	"rule remote on t3 for all true do ext.c = this.t3",
+}
+
+// newMixedPoolExecuter creates an Executer whose pool contains, in arrival order, two updates
+// produced by the local rule "cosmetic", an update received from another node (looped back by
+// the MockAgent) and an update produced by the local rule "safety".
+func newMixedPoolExecuter(t *testing.T, policy SchedulingPolicy) *Executer {
+	return newPoolExecuter(t, mixedPoolRules, policy)
+}
+
+// newPoolExecuter creates an Executer with the given rules and fills its pool like newMixedPoolExecuter.
+func newPoolExecuter(t *testing.T, rules []string, policy SchedulingPolicy) *Executer {
+	mem := memory.MakeResources()
+	mem.Integer["t1"] = 0
+	mem.Integer["t2"] = 0
+	mem.Integer["t3"] = 0
+	mem.Integer["a"] = 0
+	mem.Integer["b"] = 0
+	mem.Integer["c"] = 0
+	e, err := NewExecuterAdvanced(mem, rules, MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{Scheduling: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	for i, input := range mixedPoolInputs {
+		err = e.Input(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			_, pool := e.TakeState()
+			if len(pool) == i+1 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("pool should have length %d after input %q, got %v", i+1, input, pool)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	return e
+}
+
+// label returns a string identifying the update u of the pool created by newMixedPoolExecuter.
+func label(u Update) string {
+	name := u.Rule
+	if u.Source == SourceRemote {
+		name = "received"
+	}
+	return fmt.Sprintf("%s(%v)", name, u.Assignments[0].Value)
+}
+
+// execLabel executes an update of the pool of e and returns its label.
+func execLabel(t *testing.T, e *Executer) string {
+	_, before := e.TakeState()
+	e.Exec()
+	_, after := e.TakeState()
+	if len(after) != len(before)-1 {
+		t.Fatal("pool should have length", len(before)-1)
+	}
+	present := make(map[string]int)
+	for _, u := range after {
+		present[label(u)]++
+	}
+	for _, u := range before {
+		l := label(u)
+		if present[l] == 0 {
+			return l
+		}
+		present[l]--
+	}
+	t.Fatal("could not find the executed update")
+	return ""
+}
+
+func TestSchedulingPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   SchedulingPolicy
+		expected []string
+	}{
+		//  {name, policy, expected},
+		{"nil", nil, []string{"cosmetic(1)", "cosmetic(2)", "received(1)", "safety(1)"}},
+		{"FIFO", FIFO(), []string{"cosmetic(1)", "cosmetic(2)", "received(1)", "safety(1)"}},
+		{"LIFO", LIFO(), []string{"safety(1)", "received(1)", "cosmetic(2)", "cosmetic(1)"}},
+		{"RulePriority", RulePriority(map[string]int{"safety": 10, "cosmetic": -1}, 0),
+			[]string{"safety(1)", "received(1)", "cosmetic(1)", "cosmetic(2)"}},
+		{"RulePriorityFallback", RulePriority(map[string]int{"safety": 10, "cosmetic": -1}, -5),
+			[]string{"safety(1)", "cosmetic(1)", "cosmetic(2)", "received(1)"}},
+		{"OldestPerResource", OldestPerResource(), []string{"cosmetic(1)", "received(1)", "safety(1)", "cosmetic(2)"}},
+	}
+	for _, test := range tests {
+		t.Run("TestSchedulingPolicies#"+test.name, func(t *testing.T) {
+			e := newMixedPoolExecuter(t, test.policy)
+			for i, expected := range test.expected {
+				if l := execLabel(t, e); l != expected {
+					t.Errorf("execution #%d should be %s, got %s", i+1, expected, l)
+				}
+			}
+			if !e.DoIfStable(func() {}) {
+				t.Error("should be stable")
+			}
+		})
+	}
+}
+
+func TestRandomPolicy(t *testing.T) {
+	const seed = 42
+	e := newMixedPoolExecuter(t, Random(seed))
+	_, pool := e.TakeState()
+	var expected []string
+	reference := Random(seed)
+	for len(pool) > 0 {
+		i := reference.Choose(pool)
+		expected = append(expected, label(pool[i]))
+		pool = append(pool[:i], pool[i+1:]...)
+	}
+	for i := range expected {
+		if l := execLabel(t, e); l != expected[i] {
+			t.Errorf("execution #%d should be %s, got %s", i+1, expected[i], l)
+		}
+	}
+	if !e.DoIfStable(func() {}) {
+		t.Error("should be stable")
+	}
+}
+
+func TestSetSchedulingPolicy(t *testing.T) {
+	e := newMixedPoolExecuter(t, LIFO())
+	if l := execLabel(t, e); l != "safety(1)" {
+		t.Error("first execution should be safety(1), got", l)
+	}
+	e.SetSchedulingPolicy(nil)
+	if l := execLabel(t, e); l != "cosmetic(1)" {
+		t.Error("second execution should be cosmetic(1), got", l)
+	}
+	e.SetSchedulingPolicy(RulePriority(map[string]int{"remote": 1}, 2))
+	if l := execLabel(t, e); l != "cosmetic(2)" {
+		t.Error("third execution should be cosmetic(2), got", l)
+	}
+}
+
+func TestSalience(t *testing.T) {
+	rules := []string{
+		"rule cosmetic on t1 for true do a = t1",
+		"rule safety salience 10 on t2 for true do b = t2",
+		"rule remote salience 5 on t3 for all true do ext.c = this.t3",
+	}
+	tests := []struct {
+		name     string
+		policy   SchedulingPolicy
+		expected []string
+	}{
+		//  {name, policy, expected},
+		{"nil", nil, []string{"safety(1)", "received(1)", "cosmetic(1)", "cosmetic(2)"}},
+		{"LIFO", LIFO(), []string{"safety(1)", "received(1)", "cosmetic(2)", "cosmetic(1)"}},
+		{"RulePriority", RulePriority(map[string]int{"cosmetic": 10}, 0),
+			[]string{"safety(1)", "received(1)", "cosmetic(1)", "cosmetic(2)"}},
+	}
+	for _, test := range tests {
+		t.Run("TestSalience#"+test.name, func(t *testing.T) {
+			e := newPoolExecuter(t, rules, test.policy)
+			_, pool := e.TakeState()
+			for _, u := range pool {
+				if u.Source == SourceRemote && u.Salience != 5 {
+					t.Error("received update should have salience 5, got", u.Salience)
+				}
+			}
+			for i, expected := range test.expected {
+				if l := execLabel(t, e); l != expected {
+					t.Errorf("execution #%d should be %s, got %s", i+1, expected, l)
+				}
+			}
+		})
+	}
+}
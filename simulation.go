@@ -0,0 +1,199 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/memory"
+)
+
+// Simulation is the outcome of a dry run performed by Simulate.
+type Simulation struct {
+	// State contains the values of the resources at the end of the simulation.
+	State memory.Resources
+	// Applied contains the updates applied during the simulation in execution order,
+	// starting with the one of the simulated input.
+	Applied []Update
+	// Violations contains the invariant violations reported during the simulation (see Violations).
+	Violations []InvariantViolation
+	// Errors contains the errors that occurred while evaluating rules, tasks and invariants.
+	Errors []error
+	// RemoteTasks contains the tasks that would be sent to the other nodes, in sending order.
+	RemoteTasks []ecarule.RemoteTask
+	// Pending contains the updates left in the pool when the simulation reached its depth.
+	Pending []Update
+}
+
+// Simulate performs a dry run of Input(actions) followed by at most depth executions of the local
+// updates triggered by it, as Exec would do. The simulation works on a copy of the resources, of the
+// rules and of the invariants of m: the Executer, its ResourceController and its Agent are not
+// affected, in particular no task is sent to the other nodes and the updates already in the pool
+// of m are not considered. The updates are chosen by their salience and then by the scheduling policy
+// of m, whose state (e.g. the one of a [Random] policy) is shared with the simulation. The evaluations
+// use the Clock of m.
+// The periodic and the delayed rules are never activated during the simulation.
+// If the input cannot be performed then its error is returned along with the partial Simulation.
+func (m *Executer) Simulate(actions string, depth int) (Simulation, error) {
+	agt := &simulationAgent{id: m.agentID()}
+	var res Simulation
+	sb, err := m.sandbox(agt, &res)
+	if err != nil {
+		return Simulation{}, err
+	}
+	update, err := sb.input(actions, nil)
+	if err == nil {
+		res.Applied = append(res.Applied, update)
+		for i := 0; i < depth; i++ {
+			update, executed := sb.exec()
+			if executed {
+				res.Applied = append(res.Applied, update)
+			}
+			sb.lockPool.Lock()
+			empty := len(sb.pool) == 0
+			sb.lockPool.Unlock()
+			if empty {
+				break
+			}
+		}
+	}
+	sb.stopSandbox()
+	res.collect(sb, agt)
+	return res, err
+}
+
+// sandbox returns an Executer having a copy of the resources (with their metadata), rules, invariants and input policy of m,
+// sharing its clock and its scheduling policy, that uses agt as its Agent and appends all its violations and errors to collector.
+// Only the goroutine receiving the updates of the sandbox is started, it should be terminated by means of stopSandbox.
+func (m *Executer) sandbox(agt Agent, collector *Simulation) (*Executer, error) {
+	m.lockRules.Lock()
+	rules := m.persistentRules()
+	m.lockRules.Unlock()
+	m.lockMemory.RLock()
+	// unlike Extract, Copy preserves the metadata of the resources
+	mem := m.memory.Copy().GetResources()
+	invariants := m.persistentInvariants()
+	policy := m.inputPolicy
+	m.lockMemory.RUnlock()
+	var scheduling SchedulingPolicy
+	m.lockPool.Lock()
+	if m.scheduling != nil {
+		scheduling = sharedPolicy{policy: m.scheduling, lock: &m.lockPool}
+	}
+	m.lockPool.Unlock()
+	sources := make([]string, 0, len(rules))
+	for _, r := range rules {
+		sources = append(sources, r.Source)
+	}
+	lc := config.LogConfig{Level: config.LogFatal}
+	cfg := &ExecuterConfig{
+		InputPolicy:   policy,
+		CascadeBudget: m.cascadeBudget,
+		Functions:     m.functions.registered(),
+		Clock:         m.clock,
+		Scheduling:    scheduling,
+	}
+	res, err := newExecuter(mem, sources, agt, lc, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		if !r.Enabled {
+			res.disabledRules.Insert(r.Name)
+		}
+	}
+	for _, inv := range invariants {
+		err = res.addInvariant(inv.Name, inv.Source)
+		if err == nil && inv.Repair != "" {
+			err = res.setInvariantRepair(inv.Name, inv.Repair)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	res.collector = collector
+	res.updateReceiver = res.startUpdateReceiver()
+	return res, nil
+}
+
+// sharedPolicy is a SchedulingPolicy shared by an Executer with its sandboxes, its choices are made
+// while holding the pool's lock of the Executer.
+type sharedPolicy struct {
+	policy SchedulingPolicy
+	lock   sync.Locker
+}
+
+// Choose returns the choice of the shared policy.
+func (p sharedPolicy) Choose(pool []Update) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.policy.Choose(pool)
+}
+
+// stopSandbox terminates the goroutine started by sandbox.
+func (m *Executer) stopSandbox() {
+	reply := make(chan bool)
+	m.quitUpdates <- reply
+	<-reply
+}
+
+// collect stores in s the final state of the sandbox sb, the remaining updates of its pool
+// and the tasks sent through agt. It should be called after stopSandbox.
+func (s *Simulation) collect(sb *Executer, agt *simulationAgent) {
+	sb.lockMemory.RLock()
+	s.State = sb.memory.Extract(sb.memory.ResourceNames())
+	sb.lockMemory.RUnlock()
+	sb.lockPool.Lock()
+	s.Pending = append([]Update(nil), sb.pool...)
+	sb.lockPool.Unlock()
+	for _, payload := range agt.payloads {
+		wire, err := unmarshalWireTasks(payload)
+		if err != nil {
+			s.Errors = append(s.Errors, err)
+			continue
+		}
+		s.RemoteTasks = append(s.RemoteTasks, wire.Tasks...)
+	}
+}
+
+// simulationAgent is the Agent used by Simulate: instead of sending the tasks, it records them.
+type simulationAgent struct {
+	id       string
+	payloads [][]byte
+}
+
+func (a *simulationAgent) Start() error {
+	return nil
+}
+
+func (a *simulationAgent) Join() error {
+	return nil
+}
+
+func (a *simulationAgent) ForAll(payload []byte) error {
+	a.payloads = append(a.payloads, payload)
+	return nil
+}
+
+func (a *simulationAgent) ReceivedActions() (<-chan chan []byte, <-chan chan string) {
+	return nil, nil
+}
+
+func (a *simulationAgent) Stop() error {
+	return errors.New("agent is not running")
+}
+
+func (a *simulationAgent) IsRunning() bool {
+	return false
+}
+
+func (a *simulationAgent) SetLogLevel(int) {}
+
+// ID returns the identifier of the Agent of the simulated Executer.
+func (a *simulationAgent) ID() string {
+	return a.id
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestSimulate(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["level"] = 0
+	memory.Integer["pump"] = 0
+	memory.Bool["alarm"] = false
+	rules := []string{
+		"rule fill on level for level > 5 do pump = level * 10",
+		"rule limit on pump for pump > 50 do alarm = true for all ext.alarm == false do ext.alarm = true",
+		"rule again on alarm for alarm do level = 0",
+	}
+	e, err := NewExecuter(memory, rules, MakeMockAgent(), config.TestsLogConfig, "pump < 100")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	sim, err := e.Simulate("level = 7", 10)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if sim.State.Integer["level"] != 0 || sim.State.Integer["pump"] != 70 || !sim.State.Bool["alarm"] {
+		t.Error("unexpected final state:", sim.State)
+	}
+	expected := []string{"", "fill", "limit", "again"}
+	if len(sim.Applied) != len(expected) {
+		t.Fatal("unexpected applied updates:", sim.Applied)
+	}
+	for i, u := range sim.Applied {
+		if u.Rule != expected[i] {
+			t.Errorf("update %d should come from rule %q, got %v", i, expected[i], u)
+		}
+	}
+	if sim.Applied[0].Source != SourceInput {
+		t.Error("the first update should be the input, got", sim.Applied[0])
+	}
+	if len(sim.RemoteTasks) != 1 || sim.RemoteTasks[0].Rule != "limit" {
+		t.Error("unexpected remote tasks:", sim.RemoteTasks)
+	}
+	if len(sim.Violations) != 0 || len(sim.Errors) != 0 || len(sim.Pending) != 0 {
+		t.Error("unexpected outcome:", sim.Violations, sim.Errors, sim.Pending)
+	}
+	// the executer is not affected
+	mem, pool := e.TakeState()
+	if mem.Integer["level"] != 0 || mem.Integer["pump"] != 0 || mem.Bool["alarm"] || len(pool) != 0 {
+		t.Error("the simulation modified the executer:", mem, pool)
+	}
+
+	sim, err = e.Simulate("level = 12", 1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(sim.Applied) != 1 || sim.State.Integer["pump"] != 0 || len(sim.Pending) != 0 {
+		t.Error("the update violating the invariant should be discarded, got", sim.Applied, sim.State, sim.Pending)
+	}
+	if len(sim.Violations) != 1 || sim.Violations[0].Update.Rule != "fill" {
+		t.Error("unexpected violations:", sim.Violations)
+	}
+
+	sim, err = e.Simulate("level = 6", 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(sim.Applied) != 1 || len(sim.Pending) != 1 || sim.Pending[0].Rule != "fill" {
+		t.Error("only the input should be simulated, got", sim.Applied, sim.Pending)
+	}
+	if _, err = e.Simulate("absent = 1", 1); err == nil {
+		t.Error("simulating invalid actions should fail")
+	}
+}
+
+func TestSimulateConfig(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Bool["start"] = false
+	memory.Integer["x"] = 0
+	memory.Time["stamp"] = time.Unix(0, 0)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e, err := NewExecuterAdvanced(memory, []string{"rule two on start for start do x = 1 for start do stamp = Now()"},
+		MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{Clock: NewManualClock(start), Scheduling: LIFO()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	sim, err := e.Simulate("start = true", 10)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// the sandbox uses the scheduling policy and the clock of the executer
+	if len(sim.Applied) != 3 || sim.Applied[1].Task != 1 || sim.Applied[2].Task != 0 {
+		t.Fatal("the updates should be applied in LIFO order, got", sim.Applied)
+	}
+	if !sim.State.Time["stamp"].Equal(start) {
+		t.Error("unexpected time:", sim.State.Time["stamp"])
+	}
+}
+
+func TestSimulateCollectsAll(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["n"] = 0
+	memory.Integer["m"] = 0
+	rules := []string{
+		"rule step on n for n < 100 do n = n + 1",
+		"rule bad on n for true do m = 0 - n",
+	}
+	e, err := NewExecuter(memory, rules, MakeMockAgent(), config.TestsLogConfig, "m >= 0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	sim, err := e.Simulate("n = 1", 1000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if sim.State.Integer["n"] != 100 || len(sim.Pending) != 0 {
+		t.Fatal("unexpected outcome:", sim.State, sim.Pending)
+	}
+	// the violations are more than DefaultSubscriptionBuffer
+	if len(sim.Violations) != 100 {
+		t.Fatal("there should be 100 violations, got", len(sim.Violations))
+	}
+	for i, v := range sim.Violations {
+		if v.Update.Assignments[0].Value.Interface() != int64(-(i+1)) || v.Dropped != 0 {
+			t.Errorf("unexpected violation #%d: %v", i, v)
+		}
+	}
+}
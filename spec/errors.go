@@ -0,0 +1,45 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is an error found while loading a node specification, Load returns an Error for each of them.
+type Error struct {
+	// File is the path of the file containing the error: the specification or a rule file.
+	File string
+	// Line is the line of the error, starting from 1, it is 0 when the position is unknown.
+	Line int
+	// Column is the column of the error, starting from 1, it is 0 when it is unknown.
+	Column int
+	// Message describes the error.
+	Message string
+	// Err is the error causing this one, if any, e.g. a [*parser.Error] for the invalid rules.
+	Err error
+}
+
+// Error returns a description of the error prefixed by its position, like "node.yaml:12:5: message".
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ":%d", e.Column)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// Unwrap returns the error causing e, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
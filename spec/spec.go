@@ -0,0 +1,691 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spec builds GoAbU nodes from declarative specification files.
+//
+// A specification is a YAML or TOML file describing the whole node:
+//
+//	log:
+//	  encoding: console
+//	  level: info
+//	agent:
+//	  id: greenhouse
+//	  port: 8100
+//	  peers: ["10.0.0.2:8100"]
+//	resources:
+//	  temperature: {type: Float, value: 20.5, min: -40, max: 85, unit: °C}
+//	  jobs: {type: List, value: [calibrate]}
+//	  label: Text
+//	devices:
+//	  fan: {type: Motor, pins: ["13", "15"]}
+//	  light: {type: DigitalPin, pins: ["11"]}
+//	rules:
+//	  - rule cool on temperature for temperature > 30.0 do fan = 200
+//	rule_files: [rules/lighting.abu]
+//	invariants:
+//	  plausible: temperature > -50.0 && temperature < 80.0
+//
+// The resources are declared by their type (one of "Bool", "Integer", "Float", "Text", "Time", "List" and "Map")
+// and optionally by their initial value, otherwise they start from the zero value of their type.
+// The tables declaring the resources can also specify their [memory.Metadata] by means of the keys
+// "read_only", "min", "max", "unit" and "description".
+// Time values are written in RFC 3339 format. The devices are created by means of the frames of
+// [iodelegates.MakeIOresources], their pins are passed to the frame constructors.
+// The rule files contain GoAbU rules and their paths are relative to the directory of the specification.
+// The log level is one of "debug", "info", "warning", "error" and "fatal" or the corresponding integer.
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abu-lang/goabu"
+	"github.com/abu-lang/goabu/communication"
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/parser"
+	"github.com/abu-lang/goabu/physical"
+	"github.com/abu-lang/goabu/physical/iodelegates"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Options customizes the nodes built by Load.
+type Options struct {
+	// Adaptor is the adaptor of the devices, it is required if the specification declares any device.
+	Adaptor physical.IOadaptor
+	// IOresources creates the IOresources containing the devices, if nil [iodelegates.MakeIOresources] is used.
+	IOresources func(physical.IOadaptor) *physical.IOresources
+	// Agent, if not nil, is used in place of the MemberlistAgent described by the specification.
+	Agent goabu.Agent
+	// Config contains the settings of the Executer that cannot be specified in the file.
+	Config *goabu.ExecuterConfig
+}
+
+// Node is a node built by Load.
+type Node struct {
+	// Resources is the ResourceController of the node: an [*physical.IOresources] if the specification
+	// declares any device, otherwise a [memory.Resources].
+	Resources memory.ResourceController
+	Agent     goabu.Agent
+	Executer  *goabu.Executer
+}
+
+// Load builds the node described by the specification file at path, whose format is determined by its
+// extension: ".yaml" or ".yml" for YAML and ".toml" for TOML. If opts is nil then the default options are used.
+//
+// If the specification is not valid the returned error joins an [*Error] for each of the problems found,
+// along with their positions. Otherwise the Executer of the returned Node has already started its Agent
+// and its ResourceController, as NewExecuterAdvanced does.
+func Load(path string, opts *Options) (*Node, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var root *node
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		root, err = parseYAML(path, content)
+	case ".toml":
+		root, err = parseTOML(path, content)
+	default:
+		return nil, fmt.Errorf("unknown format of %s: the extension must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &decoder{file: path}
+	s := d.decode(root)
+	if len(d.errs) > 0 {
+		return nil, errors.Join(d.errs...)
+	}
+	return d.build(s, opts)
+}
+
+// nodeSpec is a decoded specification.
+type nodeSpec struct {
+	log          config.LogConfig
+	agent        *node
+	id           string
+	port         int
+	peers        []string
+	resources    memory.Resources
+	resourceDefs map[string]*node
+	devices      []deviceSpec
+	rules        []string
+	// sources contains where each of the rules has been found.
+	sources    []ruleSource
+	invariants []invariantSpec
+}
+
+type deviceSpec struct {
+	name string
+	typ  string
+	pins []any
+	def  *node
+}
+
+// ruleSource is the position of a rule: either a rule file or a string in the specification.
+type ruleSource struct {
+	file string
+	def  *node
+}
+
+type invariantSpec struct {
+	name       string
+	expression string
+	def        *node
+}
+
+// decoder decodes a specification collecting the errors found.
+type decoder struct {
+	file string
+	errs []error
+}
+
+func (d *decoder) errorf(n *node, format string, args ...any) {
+	d.errs = append(d.errs, &Error{File: d.file, Line: n.line, Column: n.column, Message: fmt.Sprintf(format, args...)})
+}
+
+// table checks that n is a table whose keys are among the given ones.
+func (d *decoder) table(n *node, what string, keys ...string) bool {
+	if n.kind != tableNode {
+		d.errorf(n, "%s must be a table, got a %v", what, n.kind)
+		return false
+	}
+	for _, e := range n.table {
+		if keys != nil && !contains(keys, e.key) {
+			d.errorf(e.value, "unknown key %s in %s, expected one of: %s", e.key, what, strings.Join(keys, ", "))
+		}
+	}
+	return true
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *decoder) str(n *node, what string) (string, bool) {
+	s, ok := n.value.(string)
+	if n.kind != scalarNode || !ok {
+		d.errorf(n, "%s must be a string", what)
+	}
+	return s, ok
+}
+
+func (d *decoder) integer(n *node, what string) (int64, bool) {
+	v, ok := scalar(n).(int64)
+	if !ok {
+		d.errorf(n, "%s must be an integer", what)
+	}
+	return v, ok
+}
+
+func (d *decoder) strings(n *node, what string) []string {
+	if n.kind != listNode {
+		d.errorf(n, "%s must be a list of strings", what)
+		return nil
+	}
+	var res []string
+	for _, e := range n.list {
+		if s, ok := d.str(e, what); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// scalar returns the value of n converted to the types of the values of the resources, it returns nil
+// if n is not a scalar or it has an unsupported type.
+func scalar(n *node) any {
+	if n.kind != scalarNode {
+		return nil
+	}
+	switch v := n.value.(type) {
+	case bool, int64, float64, string, time.Time:
+		return v
+	case int:
+		return int64(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decode(root *node) *nodeSpec {
+	res := &nodeSpec{
+		log:          config.LogConfig{Level: config.LogInfo},
+		resources:    memory.MakeResources(),
+		resourceDefs: make(map[string]*node),
+	}
+	if !d.table(root, "the specification", "log", "agent", "resources", "devices", "rules", "rule_files", "invariants") {
+		return res
+	}
+	for _, e := range root.table {
+		switch e.key {
+		case "log":
+			d.decodeLog(e.value, res)
+		case "agent":
+			d.decodeAgent(e.value, res)
+		case "resources":
+			d.decodeResources(e.value, res)
+		case "devices":
+			d.decodeDevices(e.value, res)
+		case "rules":
+			if e.value.kind != listNode {
+				d.errorf(e.value, "rules must be a list of strings")
+				break
+			}
+			for _, r := range e.value.list {
+				if s, ok := d.str(r, "a rule"); ok {
+					res.rules = append(res.rules, s)
+					res.sources = append(res.sources, ruleSource{def: r})
+				}
+			}
+		case "rule_files":
+			d.decodeRuleFiles(e.value, res)
+		case "invariants":
+			if !d.table(e.value, "invariants") {
+				break
+			}
+			for _, inv := range e.value.table {
+				if s, ok := d.str(inv.value, "an invariant"); ok {
+					res.invariants = append(res.invariants, invariantSpec{name: inv.key, expression: s, def: inv.value})
+				}
+			}
+		}
+	}
+	if len(res.resourceDefs) == 0 && len(res.devices) == 0 {
+		d.errorf(root, "the specification declares no resources")
+	}
+	return res
+}
+
+var logLevels = map[string]int{
+	"debug":   config.LogDebug,
+	"info":    config.LogInfo,
+	"warning": config.LogWarning,
+	"error":   config.LogError,
+	"fatal":   config.LogFatal,
+}
+
+func (d *decoder) decodeLog(n *node, s *nodeSpec) {
+	if !d.table(n, "log", "encoding", "level") {
+		return
+	}
+	if e := n.get("encoding"); e != nil {
+		if enc, ok := d.str(e, "the log encoding"); ok {
+			if enc != "" && enc != "console" && enc != "json" {
+				d.errorf(e, `unknown log encoding %s, expected "console" or "json"`, enc)
+			}
+			s.log.Encoding = enc
+		}
+	}
+	if l := n.get("level"); l != nil {
+		switch v := scalar(l).(type) {
+		case string:
+			level, present := logLevels[strings.ToLower(v)]
+			if !present {
+				d.errorf(l, "unknown log level %s, expected one of: debug, info, warning, error, fatal", v)
+			}
+			s.log.Level = level
+		case int64:
+			if v < config.LogDebug || v > config.LogFatal {
+				d.errorf(l, "log level %d out of range", v)
+			}
+			s.log.Level = int(v)
+		default:
+			d.errorf(l, "the log level must be a string or an integer")
+		}
+	}
+}
+
+func (d *decoder) decodeAgent(n *node, s *nodeSpec) {
+	s.agent = n
+	if !d.table(n, "agent", "id", "port", "peers") {
+		return
+	}
+	if id := n.get("id"); id != nil {
+		s.id, _ = d.str(id, "the agent id")
+	}
+	if p := n.get("port"); p != nil {
+		if port, ok := d.integer(p, "the agent port"); ok {
+			if port < 0 || port > math.MaxUint16 {
+				d.errorf(p, "port %d out of range", port)
+			}
+			s.port = int(port)
+		}
+	} else {
+		d.errorf(n, "the agent port is missing")
+	}
+	if p := n.get("peers"); p != nil {
+		s.peers = d.strings(p, "the peers")
+	}
+}
+
+func (d *decoder) decodeResources(n *node, s *nodeSpec) {
+	if !d.table(n, "resources") {
+		return
+	}
+	for _, e := range n.table {
+		if !parser.ValidateIdentifiers(e.key)[0] {
+			d.errorf(e.value, "invalid resource name: %s", e.key)
+			continue
+		}
+		var typ string
+		value := (*node)(nil)
+		switch e.value.kind {
+		case scalarNode:
+			typ, _ = d.str(e.value, "the type of a resource")
+		case tableNode:
+			if !d.table(e.value, "resource "+e.key, "type", "value", "read_only", "min", "max", "unit", "description") {
+				continue
+			}
+			t := e.value.get("type")
+			if t == nil {
+				d.errorf(e.value, "the type of resource %s is missing", e.key)
+				continue
+			}
+			typ, _ = d.str(t, "the type of a resource")
+			value = e.value.get("value")
+		default:
+			d.errorf(e.value, "resource %s must be declared by its type or by a table", e.key)
+			continue
+		}
+		if typ == "" {
+			continue
+		}
+		s.resourceDefs[e.key] = e.value
+		d.resourceValue(e.key, typ, e.value, value, s.resources)
+		if e.value.kind == tableNode {
+			d.resourceMetadata(e.key, typ, e.value, s.resources)
+		}
+	}
+}
+
+// resourceMetadata sets the metadata of the resource name of type typ declared by the table def.
+func (d *decoder) resourceMetadata(name, typ string, def *node, r memory.Resources) {
+	var meta memory.Metadata
+	if n := def.get("read_only"); n != nil {
+		var ok bool
+		meta.ReadOnly, ok = scalar(n).(bool)
+		if !ok {
+			d.errorf(n, "read_only must be a boolean")
+		}
+	}
+	bound := func(key string) *float64 {
+		n := def.get(key)
+		if n == nil {
+			return nil
+		}
+		if typ != "Integer" && typ != "Float" {
+			d.errorf(n, "the %s resource %s cannot have a range", typ, name)
+			return nil
+		}
+		switch v := scalar(n).(type) {
+		case int64:
+			return memory.Bound(float64(v))
+		case float64:
+			return memory.Bound(v)
+		}
+		d.errorf(n, "%s must be a number", key)
+		return nil
+	}
+	meta.Min, meta.Max = bound("min"), bound("max")
+	if meta.Min != nil && meta.Max != nil && *meta.Min > *meta.Max {
+		d.errorf(def, "invalid range %s of resource %s", meta.Range(), name)
+	} else if v, ok := numeric(r, name); ok && !meta.InRange(v) {
+		d.errorf(def, "the value of resource %s is out of its range %s", name, meta.Range())
+	}
+	if n := def.get("unit"); n != nil {
+		meta.Unit, _ = d.str(n, "the unit of a resource")
+	}
+	if n := def.get("description"); n != nil {
+		meta.Description, _ = d.str(n, "the description of a resource")
+	}
+	if meta != (memory.Metadata{}) {
+		r.Meta[name] = meta
+	}
+}
+
+// numeric returns the value of the Integer or Float resource name in r.
+func numeric(r memory.Resources, name string) (float64, bool) {
+	if v, present := r.Integer[name]; present {
+		return float64(v), true
+	}
+	v, present := r.Float[name]
+	return v, present
+}
+
+// resourceValue sets the resource name of type typ, declared by def, to value or to the zero value.
+func (d *decoder) resourceValue(name, typ string, def, value *node, r memory.Resources) {
+	invalid := func() {
+		d.errorf(value, "invalid value for the %s resource %s", typ, name)
+	}
+	var v any
+	if value != nil {
+		v = scalar(value)
+	}
+	switch typ {
+	case "Bool":
+		b, ok := v.(bool)
+		if value != nil && !ok {
+			invalid()
+		}
+		r.Bool[name] = b
+	case "Integer":
+		i, ok := v.(int64)
+		if value != nil && !ok {
+			invalid()
+		}
+		r.Integer[name] = i
+	case "Float":
+		f, ok := v.(float64)
+		if i, isInt := v.(int64); isInt {
+			f, ok = float64(i), true
+		}
+		if value != nil && !ok {
+			invalid()
+		}
+		r.Float[name] = f
+	case "Text":
+		t, ok := v.(string)
+		if value != nil && !ok {
+			invalid()
+		}
+		r.Text[name] = t
+	case "Time":
+		t, ok := v.(time.Time)
+		if s, isString := v.(string); isString {
+			var err error
+			t, err = time.Parse(time.RFC3339, s)
+			ok = err == nil
+		}
+		if value != nil && !ok {
+			d.errorf(value, "invalid value for the Time resource %s: the times must be written in RFC 3339 format", name)
+		}
+		r.Time[name] = t
+	case "List":
+		list := make([]interface{}, 0)
+		if value != nil && value.kind != listNode {
+			invalid()
+		} else if value != nil {
+			for _, e := range value.list {
+				if v := scalar(e); v != nil {
+					list = append(list, v)
+				} else {
+					d.errorf(e, "invalid element of the List resource %s", name)
+				}
+			}
+		}
+		r.List[name] = list
+	case "Map":
+		m := make(map[string]interface{})
+		if value != nil && value.kind != tableNode {
+			invalid()
+		} else if value != nil {
+			for _, e := range value.table {
+				if v := scalar(e.value); v != nil {
+					m[e.key] = v
+				} else {
+					d.errorf(e.value, "invalid value of the Map resource %s", name)
+				}
+			}
+		}
+		r.Map[name] = m
+	default:
+		d.errorf(def, "unknown type %s of resource %s, expected one of: Bool, Integer, Float, Text, Time, List, Map", typ, name)
+	}
+}
+
+func (d *decoder) decodeDevices(n *node, s *nodeSpec) {
+	if !d.table(n, "devices") {
+		return
+	}
+	for _, e := range n.table {
+		if !d.table(e.value, "device "+e.key, "type", "pins") {
+			continue
+		}
+		if !parser.ValidateIdentifiers(e.key)[0] {
+			d.errorf(e.value, "invalid device name: %s", e.key)
+			continue
+		}
+		dev := deviceSpec{name: e.key, def: e.value}
+		t := e.value.get("type")
+		if t == nil {
+			d.errorf(e.value, "the type of device %s is missing", e.key)
+			continue
+		}
+		dev.typ, _ = d.str(t, "the type of a device")
+		if p := e.value.get("pins"); p != nil {
+			if p.kind != listNode {
+				d.errorf(p, "the pins must be a list")
+				continue
+			}
+			for _, pin := range p.list {
+				switch v := scalar(pin).(type) {
+				case string:
+					dev.pins = append(dev.pins, v)
+				case int64:
+					dev.pins = append(dev.pins, fmt.Sprint(v))
+				default:
+					d.errorf(pin, "the pins must be strings or integers")
+				}
+			}
+		}
+		s.devices = append(s.devices, dev)
+	}
+}
+
+func (d *decoder) decodeRuleFiles(n *node, s *nodeSpec) {
+	if n.kind != listNode {
+		d.errorf(n, "rule_files must be a list of paths")
+		return
+	}
+	for _, f := range n.list {
+		path, ok := d.str(f, "a rule file")
+		if !ok {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(d.file), path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			d.errs = append(d.errs, &Error{File: d.file, Line: f.line, Column: f.column, Message: err.Error(), Err: err})
+			continue
+		}
+		s.rules = append(s.rules, string(content))
+		s.sources = append(s.sources, ruleSource{file: path, def: f})
+	}
+}
+
+// build creates the node described by s.
+func (d *decoder) build(s *nodeSpec, opts *Options) (*Node, error) {
+	res := &Node{Resources: s.resources}
+	if len(s.devices) > 0 {
+		if opts.Adaptor == nil {
+			d.errorf(s.devices[0].def, "the devices require an adaptor (see Options)")
+			return nil, errors.Join(d.errs...)
+		}
+		makeIO := opts.IOresources
+		if makeIO == nil {
+			makeIO = iodelegates.MakeIOresources
+		}
+		io := makeIO(opts.Adaptor)
+		for _, dev := range s.devices {
+			err := io.Add(dev.typ, dev.name, dev.pins...)
+			if err != nil {
+				d.errs = append(d.errs, &Error{File: d.file, Line: dev.def.line, Column: dev.def.column,
+					Message: fmt.Sprintf("cannot create device %s: %v", dev.name, err), Err: err})
+			}
+		}
+		for _, name := range s.resources.ResourceNames() {
+			if io.Has(name) {
+				d.errorf(s.resourceDefs[name], "resource %s is already managed by a device", name)
+			}
+		}
+		io.Enclose(s.resources)
+		res.Resources = io
+	}
+	d.checkInvariants(s, res.Resources.Types())
+	if len(d.errs) > 0 {
+		return nil, errors.Join(d.errs...)
+	}
+	res.Agent = opts.Agent
+	if res.Agent == nil {
+		if s.agent == nil {
+			return nil, &Error{File: d.file, Message: "the agent settings are missing"}
+		}
+		res.Agent = communication.NewMemberlistAgent(s.id, s.port, s.log, s.peers...)
+	}
+	var err error
+	res.Executer, err = goabu.NewExecuterAdvanced(res.Resources, s.rules, res.Agent, s.log, opts.Config)
+	if err != nil {
+		return nil, d.ruleErrors(err, s)
+	}
+	for _, inv := range s.invariants {
+		err = res.Executer.AddInvariant(inv.name, inv.expression)
+		if err != nil {
+			d.errs = append(d.errs, &Error{File: d.file, Line: inv.def.line, Column: inv.def.column,
+				Message: fmt.Sprintf("invalid invariant %s: %v", inv.name, err), Err: err})
+		}
+	}
+	if len(d.errs) > 0 {
+		res.Executer.Close()
+		return nil, errors.Join(d.errs...)
+	}
+	return res, nil
+}
+
+// checkInvariants parses the invariants before building the Executer, so that it is not started
+// when they are invalid.
+func (d *decoder) checkInvariants(s *nodeSpec, types map[string]string) {
+	if len(s.invariants) == 0 {
+		return
+	}
+	expressions := make([]string, 0, len(s.invariants))
+	for _, inv := range s.invariants {
+		expressions = append(expressions, inv.expression)
+	}
+	_, errs := parser.New(types, ast.NewWorkingMemory("spec", "0.0.0")).ParseExpressions(expressions...)
+	for _, err := range errs {
+		var perr *parser.Error
+		if !errors.As(err, &perr) || perr.Rule < 0 || perr.Rule >= len(s.invariants) {
+			d.errs = append(d.errs, &Error{File: d.file, Message: err.Error(), Err: err})
+			continue
+		}
+		inv := s.invariants[perr.Rule]
+		located := *perr
+		located.Rule = -1
+		d.errs = append(d.errs, &Error{File: d.file, Line: inv.def.line, Column: inv.def.column,
+			Message: fmt.Sprintf("invalid invariant %s: %v", inv.name, &located), Err: err})
+	}
+}
+
+// ruleErrors returns the errors of the rules contained in err, located in the specification or in the rule files.
+func (d *decoder) ruleErrors(err error, s *nodeSpec) error {
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+	for _, e := range errs {
+		var perr *parser.Error
+		if !errors.As(e, &perr) || perr.Rule < 0 || perr.Rule >= len(s.sources) {
+			d.errs = append(d.errs, &Error{File: d.file, Message: e.Error(), Err: e})
+			continue
+		}
+		source := s.sources[perr.Rule]
+		located := *perr
+		located.Rule = -1
+		if source.file == "" {
+			d.errs = append(d.errs, &Error{File: d.file, Line: source.def.line, Column: source.def.column,
+				Message: fmt.Sprintf("invalid rule: %v", &located), Err: e})
+			continue
+		}
+		located.Line = 0
+		res := &Error{File: source.file, Message: located.Error(), Err: e}
+		if perr.Line > 0 {
+			res.Line, res.Column = perr.Line, perr.Column+1
+		}
+		d.errs = append(d.errs, res)
+	}
+	return errors.Join(d.errs...)
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package spec_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu"
+	"github.com/abu-lang/goabu/parser"
+	"github.com/abu-lang/goabu/physical"
+	"github.com/abu-lang/goabu/spec"
+)
+
+const yamlSpec = `log:
+  encoding: console
+  level: fatal
+agent:
+  id: node
+  port: 0
+resources:
+  temperature: {type: Float, value: 20, min: -50, max: 80, unit: °C}
+  alarm: Bool
+  since:
+    type: Time
+    value: 2026-01-02T15:04:05Z
+  jobs: {type: List, value: [calibrate, 3]}
+  limits: {type: Map, value: {max: 30}}
+rules:
+  - rule hot on temperature for temperature > limits["max"] do alarm = true
+rule_files: [rules/jobs.abu]
+invariants:
+  plausible: temperature > -50
+`
+
+const tomlSpec = `rules = ["rule hot on temperature for temperature > limits[\"max\"] do alarm = true"]
+rule_files = ["rules/jobs.abu"]
+
+[log]
+encoding = "console"
+level = "fatal"
+
+[agent]
+id = "node"
+port = 0
+
+[resources]
+temperature = {type = "Float", value = 20, min = -50, max = 80, unit = "°C"}
+alarm = "Bool"
+since = {type = "Time", value = 2026-01-02T15:04:05Z}
+jobs = {type = "List", value = ["calibrate", 3]}
+limits = {type = "Map", value = {max = 30}}
+
+[invariants]
+plausible = "temperature > -50"
+`
+
+const jobsRule = `rule enqueue on alarm for alarm do jobs = Append(jobs, "cool")
+`
+
+// write writes the given files in a temporary directory and returns its path.
+func write(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	for _, file := range []string{"node.yaml", "node.toml"} {
+		t.Run(file, func(t *testing.T) {
+			content := yamlSpec
+			if strings.HasSuffix(file, ".toml") {
+				content = tomlSpec
+			}
+			dir := write(t, map[string]string{file: content, "rules/jobs.abu": jobsRule})
+			n, err := spec.Load(filepath.Join(dir, file), &spec.Options{Agent: goabu.MakeMockAgent()})
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			e := n.Executer
+			defer e.Close()
+			mem, _ := e.TakeState()
+			since := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+			if mem.Float["temperature"] != 20 || mem.Bool["alarm"] || !mem.Time["since"].Equal(since) ||
+				!reflect.DeepEqual(mem.List["jobs"], []any{"calibrate", int64(3)}) ||
+				!reflect.DeepEqual(mem.Map["limits"], map[string]any{"max": int64(30)}) {
+				t.Fatal("unexpected initial state:", mem)
+			}
+			if meta := mem.Metadata("temperature"); meta.Unit != "°C" || meta.Range() != "[-50, 80]" {
+				t.Error("unexpected metadata:", meta)
+			}
+			err = e.Input("temperature = 35.0")
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for _, pool := e.TakeState(); len(pool) > 0; _, pool = e.TakeState() {
+				e.Exec()
+			}
+			mem, _ = e.TakeState()
+			if !mem.Bool["alarm"] || !reflect.DeepEqual(mem.List["jobs"], []any{"calibrate", int64(3), "cool"}) {
+				t.Error("unexpected state:", mem)
+			}
+			err = e.Input("temperature = -60.0")
+			if err == nil {
+				t.Error("the invariant should have been violated")
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := write(t, map[string]string{
+		"node.yaml": `agent: {id: node, port: 0}
+resources:
+  temperature: Float
+  fan: {type: Motor}
+  2bad: Bool
+  flag: {type: Bool, max: 1}
+colour: red
+`,
+		"invariants.yaml": `log: {level: fatal}
+resources:
+  temperature: Float
+invariants:
+  plausible: temperatur > 0
+`,
+		"rules.yaml": `log: {level: fatal}
+resources:
+  temperature: Float
+rules:
+  - rule r on temperature for temperature > 0 do temperature = "hot"
+rule_files: [bad.abu]
+`,
+		"bad.abu":     "rule r2 on temperature\nfor temperature > 0\ndo temperature = \"hot\"\n",
+		"syntax.toml": "[resources]\ntemperature = \n",
+	})
+	tests := []struct {
+		file string
+		// positions contains the expected positions of the errors, as prefixes of their descriptions
+		positions []string
+	}{
+		{"node.yaml", []string{"node.yaml:4:8: unknown type Motor", "node.yaml:5:9: invalid resource name", "node.yaml:6:27: the Bool resource flag cannot have a range", "node.yaml:7:9: unknown key colour"}},
+		{"invariants.yaml", []string{"invariants.yaml:5:14: invalid invariant plausible"}},
+		{"rules.yaml", []string{"rules.yaml:5:5: invalid rule", "bad.abu:3:"}},
+		{"syntax.toml", []string{"syntax.toml:3:1:"}},
+	}
+	for _, test := range tests {
+		_, err := spec.Load(filepath.Join(dir, test.file), &spec.Options{Agent: goabu.MakeMockAgent()})
+		if err == nil {
+			t.Errorf("%s: expected errors", test.file)
+			continue
+		}
+		for _, position := range test.positions {
+			found := false
+			for _, e := range strings.Split(err.Error(), "\n") {
+				found = found || strings.HasPrefix(strings.TrimPrefix(e, dir+string(filepath.Separator)), position)
+			}
+			if !found {
+				t.Errorf("%s: no error at %s in:\n%v", test.file, position, err)
+			}
+		}
+	}
+	_, err := spec.Load(filepath.Join(dir, "rules.yaml"), &spec.Options{Agent: goabu.MakeMockAgent()})
+	var serr *spec.Error
+	var perr *parser.Error
+	if !errors.As(err, &serr) || !errors.As(err, &perr) {
+		t.Error("the errors should be *spec.Error wrapping a *parser.Error:", err)
+	}
+}
+
+// mockAdaptor is a physical.IOadaptor recording the values written on its pins.
+type mockAdaptor struct {
+	lock   sync.Mutex
+	name   string
+	values map[string]byte
+}
+
+func (a *mockAdaptor) Name() string        { return a.name }
+func (a *mockAdaptor) SetName(name string) { a.name = name }
+func (a *mockAdaptor) Connect() error      { return nil }
+func (a *mockAdaptor) Finalize() error     { return nil }
+
+func (a *mockAdaptor) DigitalRead(pin string) (int, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return int(a.values[pin]), nil
+}
+
+func (a *mockAdaptor) DigitalWrite(pin string, val byte) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.values[pin] = val
+	return nil
+}
+
+func (a *mockAdaptor) PwmWrite(pin string, val byte) error {
+	return a.DigitalWrite(pin, val)
+}
+
+func TestLoadDevices(t *testing.T) {
+	dir := write(t, map[string]string{"node.toml": `rules = ["rule on_ on request for request do light = true"]
+
+[log]
+level = "fatal"
+
+[resources]
+request = "Bool"
+
+[devices.light]
+type = "DigitalPin"
+pins = [11]
+`})
+	_, err := spec.Load(filepath.Join(dir, "node.toml"), &spec.Options{Agent: goabu.MakeMockAgent()})
+	if err == nil || !strings.Contains(err.Error(), "adaptor") {
+		t.Error("expected an error about the missing adaptor, got:", err)
+	}
+	adaptor := &mockAdaptor{values: make(map[string]byte)}
+	n, err := spec.Load(filepath.Join(dir, "node.toml"), &spec.Options{Adaptor: adaptor, Agent: goabu.MakeMockAgent()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer n.Executer.Close()
+	if _, ok := n.Resources.(*physical.IOresources); !ok {
+		t.Fatalf("unexpected ResourceController %T", n.Resources)
+	}
+	err = n.Executer.Input("request = true")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, pool := n.Executer.TakeState(); len(pool) > 0; _, pool = n.Executer.TakeState() {
+		n.Executer.Exec()
+	}
+	if v, _ := adaptor.DigitalRead("11"); v != 1 {
+		t.Error("the light should be on")
+	}
+}
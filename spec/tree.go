@@ -0,0 +1,199 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeKind is the kind of a node of a specification.
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	listNode
+	tableNode
+)
+
+func (k nodeKind) String() string {
+	switch k {
+	case listNode:
+		return "list"
+	case tableNode:
+		return "table"
+	default:
+		return "value"
+	}
+}
+
+// node is a value of a specification along with its position, it abstracts over the YAML and TOML formats.
+type node struct {
+	kind   nodeKind
+	line   int
+	column int
+	// value is the value of a scalarNode.
+	value any
+	// list contains the elements of a listNode.
+	list []*node
+	// table contains the entries of a tableNode in the order in which they appear in the file.
+	table []entry
+}
+
+// entry is an entry of a tableNode.
+type entry struct {
+	key   string
+	value *node
+}
+
+// get returns the value of the entry of n with the given key, or nil.
+func (n *node) get(key string) *node {
+	for _, e := range n.table {
+		if e.key == key {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// parseYAML parses a YAML document.
+func parseYAML(file string, content []byte) (*node, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, yamlError(file, err)
+	}
+	if doc.Kind == 0 {
+		return &node{kind: tableNode, line: 1, column: 1}, nil
+	}
+	return fromYAML(file, &doc)
+}
+
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlError returns the *Error corresponding to the error returned by the YAML parser.
+func yamlError(file string, err error) error {
+	res := &Error{File: file, Message: err.Error(), Err: err}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		res.Message = typeErr.Errors[0]
+	}
+	if m := yamlLine.FindStringSubmatch(res.Message); m != nil {
+		res.Line, _ = strconv.Atoi(m[1])
+		res.Message = m[2]
+	}
+	return res
+}
+
+func fromYAML(file string, n *yaml.Node) (*node, error) {
+	res := &node{line: n.Line, column: n.Column}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		return fromYAML(file, n.Content[0])
+	case yaml.AliasNode:
+		return fromYAML(file, n.Alias)
+	case yaml.ScalarNode:
+		res.kind = scalarNode
+		err := n.Decode(&res.value)
+		if err != nil {
+			return nil, &Error{File: file, Line: n.Line, Column: n.Column, Message: err.Error(), Err: err}
+		}
+	case yaml.SequenceNode:
+		res.kind = listNode
+		for _, c := range n.Content {
+			e, err := fromYAML(file, c)
+			if err != nil {
+				return nil, err
+			}
+			res.list = append(res.list, e)
+		}
+	case yaml.MappingNode:
+		res.kind = tableNode
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Kind != yaml.ScalarNode {
+				return nil, &Error{File: file, Line: k.Line, Column: k.Column, Message: "the keys must be strings"}
+			}
+			if res.get(k.Value) != nil {
+				return nil, &Error{File: file, Line: k.Line, Column: k.Column, Message: fmt.Sprintf("duplicate key %s", k.Value)}
+			}
+			e, err := fromYAML(file, v)
+			if err != nil {
+				return nil, err
+			}
+			res.table = append(res.table, entry{key: k.Value, value: e})
+		}
+	}
+	return res, nil
+}
+
+// parseTOML parses a TOML document.
+func parseTOML(file string, content []byte) (*node, error) {
+	tree, err := toml.LoadBytes(content)
+	if err != nil {
+		return nil, tomlError(file, err)
+	}
+	return fromTOML(tree, toml.Position{Line: 1, Col: 1}), nil
+}
+
+var tomlPosition = regexp.MustCompile(`^\((\d+), (\d+)\): (.*)$`)
+
+// tomlError returns the *Error corresponding to the error returned by the TOML parser.
+func tomlError(file string, err error) error {
+	res := &Error{File: file, Message: err.Error(), Err: err}
+	if m := tomlPosition.FindStringSubmatch(res.Message); m != nil {
+		res.Line, _ = strconv.Atoi(m[1])
+		res.Column, _ = strconv.Atoi(m[2])
+		res.Message = m[3]
+	}
+	return res
+}
+
+// fromTOML converts the value v, found at pos, of a TOML document.
+func fromTOML(v any, pos toml.Position) *node {
+	res := &node{line: pos.Line, column: pos.Col}
+	switch v := v.(type) {
+	case *toml.Tree:
+		res.kind = tableNode
+		if p := v.Position(); !p.Invalid() {
+			res.line, res.column = p.Line, p.Col
+		}
+		for _, k := range v.Keys() {
+			p := v.GetPositionPath([]string{k})
+			if p.Invalid() {
+				// the entries of inline tables have no position
+				p = toml.Position{Line: res.line, Col: res.column}
+			}
+			res.table = append(res.table, entry{key: k, value: fromTOML(v.GetPath([]string{k}), p)})
+		}
+		// the TOML parser does not preserve the order of the keys
+		sort.Slice(res.table, func(i, j int) bool {
+			a, b := res.table[i].value, res.table[j].value
+			if a.line != b.line || a.column != b.column {
+				return a.line < b.line || a.line == b.line && a.column < b.column
+			}
+			return res.table[i].key < res.table[j].key
+		})
+	case []*toml.Tree:
+		res.kind = listNode
+		for _, t := range v {
+			res.list = append(res.list, fromTOML(t, pos))
+		}
+	case []any:
+		res.kind = listNode
+		for _, e := range v {
+			res.list = append(res.list, fromTOML(e, pos))
+		}
+	default:
+		res.kind = scalarNode
+		res.value = v
+	}
+	return res
+}
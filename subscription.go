@@ -0,0 +1,169 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/stringset"
+)
+
+// DefaultSubscriptionBuffer is the capacity of the channels returned by Subscribe
+// when [ExecuterConfig] does not specify one.
+const DefaultSubscriptionBuffer = 64
+
+// ChangeSource specifies the origin of a [ResourceChange].
+type ChangeSource int
+
+const (
+	// SourceInput marks the changes performed by Input (e.g. the inputs from the environment).
+	SourceInput ChangeSource = iota
+	// SourceLocal marks the changes performed by updates produced by the rules of the node.
+	SourceLocal
+	// SourceRemote marks the changes performed by updates received from other nodes.
+	SourceRemote
+)
+
+// String returns the name of the source.
+func (s ChangeSource) String() string {
+	switch s {
+	case SourceInput:
+		return "input"
+	case SourceLocal:
+		return "local"
+	case SourceRemote:
+		return "remote"
+	default:
+		return fmt.Sprintf("ChangeSource(%d)", int(s))
+	}
+}
+
+// ResourceChange is the event sent to subscribers when the value of a resource changes.
+type ResourceChange struct {
+	// Resource is the name of the modified resource.
+	Resource string
+	// Old is the value of the resource before the change.
+	Old any
+	// New is the value of the resource after the change.
+	New any
+	// Source specifies where the change originated.
+	Source ChangeSource
+	// Rule is the name of the rule that produced the change, it is empty if Source is SourceInput.
+	Rule string
+	// Dropped is the number of older events that were discarded to make room for this one
+	// because the channel of the subscriber was full.
+	Dropped int
+}
+
+// copy returns a copy of c that does not share its values with c.
+func (c ResourceChange) copy() ResourceChange {
+	c.Old = memory.DeepCopy(c.Old)
+	c.New = memory.DeepCopy(c.New)
+	return c
+}
+
+// subscription holds the state of a channel returned by Subscribe.
+type subscription struct {
+	ch chan ResourceChange
+	// resources contains the resources of interest, if it is nil then every resource is of interest.
+	resources stringset.Set
+}
+
+// Subscribe returns a channel over which the changes of the specified resources are sent as they
+// are applied by the Executer. If no resource is specified then the changes of every resource are sent.
+//
+// The channel has a bounded buffer (see [ExecuterConfig]): the Executer never blocks on a slow
+// subscriber, when the buffer is full the oldest event in the buffer is discarded in favor of the
+// new one and the number of discarded events is reported by the Dropped field of the latter.
+// The channel is closed by Unsubscribe and by Close.
+func (m *Executer) Subscribe(resources ...string) (<-chan ResourceChange, error) {
+	var interest stringset.Set
+	if len(resources) > 0 {
+		interest = stringset.Make()
+		for _, r := range resources {
+			if _, present := m.types[r]; !present {
+				return nil, fmt.Errorf("no resource named %s", r)
+			}
+			interest.Insert(r)
+		}
+	}
+	m.lockSubscribers.Lock()
+	defer m.lockSubscribers.Unlock()
+	select {
+	case <-m.closed:
+		return nil, ErrClosed
+	default:
+	}
+	s := &subscription{
+		ch:        make(chan ResourceChange, m.subscriptionBuffer),
+		resources: interest,
+	}
+	m.subscribers[s.ch] = s
+	return s.ch, nil
+}
+
+// Unsubscribe stops sending events over a channel returned by Subscribe and closes it.
+func (m *Executer) Unsubscribe(ch <-chan ResourceChange) error {
+	m.lockSubscribers.Lock()
+	defer m.lockSubscribers.Unlock()
+	s, present := m.subscribers[ch]
+	if !present {
+		return errors.New("unknown subscription")
+	}
+	delete(m.subscribers, ch)
+	close(s.ch)
+	return nil
+}
+
+// publishChanges sends to each of the interested subscribers its own copy of the given changes.
+func (m *Executer) publishChanges(changes []ResourceChange) {
+	if len(changes) == 0 {
+		return
+	}
+	m.lockSubscribers.Lock()
+	defer m.lockSubscribers.Unlock()
+	for _, s := range m.subscribers {
+		for _, c := range changes {
+			if s.resources == nil || s.resources.Has(c.Resource) {
+				s.send(c.copy())
+			}
+		}
+	}
+}
+
+// closeSubscriptions closes the channels of all the subscribers.
+func (m *Executer) closeSubscriptions() {
+	m.lockSubscribers.Lock()
+	defer m.lockSubscribers.Unlock()
+	for ch, s := range m.subscribers {
+		delete(m.subscribers, ch)
+		close(s.ch)
+	}
+}
+
+// send delivers c without blocking, discarding the oldest buffered events if needed.
+func (s *subscription) send(c ResourceChange) {
+	sendDropOldest(s.ch, c, func(c *ResourceChange) { c.Dropped++ })
+}
+
+// sendDropOldest sends v over ch without blocking: while ch is full its oldest value is discarded
+// and, if dropped is not nil, it is called on v before trying again. ch must not be closed concurrently.
+func sendDropOldest[T any](ch chan T, v T, dropped func(*T)) {
+	for {
+		select {
+		case ch <- v:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+			if dropped != nil {
+				dropped(&v)
+			}
+		default:
+		}
+	}
+}
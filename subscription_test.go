@@ -0,0 +1,172 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+// receiveChange returns the next event sent over ch, it fails t after a timeout.
+func receiveChange(t *testing.T, ch <-chan ResourceChange) ResourceChange {
+	select {
+	case c := <-ch:
+		return c
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for a change")
+	}
+	return ResourceChange{}
+}
+
+func TestSubscribe(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	memory.Integer["bar"] = 0
+	memory.Integer["baz"] = 0
+	rules := []string{
+		"rule local on foo for true do bar = foo * 2",
+		"rule global on bar for all true do ext.baz = this.bar",
+	}
+	e, err := NewExecuter(memory, rules, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	if _, err := e.Subscribe("qux"); err == nil {
+		t.Error("subscribing to an absent resource should be an error")
+	}
+	all, err := e.Subscribe()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	baz, err := e.Subscribe("baz")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input("foo = 3")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for !e.DoIfStable(func() {}) {
+		e.Exec()
+	}
+	expected := []ResourceChange{
+		{Resource: "foo", Old: int64(0), New: int64(3), Source: SourceInput},
+		{Resource: "bar", Old: int64(0), New: int64(6), Source: SourceLocal, Rule: "local"},
+		{Resource: "baz", Old: int64(0), New: int64(6), Source: SourceRemote, Rule: "global"},
+	}
+	for i, exp := range expected {
+		if c := receiveChange(t, all); c != exp {
+			t.Errorf("change #%d should be %v, got %v", i+1, exp, c)
+		}
+	}
+	if c := receiveChange(t, baz); c != expected[2] {
+		t.Errorf("change should be %v, got %v", expected[2], c)
+	}
+	err = e.Unsubscribe(baz)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if _, ok := <-baz; ok {
+		t.Error("channel should be closed by Unsubscribe")
+	}
+	if e.Unsubscribe(baz) == nil {
+		t.Error("unsubscribing twice should be an error")
+	}
+	err = e.Close()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if _, ok := <-all; ok {
+		t.Error("channel should be closed by Close")
+	}
+	if _, err := e.Subscribe(); err != ErrClosed {
+		t.Error("Subscribe should return ErrClosed after Close")
+	}
+}
+
+func TestSubscriptionOverflow(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["foo"] = 0
+	e, err := NewExecuterAdvanced(memory, nil, MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{SubscriptionBuffer: 3})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticInput(*Optimistic)
+	ch, err := e.Subscribe("foo")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 1; i <= 5; i++ {
+		e.addActions(fmt.Sprintf("foo = %d", i))
+	}
+	for !e.DoIfStable(func() {}) {
+		e.Exec()
+	}
+	if len(ch) != 3 {
+		t.Fatal("channel should have 3 buffered events, got", len(ch))
+	}
+	expected := []ResourceChange{
+		{Resource: "foo", Old: int64(2), New: int64(3)},
+		{Resource: "foo", Old: int64(3), New: int64(4), Dropped: 1},
+		{Resource: "foo", Old: int64(4), New: int64(5), Dropped: 1},
+	}
+	for i, exp := range expected {
+		if c := receiveChange(t, ch); c != exp {
+			t.Errorf("change #%d should be %v, got %v", i+1, exp, c)
+		}
+	}
+}
+
+func TestChangesAreCopies(t *testing.T) {
+	mem := memory.MakeResources()
+	mem.List["jobs"] = []any{}
+	e, err := NewExecuter(mem, nil, MakeMockAgent(), config.TestsLogConfig, "Len(jobs) < 2")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	first, err := e.Subscribe("jobs")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := e.Subscribe()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = e.Input(`jobs = Append(jobs, "build")`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c := receiveChange(t, first)
+	c.New.([]any)[0] = "changed"
+	if c := receiveChange(t, second); !reflect.DeepEqual(c.New, []any{"build"}) {
+		t.Error("the subscribers should receive their own values, got", c.New)
+	}
+	if mem, _ := e.TakeState(); !reflect.DeepEqual(mem.List["jobs"], []any{"build"}) {
+		t.Error("modifying a change should not affect the state, got", mem.List["jobs"])
+	}
+	if e.Input(`jobs = Append(jobs, "test")`) == nil {
+		t.Fatal("the input violating the invariant should be rejected")
+	}
+	var v InvariantViolation
+	select {
+	case v = <-e.Violations():
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for a violation")
+	}
+	v.Before["jobs"].([]any)[0] = "changed"
+	v.After["jobs"].([]any)[0] = "changed"
+	if mem, _ := e.TakeState(); !reflect.DeepEqual(mem.List["jobs"], []any{"build"}) {
+		t.Error("modifying a violation should not affect the state, got", mem.List["jobs"])
+	}
+}
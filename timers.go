@@ -0,0 +1,129 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/stringset"
+	"github.com/abu-lang/goabu/tracing"
+
+	"go.uber.org/zap"
+)
+
+// periodicEvent is the key of m.ruleLibrary indexing the periodic rules, which have no events.
+const periodicEvent = ""
+
+// ruleTimer is the scheduled activation of a periodic or a delayed rule.
+type ruleTimer struct {
+	timer Timer
+}
+
+// startTimers schedules the activation of the periodic rules of m, from then on the periodic rules
+// added to m are scheduled as well and the delayed rules are scheduled when they are activated.
+func (m *Executer) startTimers() {
+	m.lockRules.Lock()
+	defer m.lockRules.Unlock()
+	m.lockTimers.Lock()
+	m.timersActive = true
+	m.lockTimers.Unlock()
+	for _, rule := range m.ruleLibrary[periodicEvent] {
+		m.schedulePeriodic(rule)
+	}
+}
+
+// stopTimers cancels every scheduled activation of m and waits for the ones in progress to terminate.
+func (m *Executer) stopTimers() {
+	m.lockTimers.Lock()
+	m.timersActive = false
+	for name, rt := range m.timers {
+		rt.timer.Stop()
+		delete(m.timers, name)
+	}
+	m.lockTimers.Unlock()
+	m.firing.Wait()
+}
+
+// schedulePeriodic schedules the next activation of the periodic rule.
+// It should be called while holding m.lockRules.
+func (m *Executer) schedulePeriodic(rule *ecarule.Rule) {
+	m.schedule(rule.Name, rule.Period, true, Update{})
+}
+
+// delay (re)schedules the activation of the delayed rule once its delay has elapsed, the activation
+// is caused by cause. It should be called while holding m.lockRules.
+func (m *Executer) delay(rule *ecarule.Rule, cause Update) {
+	m.schedule(rule.Name, rule.Delay, false, cause)
+}
+
+// schedule replaces the scheduled activation of the rule with the given name, if any, with its activation
+// after d. If periodic is true, the next activation is scheduled when the activation takes place.
+// It does nothing if the timers of m are not active.
+func (m *Executer) schedule(name string, d time.Duration, periodic bool, cause Update) {
+	m.lockTimers.Lock()
+	defer m.lockTimers.Unlock()
+	if !m.timersActive {
+		return
+	}
+	if rt, ok := m.timers[name]; ok {
+		rt.timer.Stop()
+	}
+	rt := &ruleTimer{}
+	rt.timer = m.clock.AfterFunc(d, func() {
+		m.lockTimers.Lock()
+		if !m.timersActive || m.timers[name] != rt {
+			// stopped or rescheduled
+			m.lockTimers.Unlock()
+			return
+		}
+		delete(m.timers, name)
+		m.firing.Add(1)
+		m.lockTimers.Unlock()
+		defer m.firing.Done()
+		if periodic {
+			m.lockRules.Lock()
+			if rule, ok := m.ruleLibrary[periodicEvent][name]; ok {
+				m.schedulePeriodic(rule)
+			}
+			m.lockRules.Unlock()
+		}
+		m.fireTimer(name, cause)
+	})
+	m.timers[name] = rt
+}
+
+// unschedule cancels the scheduled activation of the rule with the given name, if any.
+func (m *Executer) unschedule(name string) {
+	m.lockTimers.Lock()
+	defer m.lockTimers.Unlock()
+	if rt, ok := m.timers[name]; ok {
+		rt.timer.Stop()
+		delete(m.timers, name)
+	}
+}
+
+// fireTimer activates the rule with the given name, if it is still enabled, as if the activation was
+// caused by cause.
+func (m *Executer) fireTimer(name string, cause Update) {
+	m.requestWrite(m.HasOptimisticExec())
+	defer m.coordinator.closeWrite()
+	m.coordinator.fixWorkingSetWrite(stringset.Make())
+	m.lockMemory.Lock()
+	m.lockRules.Lock()
+	rules := ecarule.MakeRuleDict()
+	for _, d := range m.ruleLibrary {
+		if rule, ok := d[name]; ok && !m.disabledRules.Has(name) {
+			rules.Insert(rule)
+			break
+		}
+	}
+	m.lockRules.Unlock()
+	m.logger.Debug(fmt.Sprintf("Timer of rule %s expired", name), zap.String("act", "timer"), zap.String("obj", name))
+	span := m.tracer.Start("timer", tracing.SpanKindInternal, cause.Trace, tracing.String("goabu.rule", name))
+	defer span.End()
+	cause.Trace = span.Context()
+	m.activate(rules, cause)
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func TestTimers(t *testing.T) {
+	memory := memory.MakeResources()
+	memory.Integer["beats"] = 0
+	memory.Bool["door"] = false
+	memory.Bool["alarm"] = false
+	clock := NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	rules := []string{
+		"rule Heartbeat every 5s for true do beats = beats + 1",
+		"rule T after 30s on door for door do alarm = true",
+	}
+	e, err := NewExecuterAdvanced(memory, rules, MakeMockAgent(), config.TestsLogConfig, &ExecuterConfig{Clock: clock})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	// the updates are evaluated upon activation, hence they are executed after every second
+	advance := func(d time.Duration) {
+		for ; d > 0; d -= time.Second {
+			clock.Advance(time.Second)
+			for {
+				_, pool := e.TakeState()
+				if len(pool) == 0 {
+					break
+				}
+				e.Exec()
+			}
+		}
+	}
+	advance(12 * time.Second)
+	mem, _ := e.TakeState()
+	if mem.Integer["beats"] != 2 {
+		t.Error("the periodic rule should have been activated twice, got", mem.Integer["beats"])
+	}
+
+	// the delayed rule is activated 30s after the last change of door
+	err = e.Input("door = true")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	advance(20 * time.Second)
+	e.Input("door = false")
+	e.Input("door = true")
+	advance(20 * time.Second)
+	mem, _ = e.TakeState()
+	if mem.Bool["alarm"] {
+		t.Error("the delayed rule should be rescheduled by the changes of door")
+	}
+	advance(10 * time.Second)
+	mem, _ = e.TakeState()
+	if !mem.Bool["alarm"] || mem.Integer["beats"] != 12 {
+		t.Error("unexpected state:", mem)
+	}
+
+	if err = e.SetRuleEnabled("Heartbeat", false); err != nil {
+		t.Fatal(err.Error())
+	}
+	advance(10 * time.Second)
+	if err = e.ReplaceRule("rule Heartbeat every 1s for true do beats = 0"); err != nil {
+		t.Fatal(err.Error())
+	}
+	advance(time.Second)
+	mem, _ = e.TakeState()
+	if mem.Integer["beats"] != 12 {
+		t.Error("disabled rules should not be activated, got", mem.Integer["beats"])
+	}
+	if err = e.SetRuleEnabled("Heartbeat", true); err != nil {
+		t.Fatal(err.Error())
+	}
+	advance(time.Second)
+	mem, _ = e.TakeState()
+	if mem.Integer["beats"] != 0 {
+		t.Error("the replaced periodic rule should be activated, got", mem.Integer["beats"])
+	}
+	// the updates are enqueued at the time of the clock
+	clock.Advance(time.Second)
+	if _, pool := e.TakeState(); len(pool) != 1 || !pool[0].Enqueued.Equal(clock.Now()) {
+		t.Error("unexpected enqueue time:", pool)
+	}
+	e.Exec()
+	if err = e.RemoveRule("Heartbeat"); err != nil {
+		t.Fatal(err.Error())
+	}
+	e.Input("beats = 5")
+	advance(time.Minute)
+	mem, _ = e.TakeState()
+	if mem.Integer["beats"] != 5 {
+		t.Error("removed rules should not be activated, got", mem.Integer["beats"])
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ScopeName is the name of the instrumentation scope of the exported spans.
+const ScopeName = "github.com/abu-lang/goabu"
+
+// JSONExporter is an Exporter writing each span on a line as an OTLP/JSON ExportTraceServiceRequest,
+// the format of the file exporter of the OpenTelemetry Collector.
+type JSONExporter struct {
+	w    io.Writer
+	lock sync.Mutex
+}
+
+// NewJSONExporter returns a JSONExporter writing to w.
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{w: w}
+}
+
+// NewFileExporter returns a JSONExporter appending to the named file, which is created if it does not exist.
+// The file is closed by calling Close.
+func NewFileExporter(path string) (*JSONExporter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONExporter(f), nil
+}
+
+// Export writes s followed by a newline.
+func (e *JSONExporter) Export(s SpanData) error {
+	b, err := json.Marshal(encodeRequest(s))
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = e.w.Write(b)
+	return err
+}
+
+// Close closes the underlying writer if it is an [io.Closer].
+func (e *JSONExporter) Close() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if c, ok := e.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ReadSpans reads the spans written by a JSONExporter, or more generally the spans contained
+// in a stream of OTLP/JSON ExportTraceServiceRequests separated by newlines.
+func ReadSpans(r io.Reader) ([]SpanData, error) {
+	var res []SpanData
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var req otlpRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return res, fmt.Errorf("line %d: %w", line, err)
+		}
+		spans, err := req.decode()
+		if err != nil {
+			return res, fmt.Errorf("line %d: %w", line, err)
+		}
+		res = append(res, spans...)
+	}
+	return res, scanner.Err()
+}
+
+// The following types mirror the OTLP/JSON encoding of ExportTraceServiceRequest.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           TraceID         `json:"traceId"`
+	SpanID            SpanID          `json:"spanId"`
+	ParentSpanID      *SpanID         `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+func encodeRequest(s SpanData) otlpRequest {
+	span := otlpSpan{
+		TraceID:           s.TraceID,
+		SpanID:            s.SpanID,
+		Name:              s.Name,
+		Kind:              s.Kind,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Attributes:        encodeAttributes(s.Attributes),
+		Status:            otlpStatus{Code: s.Status, Message: s.StatusMessage},
+	}
+	if s.Parent.IsValid() {
+		parent := s.Parent
+		span.ParentSpanID = &parent
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttributes([]Attribute{String("service.name", s.Service)})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: ScopeName}, Spans: []otlpSpan{span}}},
+	}}}
+}
+
+func encodeAttributes(attrs []Attribute) []otlpAttribute {
+	res := make([]otlpAttribute, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch value := a.Value.(type) {
+		case string:
+			v.StringValue = &value
+		case int64:
+			i := strconv.FormatInt(value, 10)
+			v.IntValue = &i
+		case float64:
+			v.DoubleValue = &value
+		case bool:
+			v.BoolValue = &value
+		default:
+			s := fmt.Sprint(value)
+			v.StringValue = &s
+		}
+		res = append(res, otlpAttribute{Key: a.Key, Value: v})
+	}
+	return res
+}
+
+func (r otlpRequest) decode() ([]SpanData, error) {
+	var res []SpanData
+	for _, rs := range r.ResourceSpans {
+		resource, err := decodeAttributes(rs.Resource.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		service := ""
+		for _, a := range resource {
+			if a.Key == "service.name" {
+				service, _ = a.Value.(string)
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				data := SpanData{
+					SpanContext:   SpanContext{TraceID: span.TraceID, SpanID: span.SpanID},
+					Name:          span.Name,
+					Kind:          span.Kind,
+					Status:        span.Status.Code,
+					StatusMessage: span.Status.Message,
+					Service:       service,
+				}
+				if span.ParentSpanID != nil {
+					data.Parent = *span.ParentSpanID
+				}
+				if data.Start, err = decodeTime(span.StartTimeUnixNano); err != nil {
+					return nil, err
+				}
+				if data.End, err = decodeTime(span.EndTimeUnixNano); err != nil {
+					return nil, err
+				}
+				if data.Attributes, err = decodeAttributes(span.Attributes); err != nil {
+					return nil, err
+				}
+				res = append(res, data)
+			}
+		}
+	}
+	return res, nil
+}
+
+func decodeTime(nanos string) (time.Time, error) {
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", nanos)
+	}
+	return time.Unix(0, n), nil
+}
+
+func decodeAttributes(attrs []otlpAttribute) ([]Attribute, error) {
+	res := make([]Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		switch {
+		case a.Value.StringValue != nil:
+			res = append(res, String(a.Key, *a.Value.StringValue))
+		case a.Value.IntValue != nil:
+			i, err := strconv.ParseInt(*a.Value.IntValue, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of attribute %s", a.Key)
+			}
+			res = append(res, Attribute{Key: a.Key, Value: i})
+		case a.Value.DoubleValue != nil:
+			res = append(res, Float(a.Key, *a.Value.DoubleValue))
+		case a.Value.BoolValue != nil:
+			res = append(res, Bool(a.Key, *a.Value.BoolValue))
+		default:
+			return nil, fmt.Errorf("unsupported value of attribute %s", a.Key)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing records the causal chains of the operations performed by GoAbU nodes as spans
+// following the OpenTelemetry data model, along with exporters writing them in the OTLP/JSON encoding.
+package tracing
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace, that is the set of spans caused by the same operation, also across nodes.
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid reports whether t is not the zero TraceID.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns the hexadecimal encoding of t.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// MarshalText implements [encoding.TextMarshaler] by means of the hexadecimal encoding of t.
+func (t TraceID) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (t *TraceID) UnmarshalText(text []byte) error {
+	return decodeID(t[:], text)
+}
+
+// IsValid reports whether s is not the zero SpanID.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns the hexadecimal encoding of s.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// MarshalText implements [encoding.TextMarshaler] by means of the hexadecimal encoding of s.
+func (s SpanID) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (s *SpanID) UnmarshalText(text []byte) error {
+	return decodeID(s[:], text)
+}
+
+func decodeID(dst, text []byte) error {
+	if len(text) == 0 {
+		clear(dst)
+		return nil
+	}
+	if hex.DecodedLen(len(text)) != len(dst) {
+		return fmt.Errorf("invalid identifier %q: expecting %d hexadecimal digits", text, 2*len(dst))
+	}
+	_, err := hex.Decode(dst, text)
+	return err
+}
+
+// SpanContext identifies a span, it is propagated along with the operations caused by the span
+// in order to make them its children.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid reports whether c identifies a span.
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+// SpanKind describes the relationship between a span and its parent and children, the values are the ones of OTLP.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode is the status of a span, the values are the ones of OTLP.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Attribute is a key-value pair describing a span.
+type Attribute struct {
+	Key string
+	// Value is a string, an int64, a float64 or a bool.
+	Value any
+}
+
+// String returns a string Attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer Attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Float returns a floating-point Attribute.
+func Float(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean Attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is a terminated span, as received by the exporters.
+type SpanData struct {
+	SpanContext
+	// Parent is the SpanID of the parent of the span, it is the zero SpanID for the root of a trace.
+	Parent     SpanID
+	Name       string
+	Kind       SpanKind
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Status     StatusCode
+	// StatusMessage describes the error of the span if Status == StatusError.
+	StatusMessage string
+	// Service is the name of the service, that is the node, that recorded the span.
+	Service string
+}
+
+// Exporter receives the spans terminated by a Tracer.
+// Implementations must be safe for concurrent use.
+type Exporter interface {
+	// Export exports s.
+	Export(s SpanData) error
+}
+
+// Tracer creates the spans of a service.
+type Tracer struct {
+	service  string
+	exporter Exporter
+	// onError is called with the errors returned by exporter.
+	onError func(error)
+}
+
+// NewTracer returns a Tracer for the named service that passes the terminated spans to e.
+// If e is nil then the spans are only used for propagating their identifiers and are not exported.
+func NewTracer(service string, e Exporter) *Tracer {
+	return &Tracer{service: service, exporter: e}
+}
+
+// OnError sets the function called with the errors returned by the Exporter of t, by default they are ignored.
+// It should be called before using t.
+func (t *Tracer) OnError(f func(error)) {
+	t.onError = f
+}
+
+// Start starts a span with the given name and kind. If parent is valid then the span is a child of parent,
+// otherwise the span is the root of a new trace.
+func (t *Tracer) Start(name string, kind SpanKind, parent SpanContext, attrs ...Attribute) *Span {
+	s := &Span{tracer: t}
+	s.data.Name = name
+	s.data.Kind = kind
+	s.data.Start = time.Now()
+	s.data.Service = t.service
+	s.data.Attributes = append([]Attribute(nil), attrs...)
+	if parent.IsValid() {
+		s.data.TraceID = parent.TraceID
+		s.data.Parent = parent.SpanID
+	} else {
+		for !s.data.TraceID.IsValid() {
+			binary.BigEndian.PutUint64(s.data.TraceID[:8], rand.Uint64())
+			binary.BigEndian.PutUint64(s.data.TraceID[8:], rand.Uint64())
+		}
+	}
+	for !s.data.SpanID.IsValid() {
+		binary.BigEndian.PutUint64(s.data.SpanID[:], rand.Uint64())
+	}
+	return s
+}
+
+// Span is an operation in progress, it is exported once ended. Its methods are safe for concurrent use.
+type Span struct {
+	tracer *Tracer
+	data   SpanData
+	ended  bool
+	lock   sync.Mutex
+}
+
+// Context returns the SpanContext identifying s.
+func (s *Span) Context() SpanContext {
+	return s.data.SpanContext
+}
+
+// SetAttributes adds attrs to the attributes of s.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	s.lock.Lock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+	s.lock.Unlock()
+}
+
+// SetError marks s as failed because of err, nothing is done if err is nil.
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.lock.Lock()
+	s.data.Status = StatusError
+	s.data.StatusMessage = err.Error()
+	s.lock.Unlock()
+}
+
+// End terminates s and passes it to the Exporter of its Tracer, the following calls do nothing.
+func (s *Span) End() {
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.lock.Unlock()
+	if s.tracer.exporter == nil {
+		return
+	}
+	if err := s.tracer.exporter.Export(data); err != nil && s.tracer.onError != nil {
+		s.tracer.onError(err)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONExporter(t *testing.T) {
+	var b bytes.Buffer
+	tracer := NewTracer("node", NewJSONExporter(&b))
+	root := tracer.Start("input", SpanKindInternal, SpanContext{}, String("actions", "level = 12"))
+	if !root.Context().IsValid() {
+		t.Fatal("the root span should have a valid context")
+	}
+	child := tracer.Start("exec", SpanKindInternal, root.Context())
+	child.SetAttributes(Int("task", 1), Bool("remote", false), Float("ratio", 0.5))
+	child.SetError(errors.New("boom"))
+	child.End()
+	child.End()
+	root.End()
+	if child.Context().TraceID != root.Context().TraceID {
+		t.Error("the child should belong to the trace of its parent")
+	}
+	other := NewTracer("node", nil).Start("input", SpanKindInternal, SpanContext{})
+	if other.Context().TraceID == root.Context().TraceID {
+		t.Error("root spans should start new traces")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got:\n%s", b.String())
+	}
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &raw); err != nil {
+		t.Fatal(err.Error())
+	}
+	span := raw["resourceSpans"].([]any)[0].(map[string]any)["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	if span["traceId"] != root.Context().TraceID.String() || span["spanId"] != root.Context().SpanID.String() {
+		t.Errorf("unexpected identifiers in %s", lines[1])
+	}
+	if _, present := span["parentSpanId"]; present {
+		t.Errorf("a root span should have no parent: %s", lines[1])
+	}
+
+	spans, err := ReadSpans(&b)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	exec := spans[0]
+	if exec.Name != "exec" || exec.Parent != root.Context().SpanID || exec.SpanContext != child.Context() {
+		t.Errorf("unexpected span: %+v", exec)
+	}
+	if exec.Service != "node" || exec.Status != StatusError || exec.StatusMessage != "boom" {
+		t.Errorf("unexpected span: %+v", exec)
+	}
+	attrs := []Attribute{{"task", int64(1)}, {"remote", false}, {"ratio", 0.5}}
+	if !reflect.DeepEqual(exec.Attributes, attrs) {
+		t.Errorf("unexpected attributes: %v", exec.Attributes)
+	}
+	if exec.End.Before(exec.Start) {
+		t.Error("the span should end after its start")
+	}
+	if spans[1].Parent.IsValid() || !reflect.DeepEqual(spans[1].Attributes, []Attribute{String("actions", "level = 12")}) {
+		t.Errorf("unexpected span: %+v", spans[1])
+	}
+
+	if _, err = ReadSpans(strings.NewReader(`{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"xyz"}]}]}]}`)); err == nil {
+		t.Error("reading an invalid identifier should fail")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abu-lang/goabu/memory"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"go.uber.org/zap"
+)
+
+// Set sets the resource with the given name to value as an input from the environment, like Input
+// does, without parsing any action. The type of value must be the one of the resource: bool for Bool
+// resources, any integer type for Integer resources, any floating point type for Float resources,
+// string for Text resources, [time.Time] for Time resources, a slice for List resources, a map with
+// string keys for Map resources and any non-nil value for Other resources. The elements of the slices
+// and the values of the maps must be booleans, integers, floating point numbers, strings or [time.Time]
+// values, they are converted like the values of the other resources.
+func (m *Executer) Set(name string, value any) error {
+	return m.SetMany(map[string]any{name: value})
+}
+
+// SetMany atomically sets each of the resources named by the keys of values to the corresponding
+// value as a single input from the environment (see Set).
+func (m *Executer) SetMany(values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+	_, err := m.input("", values)
+	return err
+}
+
+// typedAssignments returns the assignments setting the resources named by the keys of values to the
+// corresponding values (see Set), sorted by resource name.
+func (m *Executer) typedAssignments(values map[string]any) ([]Assignment, error) {
+	var res []Assignment
+	for name, value := range values {
+		v, err := m.typedValue(name, value)
+		if err != nil {
+			m.logger.Error(err.Error(), zap.String("act", "set"), zap.String("obj", name))
+			return nil, err
+		}
+		res = append(res, Assignment{
+			Resource: name,
+			variable: m.variables[name],
+			Value:    v,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Resource < res[j].Resource
+	})
+	return res, nil
+}
+
+// typedValue returns the value to be assigned to the resource name if value has the type
+// of the resource, otherwise it returns an error.
+func (m *Executer) typedValue(name string, value any) (reflect.Value, error) {
+	typ, present := m.types[name]
+	if !present {
+		return reflect.Value{}, fmt.Errorf("no resource named %s", name)
+	}
+	v := reflect.ValueOf(value)
+	invalid := fmt.Errorf("cannot assign a value of type %T to %s resource %s", value, typ, name)
+	switch typ {
+	case "Bool":
+		if _, ok := value.(bool); !ok {
+			return reflect.Value{}, invalid
+		}
+	case "Integer":
+		switch {
+		case v.CanInt():
+			v = reflect.ValueOf(v.Int())
+		case v.CanUint() && v.Uint() <= math.MaxInt64:
+			v = reflect.ValueOf(int64(v.Uint()))
+		default:
+			return reflect.Value{}, invalid
+		}
+	case "Float":
+		if !v.CanFloat() {
+			return reflect.Value{}, invalid
+		}
+		v = reflect.ValueOf(v.Float())
+	case "Text":
+		if _, ok := value.(string); !ok {
+			return reflect.Value{}, invalid
+		}
+	case "Time":
+		if _, ok := value.(time.Time); !ok {
+			return reflect.Value{}, invalid
+		}
+	case "List":
+		list, err := listValue(v)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: %v", invalid, err)
+		}
+		v = reflect.ValueOf(list)
+	case "Map":
+		m, err := mapValue(v)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: %v", invalid, err)
+		}
+		v = reflect.ValueOf(m)
+	default:
+		if value == nil {
+			return reflect.Value{}, invalid
+		}
+		v = reflect.ValueOf(memory.DeepCopy(value))
+	}
+	return v, nil
+}
+
+// resourceVariables returns the variables encoding the resources of m indexed by resource name.
+func (m *Executer) resourceVariables() (map[string]*ast.Variable, error) {
+	res := make(map[string]*ast.Variable)
+	names := m.memory.ResourceNames()
+	if len(names) == 0 {
+		return res, nil
+	}
+	actions := make([]string, 0, len(names))
+	for _, r := range names {
+		actions = append(actions, fmt.Sprintf("%s = %s", r, r))
+	}
+	parsed, err := m.parseActions(strings.Join(actions, ", "))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range parsed {
+		res[a.Resource] = a.Assignment.Variable
+	}
+	return res, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Massimo Comuzzo, Michele Pasqua and Marino Miculan
+// SPDX-License-Identifier: Apache-2.0
+
+package goabu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abu-lang/goabu/config"
+	"github.com/abu-lang/goabu/memory"
+)
+
+func typedResources() memory.Resources {
+	res := memory.MakeResources()
+	res.Bool["b"] = false
+	res.Integer["i"] = 0
+	res.Float["f"] = 0
+	res.Text["s"] = ""
+	res.Time["t"] = time.Unix(0, 0)
+	res.Other["o"] = 0
+	return res
+}
+
+func TestSet(t *testing.T) {
+	e, err := NewExecuter(typedResources(), []string{"rule double on i for true do f = i * 2.0"},
+		MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	e.SetOptimisticExec(*Optimistic)
+	e.SetOptimisticInput(*Optimistic)
+	defer e.Close()
+	date := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value any
+		valid bool
+	}{
+		{"b", true, true},
+		{"b", 1, false},
+		{"i", 3, true},
+		{"i", uint8(4), true},
+		{"i", uint64(1 << 63), false},
+		{"i", 1.5, false},
+		{"f", float32(2.5), true},
+		{"f", 2, false},
+		{"s", "gopher", true},
+		{"s", []byte("gopher"), false},
+		{"t", date, true},
+		{"t", "2021-05-01", false},
+		{"o", []int{1, 2}, true},
+		{"o", nil, false},
+		{"absent", 0, false},
+	}
+	for _, test := range tests {
+		if err := e.Set(test.name, test.value); (err == nil) != test.valid {
+			t.Errorf("Set(%q, %v) returned %v", test.name, test.value, err)
+		}
+	}
+	mem, pool := e.TakeState()
+	if !mem.Bool["b"] || mem.Integer["i"] != 4 || mem.Float["f"] != 2.5 || mem.Text["s"] != "gopher" ||
+		!mem.Time["t"].Equal(date) || len(mem.Other["o"].([]int)) != 2 {
+		t.Error("unexpected state:", mem)
+	}
+	if len(pool) != 2 || pool[0].Rule != "double" {
+		t.Error("each input on i should trigger the rule, got", pool)
+	}
+	err = e.SetMany(map[string]any{"i": 10, "s": 1})
+	if err == nil {
+		t.Error("SetMany should fail if a value is invalid")
+	}
+	if mem, _ = e.TakeState(); mem.Integer["i"] != 4 {
+		t.Error("no resource should be modified by an invalid SetMany, got", mem)
+	}
+	err = e.SetMany(map[string]any{"i": 10, "s": "octocat"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if mem, _ = e.TakeState(); mem.Integer["i"] != 10 || mem.Text["s"] != "octocat" {
+		t.Error("unexpected state:", mem)
+	}
+}
+
+// eventResources are Resources providing the strings and the events of two channels as inputs.
+type eventResources struct {
+	memory.Resources
+	inputs chan string
+	events chan memory.InputEvent
+}
+
+func (r eventResources) Inputs() <-chan string {
+	return r.inputs
+}
+
+func (r eventResources) InputEvents() <-chan memory.InputEvent {
+	return r.events
+}
+
+func (r eventResources) Copy() memory.ResourceController {
+	return eventResources{Resources: r.Resources.Copy().GetResources(), inputs: r.inputs, events: r.events}
+}
+
+func TestInputEvents(t *testing.T) {
+	mem := eventResources{Resources: typedResources(), events: make(chan memory.InputEvent)}
+	e, err := NewExecuter(mem, nil, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer e.Close()
+	changes, err := e.Subscribe()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	mem.events <- memory.InputEvent{Resource: "i", Value: 7}
+	mem.events <- memory.InputEvent{Resource: "b", Value: true}
+	for i := 0; i < 2; i++ {
+		if c := receiveChange(t, changes); c.Source != SourceInput {
+			t.Error("unexpected change:", c)
+		}
+	}
+	state, _ := e.TakeState()
+	if state.Integer["i"] != 7 || !state.Bool["b"] {
+		t.Error("unexpected state:", state)
+	}
+}
+
+func TestMixedInputs(t *testing.T) {
+	res := typedResources()
+	res.Integer["n"] = 0
+	mem := eventResources{Resources: res, inputs: make(chan string), events: make(chan memory.InputEvent)}
+	e, err := NewExecuter(mem, []string{"rule count on i b for true do n = n + 1"}, MakeMockAgent(), config.TestsLogConfig)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer e.Close()
+	changes, err := e.Subscribe("i", "b")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	mem.inputs <- "i = 7,"
+	mem.events <- memory.InputEvent{Resource: "b", Value: true}
+	for i := 0; i < 2; i++ {
+		receiveChange(t, changes)
+	}
+	// the string and the event are applied by a single input activating the rule once
+	state, pool := e.TakeState()
+	if state.Integer["i"] != 7 || !state.Bool["b"] || len(pool) != 1 {
+		t.Error("unexpected state:", state, pool)
+	}
+}
@@ -6,15 +6,51 @@ package goabu
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/abu-lang/goabu/ecarule"
+	"github.com/abu-lang/goabu/memory"
+	"github.com/abu-lang/goabu/tracing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/hyperjumptech/grule-rule-engine/ast"
 )
 
-type Update []Assignment
+// Update groups a list of Assignments that are to be performed atomically.
+type Update struct {
+	// Assignments contains the assignments performed by the update.
+	Assignments []Assignment
+	// Rule is the name of the rule that produced the update, it is empty for the updates produced by inputs.
+	Rule string
+	// Task is the index of the task of Rule that produced the update: among the local tasks of the rule
+	// for the updates with Source == SourceLocal, among its global tasks for the ones with Source == SourceRemote.
+	Task int
+	// Salience is the salience of the rule that produced the update, updates with higher
+	// salience are executed first. It is 0 for the updates produced by inputs.
+	Salience int
+	// Source specifies where the update originated.
+	Source ChangeSource
+	// Initiator is the identifier of the agent of the node where Rule was activated. It is empty
+	// if the agent has no identifier (see [IdentifiedAgent]).
+	Initiator string
+	// Transaction identifies the transaction that delivered the update, it is empty unless
+	// Source == SourceRemote.
+	Transaction string
+	// Enqueued is the time when the update was added to the pool.
+	Enqueued time.Time
+	// Depth is the length of the causal chain of updates that led to the update, also across nodes:
+	// it is 0 for the updates produced by inputs and one more than the depth of the update that
+	// activated Rule otherwise.
+	Depth int
+	// Trace identifies the span that caused the update: the span of the input, of the execution of the
+	// update that activated Rule, of the timer that activated Rule or of the reception of the remote tasks.
+	// The updates caused by the same input share its TraceID, also across nodes.
+	Trace tracing.SpanContext
+	// id identifies the update among the ones added to the pool, it is 0 if the update
+	// was not added to the pool by the update receiver.
+	id uint64
+}
 
 type Assignment struct {
 	Resource string
@@ -26,8 +62,34 @@ func (a Assignment) String() string {
 	return fmt.Sprintf("(%s,%v)", a.Resource, a.Value)
 }
 
+func (u Update) String() string {
+	return fmt.Sprint(u.Assignments)
+}
+
+// resources returns the names of the resources assigned by u.
+func (u Update) resources() []string {
+	res := make([]string, 0, len(u.Assignments))
+	for _, a := range u.Assignments {
+		res = append(res, a.Resource)
+	}
+	return res
+}
+
+// copy returns a copy of u that does not share its Assignments, nor their values, with u.
+func (u Update) copy() Update {
+	res := u
+	res.Assignments = make([]Assignment, len(u.Assignments))
+	copy(res.Assignments, u.Assignments)
+	for i, a := range res.Assignments {
+		if a.Value.IsValid() && a.Value.CanInterface() {
+			res.Assignments[i].Value = reflect.ValueOf(memory.DeepCopy(a.Value.Interface()))
+		}
+	}
+	return res
+}
+
 func appendNonempty(pool []Update, u Update) []Update {
-	if len(u) == 0 {
+	if len(u.Assignments) == 0 {
 		return pool
 	}
 	return append(pool, u)
@@ -42,7 +104,7 @@ func evalActions(actions []ecarule.Action, dataContext ast.IDataContext, working
 		rexpr = workingMemory.AddExpression(rexpr)
 		exprVal, err := rexpr.Evaluate(dataContext, workingMemory)
 		if err != nil {
-			return nil, err
+			return Update{}, err
 		}
 		res = append(res, Assignment{
 			Resource: action.Resource,
@@ -50,19 +112,19 @@ func evalActions(actions []ecarule.Action, dataContext ast.IDataContext, working
 			Value:    exprVal,
 		})
 	}
-	return res, nil
+	return Update{Assignments: res}, nil
 }
 
 func condEvalActions(exp *ast.Expression, actions []ecarule.Action, dataContext ast.IDataContext, workingMemory *ast.WorkingMemory) (Update, error) {
 	exp = workingMemory.AddExpression(exp)
 	val, err := exp.Evaluate(dataContext, workingMemory)
 	if err != nil {
-		return nil, err
+		return Update{}, err
 	}
 	if val.Bool() {
 		return evalActions(actions, dataContext, workingMemory)
 	}
-	return nil, nil
+	return Update{}, nil
 }
 
 //----------------------------------LOGGER------------------------------------
@@ -110,5 +172,52 @@ func (a Assignment) arrayMarshaler() zapcore.ArrayMarshaler {
 
 // arrayMarshaler returns a [zapcore.ArrayMarshaler] for encoding the receiver as an array.
 func (u Update) arrayMarshaler() zapcore.ArrayMarshaler {
-	return newArrayMarshaler(u...)
+	return newArrayMarshaler(u.Assignments...)
+}
+
+// zapOrigin constructs a [zapcore.Field] encoding the provenance of u.
+func zapOrigin(key string, u Update) zapcore.Field {
+	return zap.Object(key, u.originMarshaler())
+}
+
+// zapOrigins constructs a [zapcore.Field] encoding the provenance of each of the updates.
+func zapOrigins(key string, updates []Update) zapcore.Field {
+	return zap.Array(key, zapcore.ArrayMarshalerFunc(
+		func(enc zapcore.ArrayEncoder) error {
+			for _, u := range updates {
+				err := enc.AppendObject(u.originMarshaler())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+}
+
+// originMarshaler returns a [zapcore.ObjectMarshaler] for encoding the provenance of the receiver.
+func (u Update) originMarshaler() zapcore.ObjectMarshaler {
+	return zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("source", u.Source.String())
+		if u.Rule != "" {
+			enc.AddString("rule", u.Rule)
+			enc.AddInt("task", u.Task)
+		}
+		if u.Initiator != "" {
+			enc.AddString("initiator", u.Initiator)
+		}
+		if u.Transaction != "" {
+			enc.AddString("transaction", u.Transaction)
+		}
+		if !u.Enqueued.IsZero() {
+			enc.AddTime("enqueued", u.Enqueued)
+		}
+		if u.Depth > 0 {
+			enc.AddInt("depth", u.Depth)
+		}
+		if u.Trace.IsValid() {
+			enc.AddString("trace", u.Trace.TraceID.String())
+			enc.AddString("span", u.Trace.SpanID.String())
+		}
+		return nil
+	})
 }
@@ -10,12 +10,19 @@ import (
 	"github.com/abu-lang/goabu/ecarule"
 	"github.com/abu-lang/goabu/memory"
 	"github.com/abu-lang/goabu/stringset"
+	"github.com/abu-lang/goabu/tracing"
 )
 
 // wireTasks groups a list of [ecarule.RemoteTask] along with a list of values for their remote resources.
 type wireTasks struct {
 	memory.Resources
 	Tasks []ecarule.RemoteTask
+	// Initiator is the identifier of the agent of the sending node.
+	Initiator string
+	// Depth is the depth of the updates produced by Tasks (see Update).
+	Depth int
+	// Trace identifies the span that caused the sending of Tasks.
+	Trace tracing.SpanContext
 }
 
 // marshalWireTasks marshalls w allowing for network transfer.